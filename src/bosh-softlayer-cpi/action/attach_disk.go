@@ -14,6 +14,7 @@ type AttachDisk struct {
 	diskService    disk.Service
 	vmService      instance.Service
 	registryClient registry.Client
+	apiVersions    ApiVersions
 }
 
 func NewAttachDisk(
@@ -28,6 +29,11 @@ func NewAttachDisk(
 	}
 }
 
+func (ad AttachDisk) WithContext(context CallContext) Action {
+	ad.apiVersions = context.ApiVersions
+	return ad
+}
+
 func (ad AttachDisk) Run(vmCID VMCID, diskCID DiskCID) (interface{}, error) {
 	// Find the disk
 	_, err := ad.diskService.Find(diskCID.Int())
@@ -59,5 +65,10 @@ func (ad AttachDisk) Run(vmCID VMCID, diskCID DiskCID) (interface{}, error) {
 		return nil, bosherr.WrapErrorf(err, "Attaching disk '%s' to vm '%s'", diskCID, vmCID)
 	}
 
+	if ad.apiVersions.IsV2() {
+		// Disk hints are the persistent disk settings the agent needs to mount the iSCSI volume
+		return newAgentSettings.Disks.Persistent[diskCID.String()], nil
+	}
+
 	return nil, nil
 }
@@ -83,6 +83,20 @@ var _ = Describe("AttachDisk", func() {
 			Expect(registryClient.UpdateSettings).To(Equal(expectedAgentSettings))
 		})
 
+		It("returns disk hints when the director uses API v2", func() {
+			attachDisk = attachDisk.WithContext(CallContext{ApiVersions: NewApiVersions(2, 2)}).(AttachDisk)
+
+			diskHints, err := attachDisk.Run(vmCID, diskCID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diskHints).To(Equal(expectedAgentSettings.Disks.Persistent["25667635"]))
+		})
+
+		It("returns no disk hints when the director uses API v1", func() {
+			diskHints, err := attachDisk.Run(vmCID, diskCID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diskHints).To(BeNil())
+		})
+
 		It("returns an error if diskService find call returns an error", func() {
 			diskService.FindReturns(
 				&datatypes.Network_Storage{},
@@ -0,0 +1,44 @@
+package action
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	"bosh-softlayer-cpi/api"
+	instance "bosh-softlayer-cpi/softlayer/virtual_guest_service"
+)
+
+// VMResources are the resources requested through vm_resources in a deployment manifest.
+type VMResources struct {
+	Cpu               int `json:"cpu"`
+	Ram               int `json:"ram"`
+	EphemeralDiskSize int `json:"ephemeral_disk_size"`
+}
+
+type CalculateVMCloudProperties struct {
+	vmService instance.Service
+}
+
+func NewCalculateVMCloudProperties(
+	vmService instance.Service,
+) CalculateVMCloudProperties {
+	return CalculateVMCloudProperties{
+		vmService: vmService,
+	}
+}
+
+func (cp CalculateVMCloudProperties) Run(vmResources VMResources) (VMCloudProperties, error) {
+	resources, err := cp.vmService.CalculateResources(vmResources.Cpu, vmResources.Ram, vmResources.EphemeralDiskSize)
+	if err != nil {
+		if _, ok := err.(api.CloudError); ok {
+			return VMCloudProperties{}, err
+		}
+		return VMCloudProperties{}, bosherr.WrapErrorf(err, "Calculating vm cloud properties for '%+v'", vmResources)
+	}
+
+	return VMCloudProperties{
+		FlavorKeyName:     resources.FlavorKeyName,
+		Cpu:               resources.Cpu,
+		Memory:            resources.Memory,
+		EphemeralDiskSize: resources.EphemeralDiskSize,
+	}, nil
+}
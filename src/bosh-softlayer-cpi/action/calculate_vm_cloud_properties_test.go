@@ -0,0 +1,88 @@
+package action_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "bosh-softlayer-cpi/action"
+
+	instance "bosh-softlayer-cpi/softlayer/virtual_guest_service"
+	instancefakes "bosh-softlayer-cpi/softlayer/virtual_guest_service/fakes"
+)
+
+var _ = Describe("CalculateVMCloudProperties", func() {
+	var (
+		err         error
+		cloudProps  VMCloudProperties
+		vmResources VMResources
+
+		vmService *instancefakes.FakeService
+
+		calculateVMCloudProperties CalculateVMCloudProperties
+	)
+
+	BeforeEach(func() {
+		vmService = &instancefakes.FakeService{}
+		calculateVMCloudProperties = NewCalculateVMCloudProperties(vmService)
+
+		vmResources = VMResources{
+			Cpu:               2,
+			Ram:               3072,
+			EphemeralDiskSize: 20480,
+		}
+	})
+
+	Describe("Run", func() {
+		It("returns the cpu/memory pair and ephemeral disk size", func() {
+			vmService.CalculateResourcesReturns(
+				instance.Resources{
+					Cpu:               2,
+					Memory:            4096,
+					EphemeralDiskSize: 25,
+				},
+				nil,
+			)
+
+			cloudProps, err = calculateVMCloudProperties.Run(vmResources)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmService.CalculateResourcesCallCount()).To(Equal(1))
+			actualCpu, actualMemory, actualEphemeralDiskSize := vmService.CalculateResourcesArgsForCall(0)
+			Expect(actualCpu).To(Equal(2))
+			Expect(actualMemory).To(Equal(3072))
+			Expect(actualEphemeralDiskSize).To(Equal(20480))
+			Expect(cloudProps).To(Equal(VMCloudProperties{
+				Cpu:               2,
+				Memory:            4096,
+				EphemeralDiskSize: 25,
+			}))
+		})
+
+		It("returns the flavor key name", func() {
+			vmService.CalculateResourcesReturns(
+				instance.Resources{
+					FlavorKeyName: "B1_2X4X25",
+				},
+				nil,
+			)
+
+			cloudProps, err = calculateVMCloudProperties.Run(vmResources)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cloudProps).To(Equal(VMCloudProperties{
+				FlavorKeyName: "B1_2X4X25",
+			}))
+		})
+
+		It("returns an error if vmService calculate resources call returns an error", func() {
+			vmService.CalculateResourcesReturns(
+				instance.Resources{},
+				errors.New("fake-vm-service-error"),
+			)
+
+			_, err = calculateVMCloudProperties.Run(vmResources)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-vm-service-error"))
+		})
+	})
+})
@@ -0,0 +1,89 @@
+package action
+
+import (
+	boslconfig "bosh-softlayer-cpi/softlayer/config"
+)
+
+// The highest CPI API version supported by this CPI
+const MaxSupportedApiVersion = 2
+
+// ApiVersions are the CPI API versions in effect for a particular request.
+type ApiVersions struct {
+	// CPI API contract version negotiated with the director
+	Contract int
+
+	// CPI API version supported by the agent on the stemcell
+	Stemcell int
+}
+
+// CallContext holds the request context sent by the director along with a CPI method call.
+type CallContext struct {
+	ApiVersions ApiVersions
+
+	// ID of the director request, used to correlate log lines
+	RequestID string
+
+	// UUID of the director issuing the request
+	DirectorUUID string
+
+	// SoftLayer properties sent by the director, taking precedence over the CPI config
+	SoftLayer SoftLayerOverrides
+
+	// Verify orders with SoftLayer instead of placing them
+	DryRun bool
+}
+
+// SoftLayerOverrides are the per-request SoftLayer properties sent by the director.
+type SoftLayerOverrides struct {
+	Username    string
+	ApiKey      string
+	ApiEndpoint string
+}
+
+// ContextAware is implemented by actions whose behaviour depends on the request context.
+type ContextAware interface {
+	WithContext(context CallContext) Action
+}
+
+// NewApiVersions returns the API versions to use given the version requested by the director
+// and the version reported for the stemcell (zero when not provided).
+func NewApiVersions(contract int, stemcell int) ApiVersions {
+	if contract < 1 {
+		contract = 1
+	}
+	if contract > MaxSupportedApiVersion {
+		contract = MaxSupportedApiVersion
+	}
+
+	if stemcell < 1 {
+		stemcell = 1
+	}
+
+	return ApiVersions{
+		Contract: contract,
+		Stemcell: stemcell,
+	}
+}
+
+func (v ApiVersions) IsV2() bool {
+	return v.Contract >= 2
+}
+
+func (o SoftLayerOverrides) IsEmpty() bool {
+	return o.Username == "" && o.ApiKey == "" && o.ApiEndpoint == ""
+}
+
+// ApplyTo returns a copy of the SoftLayer config with the non-empty overrides applied.
+func (o SoftLayerOverrides) ApplyTo(softlayerConfig boslconfig.Config) boslconfig.Config {
+	if o.Username != "" {
+		softlayerConfig.Username = o.Username
+	}
+	if o.ApiKey != "" {
+		softlayerConfig.ApiKey = o.ApiKey
+	}
+	if o.ApiEndpoint != "" {
+		softlayerConfig.ApiEndpoint = o.ApiEndpoint
+	}
+
+	return softlayerConfig
+}
@@ -21,6 +21,9 @@ type NetworkCloudProperties struct {
 	SubnetIds           []int `json:"subnet_ids,omitempty"`
 	VlanIds             []int `json:"vlan_ids,omitempty"`
 	SourcePolicyRouting bool  `json:"source_policy_routing,omitempty"`
+
+	// Destinations in CIDR notation routed through the gateway of the network
+	Routes []string `json:"routes,omitempty"`
 }
 
 type SnapshotMetadata struct {
@@ -53,7 +56,7 @@ type VMCloudProperties struct {
 	FlavorKeyName     string `json:"flavor_key_name,omitempty"`
 	Cpu               int    `json:"cpu,omitempty"`
 	Memory            int    `json:"memory,omitempty"`
-	Datacenter        string `json:"datacenter"`
+	Datacenter        string `json:"datacenter,omitempty"`
 	EphemeralDiskSize int    `json:"ephemeral_disk_size,omitempty"`
 	SshKey            int    `json:"ssh_key,omitempty"`
 
@@ -65,6 +68,9 @@ type VMCloudProperties struct {
 	DeployedByBoshCLI bool `json:"deployed_by_boshcli,omitempty"`
 
 	MaxNetworkSpeed int `json:"max_network_speed,omitempty"`
+
+	// Orders a primary IPv6 address for the public network component, see NormalizeIpv6
+	PrimaryIpv6Address bool `json:"primary_ipv6_address,omitempty"`
 }
 
 func (vmProps *VMCloudProperties) Validate() error {
@@ -6,6 +6,7 @@ import (
 
 	"bosh-softlayer-cpi/config"
 	"bosh-softlayer-cpi/softlayer/client"
+	boslconfig "bosh-softlayer-cpi/softlayer/config"
 	"bosh-softlayer-cpi/softlayer/disk_service"
 	"bosh-softlayer-cpi/softlayer/snapshot_service"
 	"bosh-softlayer-cpi/softlayer/virtual_guest_service"
@@ -15,8 +16,16 @@ import (
 	"bosh-softlayer-cpi/softlayer/stemcell_service"
 )
 
+// SoftlayerClientBuilder builds a SoftLayer client for the given SoftLayer config.
+type SoftlayerClientBuilder func(softlayerConfig boslconfig.Config) client.Client
+
 type concreteFactory struct {
 	availableActions map[string]Action
+
+	uuidGen       boshuuid.Generator
+	cfg           config.Config
+	logger        logger.Logger
+	clientBuilder SoftlayerClientBuilder
 }
 
 func NewConcreteFactory(
@@ -26,10 +35,19 @@ func NewConcreteFactory(
 	logger logger.Logger,
 ) concreteFactory {
 
-	registryClient := registry.NewHTTPClient(
-		cfg.Cloud.Properties.Registry,
-		logger,
-	)
+	// Without a registry the agent settings are kept in the instance user metadata
+	var registryClient registry.Client
+	if cfg.Cloud.Properties.Registry.IsConfigured() {
+		registryClient = registry.NewHTTPClient(
+			cfg.Cloud.Properties.Registry,
+			logger,
+		)
+	} else {
+		registryClient = registry.NewMetadataClient(
+			softlayerClient,
+			logger,
+		)
+	}
 
 	diskService := disk.NewSoftlayerDiskService(
 		softlayerClient,
@@ -46,7 +64,7 @@ func NewConcreteFactory(
 		softlayerClient,
 		uuidGen,
 		logger,
-	)
+	).WithPrivateRoutes(cfg.Cloud.Properties.SoftLayer.PrivateRoutesOrDefault())
 
 	snapshotService := snapshot.NewSoftlayerSnapshotService(
 		softlayerClient,
@@ -54,6 +72,10 @@ func NewConcreteFactory(
 	)
 
 	return concreteFactory{
+		uuidGen: uuidGen,
+		cfg:     cfg,
+		logger:  logger,
+
 		availableActions: map[string]Action{
 			// Stemcell management
 			"create_stemcell": NewCreateStemcell(stemcellService),
@@ -74,10 +96,14 @@ func NewConcreteFactory(
 			"set_vm_metadata":    NewSetVMMetadata(vmService),
 			"configure_networks": NewConfigureNetworks(vmService, registryClient),
 
+			"calculate_vm_cloud_properties": NewCalculateVMCloudProperties(vmService),
+			"validate_networks":             NewValidateNetworks(vmService),
+
 			// Disk management
 			"has_disk":          NewHasDisk(diskService),
 			"create_disk":       NewCreateDisk(diskService, vmService),
 			"delete_disk":       NewDeleteDisk(diskService),
+			"resize_disk":       NewResizeDisk(diskService),
 			"attach_disk":       NewAttachDisk(diskService, vmService, registryClient),
 			"detach_disk":       NewDetachDisk(vmService, registryClient),
 			"get_disks":         NewGetDisks(vmService),
@@ -93,16 +119,41 @@ func NewConcreteFactory(
 
 			// Not implemented (others):
 			//   current_vm_id
-			//   calculate_vm_cloud_properties
 		},
 	}
 }
 
-func (f concreteFactory) Create(method string) (Action, error) {
+// WithClientBuilder allows the factory to build a new SoftLayer client when a request
+// overrides the SoftLayer properties of the CPI config. The builder is called for each
+// such request, so it is up to the builder to reuse what it can of earlier clients.
+func (f concreteFactory) WithClientBuilder(clientBuilder SoftlayerClientBuilder) concreteFactory {
+	f.clientBuilder = clientBuilder
+	return f
+}
+
+// Create returns the action of method. Actions are bound to the client of their factory, so
+// a request overriding the SoftLayer properties gets its action from a factory of its own.
+func (f concreteFactory) Create(method string, context CallContext) (Action, error) {
+	if !context.SoftLayer.IsEmpty() && f.clientBuilder != nil {
+		cfg := f.cfg
+		cfg.Cloud.Properties.SoftLayer = context.SoftLayer.ApplyTo(cfg.Cloud.Properties.SoftLayer)
+
+		f = NewConcreteFactory(
+			f.clientBuilder(cfg.Cloud.Properties.SoftLayer),
+			f.uuidGen,
+			cfg,
+			f.logger,
+		).WithClientBuilder(f.clientBuilder)
+	}
+
 	action, found := f.availableActions[method]
 	if !found {
 		return nil, bosherr.Errorf("Could not create action with method %s", method)
 	}
 
+	if contextAware, ok := action.(ContextAware); ok {
+		action = contextAware.WithContext(context)
+	}
+
 	return action, nil
 }
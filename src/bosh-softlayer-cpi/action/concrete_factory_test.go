@@ -12,6 +12,7 @@ import (
 	cpiLog "bosh-softlayer-cpi/logger"
 	"bosh-softlayer-cpi/registry"
 	bosl "bosh-softlayer-cpi/softlayer/client"
+	fakeclient "bosh-softlayer-cpi/softlayer/client/fakes"
 	boslconfig "bosh-softlayer-cpi/softlayer/config"
 	"bosh-softlayer-cpi/softlayer/disk_service"
 	"bosh-softlayer-cpi/softlayer/stemcell_service"
@@ -96,13 +97,13 @@ var _ = Describe("ConcreteFactory", func() {
 	})
 
 	It("returns error if action cannot be created", func() {
-		action, err := factory.Create("fake-unknown-action")
+		action, err := factory.Create("fake-unknown-action", CallContext{})
 		Expect(err).To(HaveOccurred())
 		Expect(action).To(BeNil())
 	})
 
 	It("create_disk", func() {
-		action, err := factory.Create("create_disk")
+		action, err := factory.Create("create_disk", CallContext{})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(action).To(Equal(NewCreateDisk(
 			diskService,
@@ -111,37 +112,43 @@ var _ = Describe("ConcreteFactory", func() {
 	})
 
 	It("delete_disk", func() {
-		action, err := factory.Create("delete_disk")
+		action, err := factory.Create("delete_disk", CallContext{})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(action).To(Equal(NewDeleteDisk(diskService)))
 	})
 
+	It("resize_disk", func() {
+		action, err := factory.Create("resize_disk", CallContext{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(action).To(Equal(NewResizeDisk(diskService)))
+	})
+
 	It("attach_disk", func() {
-		action, err := factory.Create("attach_disk")
+		action, err := factory.Create("attach_disk", CallContext{})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(action).To(Equal(NewAttachDisk(diskService, vmService, registryClient)))
 	})
 
 	It("detach_disk", func() {
-		action, err := factory.Create("detach_disk")
+		action, err := factory.Create("detach_disk", CallContext{})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(action).To(Equal(NewDetachDisk(vmService, registryClient)))
 	})
 
 	It("create_stemcell", func() {
-		action, err := factory.Create("create_stemcell")
+		action, err := factory.Create("create_stemcell", CallContext{})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(action).To(Equal(NewCreateStemcell(imageService)))
 	})
 
 	It("delete_stemcell", func() {
-		action, err := factory.Create("delete_stemcell")
+		action, err := factory.Create("delete_stemcell", CallContext{})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(action).To(Equal(NewDeleteStemcell(imageService)))
 	})
 
 	It("create_vm", func() {
-		action, err := factory.Create("create_vm")
+		action, err := factory.Create("create_vm", CallContext{})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(action).To(Equal(NewCreateVM(
 			imageService,
@@ -154,67 +161,118 @@ var _ = Describe("ConcreteFactory", func() {
 	})
 
 	It("configure_networks", func() {
-		action, err := factory.Create("configure_networks")
+		action, err := factory.Create("configure_networks", CallContext{})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(action).To(Equal(NewConfigureNetworks(vmService, registryClient)))
 	})
 
 	It("delete_vm", func() {
-		action, err := factory.Create("delete_vm")
+		action, err := factory.Create("delete_vm", CallContext{})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(action).To(Equal(NewDeleteVM(vmService, registryClient, softlayerOptions)))
 	})
 
 	It("reboot_vm", func() {
-		action, err := factory.Create("reboot_vm")
+		action, err := factory.Create("reboot_vm", CallContext{})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(action).To(Equal(NewRebootVM(vmService)))
 	})
 
 	It("set_vm_metadata", func() {
-		action, err := factory.Create("set_vm_metadata")
+		action, err := factory.Create("set_vm_metadata", CallContext{})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(action).To(Equal(NewSetVMMetadata(vmService)))
 	})
 
 	It("has_vm", func() {
-		action, err := factory.Create("has_vm")
+		action, err := factory.Create("has_vm", CallContext{})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(action).To(Equal(NewHasVM(vmService)))
 	})
 
 	It("get_disks", func() {
-		action, err := factory.Create("get_disks")
+		action, err := factory.Create("get_disks", CallContext{})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(action).To(Equal(NewGetDisks(vmService)))
 	})
 
 	It("set_disk_metadata", func() {
-		action, err := factory.Create("set_disk_metadata")
+		action, err := factory.Create("set_disk_metadata", CallContext{})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(action).To(Equal(NewSetDiskMetadata(diskService)))
 	})
 
+	It("calculate_vm_cloud_properties", func() {
+		action, err := factory.Create("calculate_vm_cloud_properties", CallContext{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(action).To(Equal(NewCalculateVMCloudProperties(vmService)))
+	})
+
+	It("validate_networks", func() {
+		action, err := factory.Create("validate_networks", CallContext{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(action).To(Equal(NewValidateNetworks(vmService)))
+	})
+
 	It("info", func() {
-		action, err := factory.Create("info")
+		action, err := factory.Create("info", CallContext{})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(action).To(Equal(NewInfo()))
 	})
 
 	It("ping", func() {
-		action, err := factory.Create("ping")
+		action, err := factory.Create("ping", CallContext{})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(action).To(Equal(NewPing()))
 	})
 
+	It("keeps agent settings in the user metadata when no registry is configured", func() {
+		cfg.Cloud.Properties.Registry = registry.ClientOptions{}
+		factory = NewConcreteFactory(
+			softlayerClient,
+			uuidGen,
+			cfg,
+			logger,
+		)
+
+		action, err := factory.Create("attach_disk", CallContext{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(action).To(Equal(NewAttachDisk(diskService, vmService, registry.NewMetadataClient(softlayerClient, logger))))
+	})
+
+	It("builds the actions with a new client when the request overrides the SoftLayer credentials", func() {
+		overridingClient := &fakeclient.FakeClient{}
+		var builtConfig boslconfig.Config
+		factory = NewConcreteFactory(
+			softlayerClient,
+			uuidGen,
+			cfg,
+			logger,
+		).WithClientBuilder(func(softlayerConfig boslconfig.Config) bosl.Client {
+			builtConfig = softlayerConfig
+			return overridingClient
+		})
+
+		action, err := factory.Create("delete_disk", CallContext{
+			SoftLayer: SoftLayerOverrides{
+				Username: "fake-context-username",
+				ApiKey:   "fake-context-api-key",
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(builtConfig.Username).To(Equal("fake-context-username"))
+		Expect(builtConfig.ApiKey).To(Equal("fake-context-api-key"))
+		Expect(action).To(Equal(NewDeleteDisk(disk.NewSoftlayerDiskService(overridingClient, logger))))
+	})
+
 	It("when action is current_vm_id returns an error because this CPI does not implement the method", func() {
-		action, err := factory.Create("current_vm_id")
+		action, err := factory.Create("current_vm_id", CallContext{})
 		Expect(err).To(HaveOccurred())
 		Expect(action).To(BeNil())
 	})
 
 	It("when action is wrong returns an error because it is not an official CPI method", func() {
-		action, err := factory.Create("wrong")
+		action, err := factory.Create("wrong", CallContext{})
 		Expect(err).To(HaveOccurred())
 		Expect(action).To(BeNil())
 	})
@@ -11,6 +11,7 @@ import (
 type CreateDisk struct {
 	diskService disk.Service
 	vmService   instance.Service
+	dryRun      bool
 }
 
 func NewCreateDisk(
@@ -23,30 +24,44 @@ func NewCreateDisk(
 	}
 }
 
-func (cd CreateDisk) Run(size int, cloudProps DiskCloudProperties, vmCID VMCID) (string, error) {
+func (cd CreateDisk) WithContext(context CallContext) Action {
+	cd.dryRun = context.DryRun
+	return cd
+}
+
+func (cd CreateDisk) Run(size int, cloudProps DiskCloudProperties, vmCID VMCID) (interface{}, error) {
 	// Find the VM (if provided) so we can create the disk in the same datacenter
 	var location string
 	if vmCID != 0 {
 		vm, err := cd.vmService.Find(vmCID.Int())
 		if err != nil {
 			if _, ok := err.(api.CloudError); ok {
-				return "", err
+				return nil, err
 			}
-			return "", bosherr.WrapErrorf(err, "Creating disk with size '%d'", size)
+			return nil, bosherr.WrapErrorf(err, "Creating disk with size '%d'", size)
 		}
 		location = *vm.Datacenter.Name
 	} else {
 		if len(cloudProps.DataCenter) > 0 {
 			location = cloudProps.DataCenter
 		} else {
-			return "", bosherr.Errorf("Creating disk with size '%d': Invalid datacenter name specified.", size)
+			return nil, bosherr.Errorf("Creating disk with size '%d': Invalid datacenter name specified.", size)
 		}
 	}
 
+	if cd.dryRun {
+		report, err := cd.diskService.VerifyCreate(size, cloudProps.Iops, location, cloudProps.SnapshotSpace)
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Verifying disk with size '%d'", size)
+		}
+
+		return report, nil
+	}
+
 	// Create the Disk
 	disk, err := cd.diskService.Create(size, cloudProps.Iops, location, cloudProps.SnapshotSpace)
 	if err != nil {
-		return "", bosherr.WrapErrorf(err, "Creating disk with size '%d'", size)
+		return nil, bosherr.WrapErrorf(err, "Creating disk with size '%d'", size)
 	}
 
 	return DiskCID(disk).String(), nil
@@ -9,6 +9,7 @@ import (
 	. "bosh-softlayer-cpi/action"
 
 	"bosh-softlayer-cpi/api"
+	bosl "bosh-softlayer-cpi/softlayer/client"
 	diskfakes "bosh-softlayer-cpi/softlayer/disk_service/fakes"
 	instancefakes "bosh-softlayer-cpi/softlayer/virtual_guest_service/fakes"
 	"github.com/softlayer/softlayer-go/datatypes"
@@ -18,7 +19,7 @@ import (
 var _ = Describe("CreateDisk", func() {
 	var (
 		err     error
-		diskCID string
+		diskCID interface{}
 
 		diskService *diskfakes.FakeService
 		vmService   *instancefakes.FakeService
@@ -126,5 +127,49 @@ var _ = Describe("CreateDisk", func() {
 				Expect(diskService.CreateCallCount()).To(Equal(1))
 			})
 		})
+
+		Context("when dry_run is set in the request context", func() {
+			var dryRunDisk Action
+
+			BeforeEach(func() {
+				cloudProps = DiskCloudProperties{
+					DataCenter:    "fake-datacenter-name",
+					Iops:          1000,
+					SnapshotSpace: 20,
+				}
+				dryRunDisk = createDisk.WithContext(CallContext{DryRun: true})
+
+				diskService.VerifyCreateReturns(
+					bosl.OrderReport{Verified: true, SetupFee: 1.5},
+					nil,
+				)
+			})
+
+			It("verifies the order instead of creating the disk", func() {
+				report, err := dryRunDisk.(CreateDisk).Run(32768, cloudProps, VMCID(0))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(report).To(Equal(bosl.OrderReport{Verified: true, SetupFee: 1.5}))
+
+				Expect(diskService.CreateCallCount()).To(Equal(0))
+				Expect(diskService.VerifyCreateCallCount()).To(Equal(1))
+				actualSize, actualIops, actualLocation, actualSnapshotSpace := diskService.VerifyCreateArgsForCall(0)
+				Expect(actualSize).To(Equal(32768))
+				Expect(actualIops).To(Equal(1000))
+				Expect(actualLocation).To(Equal("fake-datacenter-name"))
+				Expect(actualSnapshotSpace).To(Equal(20))
+			})
+
+			It("returns an error if diskService verifyCreate call returns an error", func() {
+				diskService.VerifyCreateReturns(
+					bosl.OrderReport{},
+					errors.New("fake-disk-service-error"),
+				)
+
+				_, err := dryRunDisk.(CreateDisk).Run(32768, cloudProps, VMCID(0))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-disk-service-error"))
+				Expect(diskService.CreateCallCount()).To(Equal(0))
+			})
+		})
 	})
 })
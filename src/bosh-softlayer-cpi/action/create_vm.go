@@ -26,6 +26,8 @@ type CreateVM struct {
 	registryOptions     registry.ClientOptions
 	agentOptions        registry.AgentOptions
 	softlayerOptions    boslconfig.Config
+	apiVersions         ApiVersions
+	dryRun              bool
 }
 
 func NewCreateVM(
@@ -45,19 +47,38 @@ func NewCreateVM(
 	return
 }
 
-func (cv CreateVM) Run(agentID string, stemcellCID StemcellCID, cloudProps VMCloudProperties, networks Networks, diskIDs []DiskCID, env Environment) (string, error) {
+func (cv CreateVM) WithContext(context CallContext) Action {
+	cv.apiVersions = context.ApiVersions
+	cv.dryRun = context.DryRun
+	return cv
+}
+
+func (cv CreateVM) Run(agentID string, stemcellCID StemcellCID, cloudProps VMCloudProperties, networks Networks, diskIDs []DiskCID, env Environment) (interface{}, error) {
+	if cv.dryRun {
+		return cv.verify(stemcellCID, cloudProps, networks)
+	}
+
 	// Validate VM properties
 	if err := cloudProps.Validate(); err != nil {
-		return "", bosherr.WrapError(err, "Creating VM")
+		return nil, bosherr.WrapError(err, "Creating VM")
 	}
 
 	// Find stemcell uuid
 	stemcellUuid, err := cv.stemcellService.Find(int(stemcellCID))
 	if err != nil {
 		if _, ok := err.(api.CloudError); ok {
-			return "", err
+			return nil, err
+		}
+		return nil, bosherr.WrapErrorf(err, "Finding stemcell uuid with id '%d'", stemcellCID.Int())
+	}
+
+	// Find the os-code the stemcell was imported with, the network settings follow its conventions
+	stemcellOsCode, err := cv.stemcellService.FindOsCode(int(stemcellCID))
+	if err != nil {
+		if _, ok := err.(api.CloudError); ok {
+			return nil, err
 		}
-		return "", bosherr.WrapErrorf(err, "Finding stemcell uuid with id '%d'", stemcellCID.Int())
+		return nil, bosherr.WrapErrorf(err, "Finding os code of stemcell with id '%d'", stemcellCID.Int())
 	}
 
 	// Set public key
@@ -65,7 +86,7 @@ func (cv CreateVM) Run(agentID string, stemcellCID StemcellCID, cloudProps VMClo
 	if len(cv.softlayerOptions.PublicKey) > 0 {
 		sshKey, err = cv.virtualGuestService.CreateSshKey("bosh_cpi", cv.softlayerOptions.PublicKey, cv.softlayerOptions.PublicKeyFingerPrint)
 		if err != nil {
-			return "", bosherr.WrapErrorf(err, "Creating Public Key with content '%s'", cv.softlayerOptions.PublicKey)
+			return nil, bosherr.WrapErrorf(err, "Creating Public Key with content '%s'", cv.softlayerOptions.PublicKey)
 		}
 		cloudProps.SshKey = sshKey
 	}
@@ -73,13 +94,28 @@ func (cv CreateVM) Run(agentID string, stemcellCID StemcellCID, cloudProps VMClo
 	// Set userData without server name
 	userData, err := cv.createUserDataForInstance(&cv.registryOptions, cloudProps.DeployedByBoshCLI)
 	if err != nil {
-		return "", bosherr.WrapError(err, "Creating VM UserData")
+		return nil, bosherr.WrapError(err, "Creating VM UserData")
 	}
 
 	// Inspect networks to get NetworkComponents
 	publicNetworkComponent, privateNetworkComponent, err := cv.getNetworkComponents(networks)
 	if err != nil {
-		return "", bosherr.WrapError(err, "Getting NetworkComponents from networks settings")
+		return nil, bosherr.WrapError(err, "Getting NetworkComponents from networks settings")
+	}
+
+	if err = cv.validateIpv6Networks(cloudProps, networks, publicNetworkComponent); err != nil {
+		return nil, bosherr.WrapError(err, "Validating IPv6 networks")
+	}
+
+	// Find the static IPs of manual networks before anything is ordered
+	staticIps, err := cv.getStaticIps(networks)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Validating static IPs")
+	}
+
+	vips, err := cv.getVips(networks, publicNetworkComponent)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Validating vip networks")
 	}
 
 	// Create Virtual Guest template
@@ -94,7 +130,7 @@ func (cv CreateVM) Run(agentID string, stemcellCID StemcellCID, cloudProps VMClo
 	}
 
 	if err = instanceNetworks.Validate(); err != nil {
-		return "", bosherr.WrapError(err, "Creating VM")
+		return nil, bosherr.WrapError(err, "Creating VM")
 	}
 
 	if boshenv, ok := env["bosh"]; ok {
@@ -106,6 +142,19 @@ func (cv CreateVM) Run(agentID string, stemcellCID StemcellCID, cloudProps VMClo
 		}
 	}
 
+	// Reserve the static IPs before anything is ordered, so that concurrent calls cannot take them too
+	if len(staticIps) > 0 {
+		if err = cv.virtualGuestService.ReserveStaticIps(agentID, staticIps); err != nil {
+			return nil, bosherr.WrapError(err, "Reserving static IPs")
+		}
+
+		defer func() {
+			if err != nil {
+				cv.virtualGuestService.ReleaseReservedStaticIps(agentID)
+			}
+		}()
+	}
+
 	// CID for returned VM
 	cid := 0
 	osReloaded := false
@@ -113,7 +162,7 @@ func (cv CreateVM) Run(agentID string, stemcellCID StemcellCID, cloudProps VMClo
 	if !cv.softlayerOptions.DisableOsReload {
 		cid, err = cv.createByOsReload(stemcellCID, virtualGuestTemplate, instanceNetworks, userData)
 		if err != nil {
-			return "", bosherr.WrapError(err, "OS reloading VM")
+			return nil, bosherr.WrapError(err, "OS reloading VM")
 		}
 
 		osReloaded = true
@@ -124,9 +173,9 @@ func (cv CreateVM) Run(agentID string, stemcellCID StemcellCID, cloudProps VMClo
 		cid, err = cv.virtualGuestService.Create(virtualGuestTemplate, cv.softlayerOptions.EnableVps, stemcellCID.Int(), []int{cloudProps.SshKey}, userData)
 		if err != nil {
 			if _, ok := err.(api.CloudError); ok {
-				return "", err
+				return nil, err
 			}
-			return "", bosherr.WrapError(err, "Creating VM")
+			return nil, bosherr.WrapError(err, "Creating VM")
 		}
 	}
 
@@ -135,14 +184,41 @@ func (cv CreateVM) Run(agentID string, stemcellCID StemcellCID, cloudProps VMClo
 	// If any of the below code fails, we must delete the created cid
 	defer func() {
 		if err != nil && !osReloaded {
+			if len(staticIps) > 0 {
+				cv.virtualGuestService.ReleaseStaticIps(cid)
+			}
+			if len(vips) > 0 {
+				cv.virtualGuestService.UnrouteGlobalIps(cid)
+			}
 			cv.virtualGuestService.CleanUp(cid)
 		}
 	}()
 
+	// Record the static IPs of manual networks as assigned to the VM in place of the reservation
+	if len(staticIps) > 0 {
+		if err = cv.virtualGuestService.AssignStaticIps(cid, staticIps); err != nil {
+			return nil, bosherr.WrapError(err, "Assigning static IPs to VM")
+		}
+	}
+
+	// Order the primary IPv6 address before reading the network settings back
+	if cloudProps.PrimaryIpv6Address {
+		if err = cv.virtualGuestService.AttachPrimaryIpv6Address(cid); err != nil {
+			return nil, bosherr.WrapError(err, "Ordering primary IPv6 address")
+		}
+	}
+
+	// Route the global IPs of vip networks to the public address of the VM
+	for _, vip := range vips {
+		if err = cv.virtualGuestService.RouteGlobalIp(cid, vip); err != nil {
+			return nil, bosherr.WrapError(err, "Routing global IP to VM")
+		}
+	}
+
 	// Config VM network settings
-	instanceNetworks, err = cv.virtualGuestService.ConfigureNetworks(cid, instanceNetworks)
+	instanceNetworks, err = cv.virtualGuestService.ConfigureNetworks(cid, stemcellOsCode, instanceNetworks)
 	if err != nil {
-		return "", bosherr.WrapError(err, "Configuring VM networks")
+		return nil, bosherr.WrapError(err, "Configuring VM networks")
 	}
 
 	// Create VM agent settings
@@ -151,7 +227,7 @@ func (cv CreateVM) Run(agentID string, stemcellCID StemcellCID, cloudProps VMClo
 	if !cloudProps.DeployedByBoshCLI {
 		// Configure mbus and blobstore options if needed
 		if err = cv.postConfig(cid, &cv.agentOptions); err != nil {
-			return "", bosherr.WrapError(err, "Post config")
+			return nil, bosherr.WrapError(err, "Post config")
 		}
 	}
 
@@ -161,19 +237,79 @@ func (cv CreateVM) Run(agentID string, stemcellCID StemcellCID, cloudProps VMClo
 	if cloudProps.EphemeralDiskSize > 0 {
 		err = cv.virtualGuestService.AttachEphemeralDisk(cid, cloudProps.EphemeralDiskSize)
 		if err != nil {
-			return "", bosherr.WrapErrorf(err, "Attaching ephemeral disk to VM with id '%d'", cid)
+			return nil, bosherr.WrapErrorf(err, "Attaching ephemeral disk to VM with id '%d'", cid)
 		}
 		// Update VM agent settings
 		agentSettings = agentSettings.AttachEphemeralDisk(registry.DefaultEphemeralDisk)
 	}
 
 	if err = cv.registryClient.Update(instanceID, agentSettings); err != nil {
-		return "", bosherr.WrapError(err, "Updating registryClient")
+		return nil, bosherr.WrapError(err, "Updating registryClient")
+	}
+
+	if cv.apiVersions.IsV2() {
+		return []interface{}{instanceID, agentNetworks}, nil
 	}
 
 	return instanceID, nil
 }
 
+// verify builds the same virtual guest template as Run and has SoftLayer check the order.
+// Nothing is created, so the template carries no ssh key.
+func (cv CreateVM) verify(stemcellCID StemcellCID, cloudProps VMCloudProperties, networks Networks) (interface{}, error) {
+	if err := cloudProps.Validate(); err != nil {
+		return boslc.NewRejectedOrderReport(err), nil
+	}
+
+	stemcellUuid, err := cv.stemcellService.Find(int(stemcellCID))
+	if err != nil {
+		if _, ok := err.(api.CloudError); ok {
+			return nil, err
+		}
+		return nil, bosherr.WrapErrorf(err, "Finding stemcell uuid with id '%d'", stemcellCID.Int())
+	}
+
+	publicNetworkComponent, privateNetworkComponent, err := cv.getNetworkComponents(networks)
+	if err != nil {
+		return boslc.NewRejectedOrderReport(bosherr.WrapError(err, "Getting NetworkComponents from networks settings")), nil
+	}
+
+	if err = cv.validateIpv6Networks(cloudProps, networks, publicNetworkComponent); err != nil {
+		return boslc.NewRejectedOrderReport(bosherr.WrapError(err, "Validating IPv6 networks")), nil
+	}
+
+	if _, err = cv.getStaticIps(networks); err != nil {
+		return boslc.NewRejectedOrderReport(bosherr.WrapError(err, "Validating static IPs")), nil
+	}
+
+	if _, err = cv.getVips(networks, publicNetworkComponent); err != nil {
+		return boslc.NewRejectedOrderReport(bosherr.WrapError(err, "Validating vip networks")), nil
+	}
+
+	virtualGuestTemplate := cv.createVirtualGuestTemplate(stemcellUuid, *cloudProps.AsInstanceProperties(), publicNetworkComponent, privateNetworkComponent)
+
+	var instanceNetworks instance.Networks
+	if publicNetworkComponent != nil {
+		instanceNetworks = networks.AsInstanceServiceNetworks(publicNetworkComponent.NetworkVlan)
+	} else {
+		instanceNetworks = networks.AsInstanceServiceNetworks(&datatypes.Network_Vlan{})
+	}
+
+	if err = instanceNetworks.Validate(); err != nil {
+		return boslc.NewRejectedOrderReport(err), nil
+	}
+
+	report, err := cv.virtualGuestService.VerifyCreate(virtualGuestTemplate)
+	if err != nil {
+		if _, ok := err.(api.CloudError); ok {
+			return nil, err
+		}
+		return nil, bosherr.WrapError(err, "Verifying VM order")
+	}
+
+	return report, nil
+}
+
 func (cv CreateVM) createVirtualGuestTemplate(stemcellUuid string, cloudProps VMCloudProperties,
 	publicNetworkComponent *datatypes.Virtual_Guest_Network_Component, privateNetworkComponent *datatypes.Virtual_Guest_Network_Component) *datatypes.Virtual_Guest {
 
@@ -229,10 +365,13 @@ func (cv CreateVM) createVirtualGuestTemplate(stemcellUuid string, cloudProps VM
 	return virtualGuestTemplate
 }
 
+// getNetworkComponents picks the primary public and private network components of the VM from its
+// dynamic networks
 func (cv CreateVM) getNetworkComponents(networks Networks) (*datatypes.Virtual_Guest_Network_Component, *datatypes.Virtual_Guest_Network_Component, error) {
 	var publicNetworkComponent, privateNetworkComponent *datatypes.Virtual_Guest_Network_Component
 
-	for name, nw := range networks {
+	for _, name := range networks.sortedNames() {
+		nw := networks[name]
 		if nw.Type == "manual" {
 			continue
 		}
@@ -240,64 +379,48 @@ func (cv CreateVM) getNetworkComponents(networks Networks) (*datatypes.Virtual_G
 			for _, subnetId := range nw.CloudProperties.SubnetIds {
 				networkComponent, err := cv.createNetworkComponentsBySubnetId(subnetId)
 				if err != nil {
-					return &datatypes.Virtual_Guest_Network_Component{},
-						&datatypes.Virtual_Guest_Network_Component{},
-						bosherr.WrapErrorf(err, "Network: %s, subnet id: %d", name, subnetId)
+					return nil, nil, bosherr.WrapErrorf(err, "Network: %s, subnet id: %d", name, subnetId)
 				}
 
 				switch *networkComponent.NetworkVlan.NetworkSpace {
 				case "PRIVATE":
 					if privateNetworkComponent == nil {
 						privateNetworkComponent = networkComponent
-					} else if privateNetworkComponent.NetworkVlan.PrimarySubnetId != networkComponent.NetworkVlan.PrimarySubnetId {
-						return &datatypes.Virtual_Guest_Network_Component{},
-							&datatypes.Virtual_Guest_Network_Component{},
-							bosherr.Error("Only one private VLAN is supported")
+					} else if !isPrimarySubnet(privateNetworkComponent, subnetId) {
+						return nil, nil, bosherr.Errorf("Network: %s, only one private VLAN is supported", name)
 					}
 				case "PUBLIC":
 					if publicNetworkComponent == nil {
 						publicNetworkComponent = networkComponent
-					} else if publicNetworkComponent.NetworkVlan.PrimarySubnetId != publicNetworkComponent.NetworkVlan.PrimarySubnetId {
-						return &datatypes.Virtual_Guest_Network_Component{},
-							&datatypes.Virtual_Guest_Network_Component{},
-							bosherr.Error("Only one public VLAN is supported")
+					} else if !isPrimarySubnet(publicNetworkComponent, subnetId) {
+						return nil, nil, bosherr.Errorf("Network: %s, only one public VLAN is supported", name)
 					}
 				default:
-					return &datatypes.Virtual_Guest_Network_Component{},
-						&datatypes.Virtual_Guest_Network_Component{},
-						bosherr.Errorf("networkVlan %d: unknown network type '%s'", subnetId, *networkComponent.NetworkVlan.NetworkSpace)
+					return nil, nil, bosherr.Errorf("networkVlan %d: unknown network type '%s'", subnetId, *networkComponent.NetworkVlan.NetworkSpace)
 				}
 			}
 		} else if len(nw.CloudProperties.VlanIds) > 0 {
 			for _, vlanId := range nw.CloudProperties.VlanIds {
 				networkComponent, err := cv.createNetworkComponentsByVlanId(vlanId)
 				if err != nil {
-					return &datatypes.Virtual_Guest_Network_Component{},
-						&datatypes.Virtual_Guest_Network_Component{},
-						bosherr.WrapErrorf(err, "Network: %s, vlan id: %d", name, vlanId)
+					return nil, nil, bosherr.WrapErrorf(err, "Network: %s, vlan id: %d", name, vlanId)
 				}
 
 				switch *networkComponent.NetworkVlan.NetworkSpace {
 				case "PRIVATE":
 					if privateNetworkComponent == nil {
 						privateNetworkComponent = networkComponent
-					} else if privateNetworkComponent.NetworkVlan.Id != networkComponent.NetworkVlan.Id {
-						return &datatypes.Virtual_Guest_Network_Component{},
-							&datatypes.Virtual_Guest_Network_Component{},
-							bosherr.Error("Only one private VLAN is supported")
+					} else if !isNativeVlan(privateNetworkComponent, vlanId) {
+						return nil, nil, bosherr.Errorf("Network: %s, only one private VLAN is supported", name)
 					}
 				case "PUBLIC":
 					if publicNetworkComponent == nil {
 						publicNetworkComponent = networkComponent
-					} else if publicNetworkComponent.NetworkVlan.Id != publicNetworkComponent.NetworkVlan.Id {
-						return &datatypes.Virtual_Guest_Network_Component{},
-							&datatypes.Virtual_Guest_Network_Component{},
-							bosherr.Error("Only one public VLAN is supported")
+					} else if !isNativeVlan(publicNetworkComponent, vlanId) {
+						return nil, nil, bosherr.Errorf("Network: %s, only one public VLAN is supported", name)
 					}
 				default:
-					return &datatypes.Virtual_Guest_Network_Component{},
-						&datatypes.Virtual_Guest_Network_Component{},
-						bosherr.Errorf("networkVlan %d: unknown network type '%s'", vlanId, *networkComponent.NetworkVlan.NetworkSpace)
+					return nil, nil, bosherr.Errorf("networkVlan %d: unknown network type '%s'", vlanId, *networkComponent.NetworkVlan.NetworkSpace)
 				}
 			}
 		}
@@ -310,6 +433,126 @@ func (cv CreateVM) getNetworkComponents(networks Networks) (*datatypes.Virtual_G
 	return publicNetworkComponent, privateNetworkComponent, nil
 }
 
+// getStaticIps finds the IP address records of the IPv4 addresses of manual networks in their portable
+// subnets, failing when one of them is reserved or already assigned to another VM
+func (cv CreateVM) getStaticIps(networks Networks) ([]datatypes.Network_Subnet_IpAddress, error) {
+	var staticIps []datatypes.Network_Subnet_IpAddress
+
+	for _, name := range networks.sortedNames() {
+		nw := networks[name]
+		ip := net.ParseIP(nw.IP)
+		if !nw.IsManual() || ip == nil || ip.To4() == nil {
+			continue
+		}
+
+		ipAddress, err := cv.virtualGuestService.FindStaticIp(nw.IP, nw.CloudProperties.VlanIds, nw.CloudProperties.SubnetIds)
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Network: %s", name)
+		}
+		staticIps = append(staticIps, ipAddress)
+	}
+
+	return staticIps, nil
+}
+
+// getVips lists the global IPs of vip networks, which are routed to the primary public address of the VM
+func (cv CreateVM) getVips(networks Networks, publicNetworkComponent *datatypes.Virtual_Guest_Network_Component) ([]string, error) {
+	var vips []string
+
+	for _, name := range networks.sortedNames() {
+		nw := networks[name]
+		if nw.Type != "vip" {
+			continue
+		}
+
+		if publicNetworkComponent == nil {
+			return nil, bosherr.Errorf("Network: %s, vip networks need a public dynamic network", name)
+		}
+		vips = append(vips, nw.IP)
+	}
+
+	return vips, nil
+}
+
+// validateIpv6Networks checks that the primary IPv6 address has a public network component to go on and a price
+// it can be ordered at once the VM exists, and that manual IPv6 networks use portable IPv6 subnets of the native public VLAN.
+func (cv CreateVM) validateIpv6Networks(cloudProps VMCloudProperties, networks Networks, publicNetworkComponent *datatypes.Virtual_Guest_Network_Component) error {
+	if cloudProps.PrimaryIpv6Address {
+		if publicNetworkComponent == nil {
+			return bosherr.Error("primary_ipv6_address needs a public dynamic network")
+		}
+
+		found, err := cv.virtualGuestService.HasPrimaryIpv6Price()
+		if err != nil {
+			return err
+		}
+		if !found {
+			return bosherr.Errorf("primary_ipv6_address cannot be ordered: the virtual server package has no standard '%s' price", boslc.PRIMARY_IPV6_CATEGORY_CODE)
+		}
+	}
+
+	for _, name := range networks.sortedNames() {
+		nw := networks[name]
+		ip := net.ParseIP(nw.IP)
+		if !nw.IsManual() || ip == nil || ip.To4() != nil {
+			continue
+		}
+
+		if len(nw.CloudProperties.VlanIds) != 1 {
+			return bosherr.Errorf("Network: %s, IPv6 networks need exactly one vlan id", name)
+		}
+		vlanId := nw.CloudProperties.VlanIds[0]
+		if !isNativeVlan(publicNetworkComponent, vlanId) {
+			return bosherr.Errorf("Network: %s, IPv6 networks are only supported on the public vlan of a dynamic network, not on vlan %d", name, vlanId)
+		}
+
+		vlan, err := cv.virtualGuestService.GetVlan(vlanId, boslc.NETWORK_IPV6_VLAN_MASK)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Getting vlan info with id '%d'", vlanId)
+		}
+
+		subnet, found := ipv6SubnetContaining(vlan.Subnets, ip)
+		if !found {
+			return bosherr.Errorf("Network: %s, ip %s is not in an IPv6 subnet of vlan %d", name, nw.IP, vlanId)
+		}
+		if subnet.SubnetType == nil || *subnet.SubnetType != "SUBNET_ON_VLAN" {
+			return bosherr.Errorf("Network: %s, ip %s is not in a portable IPv6 subnet", name, nw.IP)
+		}
+		if nw.Netmask != "" {
+			if ones, _ := net.IPMask(net.ParseIP(nw.Netmask)).Size(); ones != *subnet.Cidr {
+				return bosherr.Errorf("Network: %s, netmask %s does not match the /%d prefix of subnet %s", name, nw.Netmask, *subnet.Cidr, *subnet.NetworkIdentifier)
+			}
+		}
+	}
+
+	return nil
+}
+
+func ipv6SubnetContaining(subnets []datatypes.Network_Subnet, ip net.IP) (datatypes.Network_Subnet, bool) {
+	for _, subnet := range subnets {
+		if subnet.Version == nil || *subnet.Version != 6 || subnet.NetworkIdentifier == nil || subnet.Cidr == nil {
+			continue
+		}
+		ipNet := net.IPNet{
+			IP:   net.ParseIP(*subnet.NetworkIdentifier),
+			Mask: net.CIDRMask(*subnet.Cidr, 128),
+		}
+		if ipNet.Contains(ip) {
+			return subnet, true
+		}
+	}
+
+	return datatypes.Network_Subnet{}, false
+}
+
+func isPrimarySubnet(networkComponent *datatypes.Virtual_Guest_Network_Component, subnetId int) bool {
+	return networkComponent.NetworkVlan.PrimarySubnetId != nil && *networkComponent.NetworkVlan.PrimarySubnetId == subnetId
+}
+
+func isNativeVlan(networkComponent *datatypes.Virtual_Guest_Network_Component, vlanId int) bool {
+	return networkComponent != nil && networkComponent.NetworkVlan.Id != nil && *networkComponent.NetworkVlan.Id == vlanId
+}
+
 func (cv CreateVM) createNetworkComponentsBySubnetId(subnetId int) (*datatypes.Virtual_Guest_Network_Component, error) {
 	subnet, err := cv.virtualGuestService.GetSubnet(subnetId, boslc.NETWORK_DEFAULT_SUBNET_MASK)
 	if err != nil {
@@ -404,8 +647,6 @@ func (cv CreateVM) createByOsReload(stemcellCID StemcellCID, template *datatypes
 
 				cid = *vm.Id
 			}
-		case "vip":
-			return cid, bosherr.Error("SoftLayer Not Support VIP Networking")
 		default:
 			continue
 		}
@@ -415,6 +656,11 @@ func (cv CreateVM) createByOsReload(stemcellCID StemcellCID, template *datatypes
 }
 
 func (cv CreateVM) createUserDataForInstance(registryOptions *registry.ClientOptions, deployedByBoshCLI bool) (*registry.SoftlayerUserData, error) {
+	if !registryOptions.IsConfigured() {
+		// Agent settings are written to the user metadata once the VM networks are known
+		return &registry.SoftlayerUserData{}, nil
+	}
+
 	var directorIP string
 	var err error
 	if deployedByBoshCLI == true {
@@ -543,14 +789,23 @@ var privateRanges = []ipRange{
 		start: net.ParseIP("198.18.0.0"),
 		end:   net.ParseIP("198.19.255.255"),
 	},
+	// IPv6 unique local addresses, fc00::/7
+	ipRange{
+		start: net.ParseIP("fc00::"),
+		end:   net.ParseIP("fdff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"),
+	},
 }
 
 func IsPrivateSubnet(ipAddress net.IP) bool {
-	if ipCheck := ipAddress.To4(); ipCheck != nil {
-		for _, r := range privateRanges {
-			if inRange(r, ipAddress) {
-				return true
-			}
+	if ipAddress == nil {
+		return false
+	}
+
+	// Compare IPv4 addresses in their 16 bytes form, like the ranges
+	ipAddress = ipAddress.To16()
+	for _, r := range privateRanges {
+		if inRange(r, ipAddress) {
+			return true
 		}
 	}
 	return false
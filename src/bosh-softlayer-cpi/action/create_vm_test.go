@@ -9,6 +9,7 @@ import (
 	. "bosh-softlayer-cpi/action"
 
 	registryfakes "bosh-softlayer-cpi/registry/fakes"
+	bosl "bosh-softlayer-cpi/softlayer/client"
 	diskfakes "bosh-softlayer-cpi/softlayer/disk_service/fakes"
 	imagefakes "bosh-softlayer-cpi/softlayer/stemcell_service/fakes"
 	instance "bosh-softlayer-cpi/softlayer/virtual_guest_service"
@@ -28,7 +29,7 @@ import (
 var _ = Describe("CreateVM", func() {
 	var (
 		err                      error
-		vmCID                    string
+		vmCID                    interface{}
 		agentID                  string
 		stemcellCID              StemcellCID
 		disks                    []DiskCID
@@ -165,6 +166,10 @@ var _ = Describe("CreateVM", func() {
 				"12345678",
 				nil,
 			)
+			imageService.FindOsCodeReturns(
+				"UBUNTU_16_64",
+				nil,
+			)
 			vmService.GetVlanReturns(
 				&datatypes.Network_Vlan{
 					Id:           sl.Int(42345678),
@@ -233,12 +238,41 @@ var _ = Describe("CreateVM", func() {
 			Expect(registryClient.UpdateCalled).To(BeTrue())
 			Expect(vmService.FindCallCount()).To(Equal(0))
 			Expect(registryClient.UpdateSettings).To(Equal(expectedAgentSettings))
-			actualCid, _ := vmService.ConfigureNetworksArgsForCall(0)
+			actualCid, _, _ := vmService.ConfigureNetworksArgsForCall(0)
 			Expect(vmCID).To(Equal(VMCID(actualCid).String()))
-			_, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
+			_, _, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
 			Expect(actualInstanceNetworks).To(Equal(expectedInstanceNetworks))
 		})
 
+		It("returns the vm cid and networks when the director uses API v2", func() {
+			createVM = createVM.WithContext(CallContext{ApiVersions: NewApiVersions(2, 2)}).(CreateVM)
+
+			vmCID, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(registryClient.UpdateCalled).To(BeTrue())
+			actualCid, _, _ := vmService.ConfigureNetworksArgsForCall(0)
+			Expect(vmCID).To(Equal([]interface{}{VMCID(actualCid).String(), expectedAgentSettings.Networks}))
+		})
+
+		It("leaves the registry endpoint out of the user data when no registry is configured", func() {
+			createVM = NewCreateVM(
+				imageService,
+				vmService,
+				registryClient,
+				registry.ClientOptions{},
+				agentOptions,
+				softlayerOptions,
+			)
+
+			vmCID, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmService.ReloadOSCallCount()).To(Equal(1))
+			_, _, _, _, _, actualUserData := vmService.ReloadOSArgsForCall(0)
+			Expect(actualUserData.Registry.Endpoint).To(BeEmpty())
+			Expect(registryClient.UpdateCalled).To(BeTrue())
+			Expect(registryClient.UpdateSettings).To(Equal(expectedAgentSettings))
+		})
+
 		It("After creating the vm, /etc/hosts updated", func() {
 			vmCID, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
 			Expect(err).NotTo(HaveOccurred())
@@ -253,9 +287,9 @@ var _ = Describe("CreateVM", func() {
 			Expect(vmService.CleanUpCallCount()).To(Equal(0))
 			Expect(registryClient.UpdateCalled).To(BeTrue())
 			Expect(registryClient.UpdateSettings).To(Equal(expectedAgentSettings))
-			actualCid, _ := vmService.ConfigureNetworksArgsForCall(0)
+			actualCid, _, _ := vmService.ConfigureNetworksArgsForCall(0)
 			Expect(vmCID).To(Equal(VMCID(actualCid).String()))
-			_, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
+			_, _, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
 			Expect(actualInstanceNetworks).To(Equal(expectedInstanceNetworks))
 
 		})
@@ -350,9 +384,9 @@ var _ = Describe("CreateVM", func() {
 			Expect(registryClient.UpdateCalled).To(BeTrue())
 			Expect(vmService.FindCallCount()).To(Equal(0))
 			Expect(registryClient.UpdateSettings).To(Equal(expectedAgentSettings))
-			actualCid, _ := vmService.ConfigureNetworksArgsForCall(0)
+			actualCid, _, _ := vmService.ConfigureNetworksArgsForCall(0)
 			Expect(vmCID).To(Equal(VMCID(actualCid).String()))
-			_, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
+			_, _, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
 			Expect(actualInstanceNetworks).To(Equal(expectedInstanceNetworks))
 		})
 
@@ -432,9 +466,9 @@ var _ = Describe("CreateVM", func() {
 			Expect(registryClient.UpdateCalled).To(BeTrue())
 			Expect(vmService.FindCallCount()).To(Equal(0))
 			Expect(registryClient.UpdateSettings).To(Equal(expectedAgentSettings))
-			actualCid, _ := vmService.ConfigureNetworksArgsForCall(0)
+			actualCid, _, _ := vmService.ConfigureNetworksArgsForCall(0)
 			Expect(vmCID).To(Equal(VMCID(actualCid).String()))
-			_, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
+			_, _, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
 			Expect(actualInstanceNetworks).To(Equal(expectedInstanceNetworks))
 		})
 
@@ -464,9 +498,9 @@ var _ = Describe("CreateVM", func() {
 			Expect(registryClient.UpdateCalled).To(BeTrue())
 			Expect(vmService.FindCallCount()).To(Equal(0))
 			Expect(registryClient.UpdateSettings).To(Equal(expectedAgentSettings))
-			actualCid, _ := vmService.ConfigureNetworksArgsForCall(0)
+			actualCid, _, _ := vmService.ConfigureNetworksArgsForCall(0)
 			Expect(vmCID).To(Equal(VMCID(actualCid).String()))
-			_, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
+			_, _, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
 			Expect(actualInstanceNetworks).To(Equal(expectedInstanceNetworks))
 		})
 
@@ -564,9 +598,9 @@ var _ = Describe("CreateVM", func() {
 			Expect(registryClient.UpdateCalled).To(BeTrue())
 			Expect(vmService.FindCallCount()).To(Equal(0))
 			Expect(registryClient.UpdateSettings).To(Equal(expectedAgentSettings))
-			actualCid, _ := vmService.ConfigureNetworksArgsForCall(0)
+			actualCid, _, _ := vmService.ConfigureNetworksArgsForCall(0)
 			Expect(vmCID).To(Equal(VMCID(actualCid).String()))
-			_, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
+			_, _, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
 			Expect(actualInstanceNetworks).To(Equal(expectedInstanceNetworks))
 		})
 
@@ -612,6 +646,29 @@ var _ = Describe("CreateVM", func() {
 			Expect(registryClient.UpdateCalled).To(BeFalse())
 		})
 
+		It("configures the vm networks for the os code of the stemcell", func() {
+			imageService.FindOsCodeReturns("CENTOS_7_64", nil)
+
+			vmCID, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(imageService.FindOsCodeCallCount()).To(Equal(1))
+			Expect(imageService.FindOsCodeArgsForCall(0)).To(Equal(stemcellCID.Int()))
+			Expect(vmService.ConfigureNetworksCallCount()).To(Equal(1))
+			_, osCode, _ := vmService.ConfigureNetworksArgsForCall(0)
+			Expect(osCode).To(Equal("CENTOS_7_64"))
+		})
+
+		It("returns an error if imageService find os code call returns an error", func() {
+			imageService.FindOsCodeReturns("", errors.New("fake-image-service-error"))
+
+			vmCID, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Finding os code of stemcell with id"))
+			Expect(vmService.ReloadOSCallCount()).To(Equal(0))
+			Expect(vmService.CreateCallCount()).To(Equal(0))
+			Expect(vmService.ConfigureNetworksCallCount()).To(Equal(0))
+		})
+
 		It("returns an error if vmService get vlan call returns an error", func() {
 			vmService.GetVlanReturns(
 				&datatypes.Network_Vlan{},
@@ -752,9 +809,9 @@ var _ = Describe("CreateVM", func() {
 				Expect(vmService.CleanUpCallCount()).To(Equal(0))
 				Expect(registryClient.UpdateCalled).To(BeTrue())
 				Expect(registryClient.UpdateSettings).To(Equal(expectedAgentSettings))
-				actualCid, _ := vmService.ConfigureNetworksArgsForCall(0)
+				actualCid, _, _ := vmService.ConfigureNetworksArgsForCall(0)
 				Expect(vmCID).To(Equal(VMCID(actualCid).String()))
-				_, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
+				_, _, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
 				Expect(actualInstanceNetworks).To(Equal(expectedInstanceNetworks))
 			})
 
@@ -840,8 +897,8 @@ var _ = Describe("CreateVM", func() {
 			It("creates the vm with only private network", func() {
 				vmCID, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
 				virtualGuest, _, _, _, _ := vmService.CreateArgsForCall(0)
-				actualCid, _ := vmService.ConfigureNetworksArgsForCall(0)
-				_, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
+				actualCid, _, _ := vmService.ConfigureNetworksArgsForCall(0)
+				_, _, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
 
 				Expect(err).NotTo(HaveOccurred())
 				Expect(imageService.FindCallCount()).To(Equal(1))
@@ -898,6 +955,310 @@ var _ = Describe("CreateVM", func() {
 				Expect(registryClient.UpdateCalled).To(BeFalse())
 			})
 
+			Context("when networks have more than one private vlan", func() {
+				BeforeEach(func() {
+					vmService.GetVlanStub = func(id int, mask string) (*datatypes.Network_Vlan, error) {
+						return &datatypes.Network_Vlan{
+							Id:           sl.Int(id),
+							NetworkSpace: sl.String("PRIVATE"),
+						}, nil
+					}
+				})
+
+				It("returns an error if a dynamic network has more than one private vlan", func() {
+					networks["fake-network-name"] = Network{
+						Type: "dynamic",
+						CloudProperties: NetworkCloudProperties{
+							VlanIds: []int{42345678, 42345679},
+						},
+					}
+
+					_, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("only one private VLAN is supported"))
+					Expect(vmService.CreateCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when manual networks have static ips", func() {
+				BeforeEach(func() {
+					networks["fake-manual-network"] = Network{
+						Type:    "manual",
+						IP:      "10.20.10.10",
+						Gateway: "10.20.10.1",
+						Netmask: "255.255.255.0",
+						CloudProperties: NetworkCloudProperties{
+							VlanIds: []int{42345678},
+						},
+					}
+					vmService.FindStaticIpReturns(
+						datatypes.Network_Subnet_IpAddress{
+							Id:        sl.Int(10776597),
+							IpAddress: sl.String("10.20.10.10"),
+						},
+						nil,
+					)
+				})
+
+				It("reserves the static ips before creating the vm and assigns them to the vm", func() {
+					vmService.ReserveStaticIpsStub = func(string, []datatypes.Network_Subnet_IpAddress) error {
+						Expect(vmService.CreateCallCount()).To(Equal(0))
+						return nil
+					}
+
+					_, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(vmService.FindStaticIpCallCount()).To(Equal(1))
+					ip, vlanIds, subnetIds := vmService.FindStaticIpArgsForCall(0)
+					Expect(ip).To(Equal("10.20.10.10"))
+					Expect(vlanIds).To(Equal([]int{42345678}))
+					Expect(subnetIds).To(BeEmpty())
+
+					Expect(vmService.ReserveStaticIpsCallCount()).To(Equal(1))
+					reservingAgentID, reservedIpAddresses := vmService.ReserveStaticIpsArgsForCall(0)
+					Expect(reservingAgentID).To(Equal(agentID))
+					Expect(reservedIpAddresses).To(Equal([]datatypes.Network_Subnet_IpAddress{
+						{Id: sl.Int(10776597), IpAddress: sl.String("10.20.10.10")},
+					}))
+					Expect(vmService.ReleaseReservedStaticIpsCallCount()).To(Equal(0))
+
+					Expect(vmService.AssignStaticIpsCallCount()).To(Equal(1))
+					actualCid, actualIpAddresses := vmService.AssignStaticIpsArgsForCall(0)
+					Expect(actualCid).To(Equal(62345678))
+					Expect(actualIpAddresses).To(Equal([]datatypes.Network_Subnet_IpAddress{
+						{Id: sl.Int(10776597), IpAddress: sl.String("10.20.10.10")},
+					}))
+				})
+
+				It("returns an error before creating the vm if a static ip is not available", func() {
+					vmService.FindStaticIpReturns(
+						datatypes.Network_Subnet_IpAddress{},
+						errors.New("Ip '10.20.10.10' is already assigned to virtual guest '22345678'"),
+					)
+
+					_, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("Validating static IPs"))
+					Expect(err.Error()).To(ContainSubstring("already assigned"))
+					Expect(vmService.CreateCallCount()).To(Equal(0))
+				})
+
+				It("returns an error before creating the vm if vmService reserve static ips returns an error", func() {
+					vmService.ReserveStaticIpsReturns(errors.New("fake-vm-service-error"))
+
+					_, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("Reserving static IPs"))
+					Expect(vmService.CreateCallCount()).To(Equal(0))
+				})
+
+				It("returns an error and releases the reserved static ips if vmService create returns an error", func() {
+					vmService.CreateReturns(0, errors.New("fake-vm-service-error"))
+
+					_, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+					Expect(err).To(HaveOccurred())
+					Expect(vmService.ReleaseReservedStaticIpsCallCount()).To(Equal(1))
+					Expect(vmService.ReleaseReservedStaticIpsArgsForCall(0)).To(Equal(agentID))
+					Expect(vmService.AssignStaticIpsCallCount()).To(Equal(0))
+				})
+
+				It("returns an error, releases the static ips and cleans up if vmService assign static ips returns an error", func() {
+					vmService.AssignStaticIpsReturns(errors.New("fake-vm-service-error"))
+
+					_, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("Assigning static IPs to VM"))
+					Expect(vmService.ReleaseReservedStaticIpsCallCount()).To(Equal(1))
+					Expect(vmService.ReleaseStaticIpsCallCount()).To(Equal(1))
+					Expect(vmService.CleanUpCallCount()).To(Equal(1))
+					Expect(registryClient.UpdateCalled).To(BeFalse())
+				})
+			})
+
+			Context("when networks have a vip network", func() {
+				BeforeEach(func() {
+					networks["fake-vip-network"] = Network{
+						Type: "vip",
+						IP:   "169.50.100.10",
+					}
+				})
+
+				It("routes the global ip to the vm when there is a public network", func() {
+					networks["fake-public-network"] = Network{
+						Type: "dynamic",
+						CloudProperties: NetworkCloudProperties{
+							VlanIds: []int{42345680},
+						},
+					}
+					vmService.GetVlanStub = func(id int, mask string) (*datatypes.Network_Vlan, error) {
+						networkSpace := "PRIVATE"
+						if id == 42345680 {
+							networkSpace = "PUBLIC"
+						}
+						return &datatypes.Network_Vlan{
+							Id:           sl.Int(id),
+							NetworkSpace: sl.String(networkSpace),
+						}, nil
+					}
+
+					_, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(vmService.RouteGlobalIpCallCount()).To(Equal(1))
+					actualCid, actualIp := vmService.RouteGlobalIpArgsForCall(0)
+					Expect(actualCid).To(Equal(62345678))
+					Expect(actualIp).To(Equal("169.50.100.10"))
+
+					_, _, actualNetworks := vmService.ConfigureNetworksArgsForCall(0)
+					Expect(actualNetworks["fake-vip-network"].Type).To(Equal("vip"))
+					Expect(actualNetworks["fake-vip-network"].IP).To(Equal("169.50.100.10"))
+				})
+
+				It("returns an error before creating the vm if there is no public network", func() {
+					_, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("vip networks need a public dynamic network"))
+					Expect(vmService.CreateCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when networks use IPv6", func() {
+				BeforeEach(func() {
+					networks["fake-public-network"] = Network{
+						Type: "dynamic",
+						CloudProperties: NetworkCloudProperties{
+							VlanIds: []int{42345680},
+						},
+					}
+					vmService.GetVlanStub = func(id int, mask string) (*datatypes.Network_Vlan, error) {
+						if id != 42345680 {
+							return &datatypes.Network_Vlan{
+								Id:           sl.Int(id),
+								NetworkSpace: sl.String("PRIVATE"),
+							}, nil
+						}
+						return &datatypes.Network_Vlan{
+							Id:           sl.Int(id),
+							NetworkSpace: sl.String("PUBLIC"),
+							Subnets: []datatypes.Network_Subnet{
+								{
+									Version:           sl.Int(6),
+									SubnetType:        sl.String("PRIMARY_6"),
+									NetworkIdentifier: sl.String("2607:f0d0:1:2::"),
+									Cidr:              sl.Int(64),
+								},
+								{
+									Version:           sl.Int(6),
+									SubnetType:        sl.String("SUBNET_ON_VLAN"),
+									NetworkIdentifier: sl.String("2607:f0d0:1:3::"),
+									Cidr:              sl.Int(64),
+								},
+							},
+						}, nil
+					}
+					vmService.HasPrimaryIpv6PriceReturns(true, nil)
+				})
+
+				It("orders the primary IPv6 address before configuring networks", func() {
+					cloudProps.PrimaryIpv6Address = true
+
+					_, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(vmService.HasPrimaryIpv6PriceCallCount()).To(Equal(1))
+					Expect(vmService.AttachPrimaryIpv6AddressCallCount()).To(Equal(1))
+					Expect(vmService.AttachPrimaryIpv6AddressArgsForCall(0)).To(Equal(62345678))
+					Expect(vmService.ConfigureNetworksCallCount()).To(Equal(1))
+				})
+
+				It("returns an error if primary_ipv6_address is set without a public network", func() {
+					cloudProps.PrimaryIpv6Address = true
+					delete(networks, "fake-public-network")
+
+					_, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("primary_ipv6_address needs a public dynamic network"))
+					Expect(vmService.CreateCallCount()).To(Equal(0))
+				})
+
+				It("returns an error before creating the vm if no primary IPv6 address price is offered", func() {
+					cloudProps.PrimaryIpv6Address = true
+					vmService.HasPrimaryIpv6PriceReturns(false, nil)
+
+					_, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("primary_ipv6_address cannot be ordered"))
+					Expect(vmService.CreateCallCount()).To(Equal(0))
+					Expect(vmService.AttachPrimaryIpv6AddressCallCount()).To(Equal(0))
+				})
+
+				It("returns an error before creating the vm if vmService finding the primary IPv6 address price returns an error", func() {
+					cloudProps.PrimaryIpv6Address = true
+					vmService.HasPrimaryIpv6PriceReturns(false, errors.New("fake-vm-service-error"))
+
+					_, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("fake-vm-service-error"))
+					Expect(vmService.CreateCallCount()).To(Equal(0))
+				})
+
+				It("returns an error and cleans up if vmService attach primary ipv6 address returns an error", func() {
+					cloudProps.PrimaryIpv6Address = true
+					vmService.AttachPrimaryIpv6AddressReturns(errors.New("fake-vm-service-error"))
+
+					_, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("Ordering primary IPv6 address"))
+					Expect(vmService.ConfigureNetworksCallCount()).To(Equal(0))
+					Expect(vmService.CleanUpCallCount()).To(Equal(1))
+				})
+
+				It("accepts manual IPv6 networks on a portable subnet of the public vlan", func() {
+					networks["fake-ipv6-network"] = Network{
+						Type:    "manual",
+						IP:      "2607:f0d0:1:3::10",
+						Gateway: "2607:f0d0:1:3::1",
+						Netmask: "ffff:ffff:ffff:ffff::",
+						CloudProperties: NetworkCloudProperties{
+							VlanIds: []int{42345680},
+						},
+					}
+
+					_, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("returns an error if a manual IPv6 network is not on a portable subnet", func() {
+					networks["fake-ipv6-network"] = Network{
+						Type:    "manual",
+						IP:      "2607:f0d0:1:2::10",
+						Gateway: "2607:f0d0:1:2::1",
+						CloudProperties: NetworkCloudProperties{
+							VlanIds: []int{42345680},
+						},
+					}
+
+					_, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("ip 2607:f0d0:1:2::10 is not in a portable IPv6 subnet"))
+					Expect(vmService.CreateCallCount()).To(Equal(0))
+				})
+
+				It("returns an error if the netmask of a manual IPv6 network does not match its subnet", func() {
+					networks["fake-ipv6-network"] = Network{
+						Type:    "manual",
+						IP:      "2607:f0d0:1:3::10",
+						Netmask: "ffff:ffff:ffff:ffff:ffff::",
+						CloudProperties: NetworkCloudProperties{
+							VlanIds: []int{42345680},
+						},
+					}
+
+					_, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("does not match the /64 prefix"))
+				})
+			})
+
 			It("returns an error if vmService create call returns an error", func() {
 				vmService.CreateReturns(
 					0,
@@ -1042,9 +1403,9 @@ var _ = Describe("CreateVM", func() {
 				Expect(registryClient.UpdateCalled).To(BeTrue())
 				Expect(vmService.CleanUpCallCount()).To(Equal(0))
 				Expect(registryClient.UpdateSettings).To(Equal(expectedAgentSettings))
-				actualCid, _ := vmService.ConfigureNetworksArgsForCall(0)
+				actualCid, _, _ := vmService.ConfigureNetworksArgsForCall(0)
 				Expect(vmCID).To(Equal(VMCID(actualCid).String()))
-				_, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
+				_, _, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
 				Expect(actualInstanceNetworks).To(Equal(expectedInstanceNetworks))
 			})
 
@@ -1069,6 +1430,109 @@ var _ = Describe("CreateVM", func() {
 			})
 		})
 
+		Context("when dry_run is set in the request context", func() {
+			BeforeEach(func() {
+				softlayerOptions.PublicKey = "fake-public-key"
+				createVM = NewCreateVM(
+					imageService,
+					vmService,
+					registryClient,
+					registryOptions,
+					agentOptions,
+					softlayerOptions,
+				).WithContext(CallContext{DryRun: true}).(CreateVM)
+
+				vmService.VerifyCreateReturns(
+					bosl.OrderReport{Verified: true, HourlyRecurringFee: 0.1},
+					nil,
+				)
+			})
+
+			It("verifies the virtual guest template instead of creating the vm", func() {
+				vmCID, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(vmCID).To(Equal(bosl.OrderReport{Verified: true, HourlyRecurringFee: 0.1}))
+
+				Expect(vmService.VerifyCreateCallCount()).To(Equal(1))
+				template := vmService.VerifyCreateArgsForCall(0)
+				Expect(*template.BlockDeviceTemplateGroup.GlobalIdentifier).To(Equal("12345678"))
+				Expect(*template.Datacenter.Name).To(Equal("fake-datacenter"))
+				Expect(*template.StartCpus).To(Equal(2))
+				Expect(*template.PrimaryBackendNetworkComponent.NetworkVlan.Id).To(Equal(42345678))
+
+				Expect(vmService.CreateSshKeyCallCount()).To(Equal(0))
+				Expect(vmService.FindByPrimaryBackendIpCallCount()).To(Equal(0))
+				Expect(vmService.ReloadOSCallCount()).To(Equal(0))
+				Expect(vmService.CreateCallCount()).To(Equal(0))
+				Expect(vmService.ConfigureNetworksCallCount()).To(Equal(0))
+				Expect(registryClient.UpdateCalled).To(BeFalse())
+			})
+
+			It("reports invalid cloud properties as a rejected order", func() {
+				cloudProps.Datacenter = ""
+
+				vmCID, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+				Expect(err).NotTo(HaveOccurred())
+				report := vmCID.(bosl.OrderReport)
+				Expect(report.Verified).To(BeFalse())
+				Expect(report.Errors).To(ConsistOf(ContainSubstring("The property 'datacenter' must be set to create an instance")))
+				Expect(vmService.VerifyCreateCallCount()).To(Equal(0))
+			})
+
+			It("reports unknown vlans as a rejected order", func() {
+				vmService.GetVlanReturns(
+					&datatypes.Network_Vlan{},
+					errors.New("fake-vlan-not-found"),
+				)
+
+				vmCID, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+				Expect(err).NotTo(HaveOccurred())
+				report := vmCID.(bosl.OrderReport)
+				Expect(report.Verified).To(BeFalse())
+				Expect(report.Errors).To(ConsistOf(ContainSubstring("fake-vlan-not-found")))
+				Expect(vmService.VerifyCreateCallCount()).To(Equal(0))
+			})
+
+			It("reports a primary IPv6 address without a price as a rejected order", func() {
+				cloudProps.PrimaryIpv6Address = true
+				networks["fake-public-network"] = Network{
+					Type: "dynamic",
+					CloudProperties: NetworkCloudProperties{
+						VlanIds: []int{42345680},
+					},
+				}
+				vmService.GetVlanStub = func(id int, mask string) (*datatypes.Network_Vlan, error) {
+					networkSpace := "PRIVATE"
+					if id == 42345680 {
+						networkSpace = "PUBLIC"
+					}
+					return &datatypes.Network_Vlan{
+						Id:           sl.Int(id),
+						NetworkSpace: sl.String(networkSpace),
+					}, nil
+				}
+				vmService.HasPrimaryIpv6PriceReturns(false, nil)
+
+				vmCID, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+				Expect(err).NotTo(HaveOccurred())
+				report := vmCID.(bosl.OrderReport)
+				Expect(report.Verified).To(BeFalse())
+				Expect(report.Errors).To(ConsistOf(ContainSubstring("primary_ipv6_address cannot be ordered")))
+				Expect(vmService.VerifyCreateCallCount()).To(Equal(0))
+			})
+
+			It("returns an error if vmService verifyCreate call returns an error", func() {
+				vmService.VerifyCreateReturns(
+					bosl.OrderReport{},
+					errors.New("fake-vm-service-error"),
+				)
+
+				_, err = createVM.Run(agentID, stemcellCID, cloudProps, networks, disks, env)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-vm-service-error"))
+			})
+		})
+
 		Context("when required cloud properties is not set", func() {
 			It("returns an error if property 'vmNamePrefix' is not set", func() {
 				cloudProps = VMCloudProperties{
@@ -1199,9 +1663,9 @@ var _ = Describe("CreateVM", func() {
 				Expect(registryClient.UpdateCalled).To(BeTrue())
 				Expect(vmService.FindCallCount()).To(Equal(0))
 				Expect(registryClient.UpdateSettings).To(BeEquivalentTo(expectedAgentSettings))
-				actualCid, _ := vmService.ConfigureNetworksArgsForCall(0)
+				actualCid, _, _ := vmService.ConfigureNetworksArgsForCall(0)
 				Expect(vmCID).To(Equal(VMCID(actualCid).String()))
-				_, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
+				_, _, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
 				Expect(actualInstanceNetworks).To(BeEquivalentTo(expectedInstanceNetworks))
 			})
 		})
@@ -1280,9 +1744,9 @@ var _ = Describe("CreateVM", func() {
 				Expect(registryClient.UpdateCalled).To(BeTrue())
 				Expect(vmService.FindCallCount()).To(Equal(0))
 				Expect(registryClient.UpdateSettings).To(BeEquivalentTo(expectedAgentSettings))
-				actualCid, _ := vmService.ConfigureNetworksArgsForCall(0)
+				actualCid, _, _ := vmService.ConfigureNetworksArgsForCall(0)
 				Expect(vmCID).To(Equal(VMCID(actualCid).String()))
-				_, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
+				_, _, actualInstanceNetworks := vmService.ConfigureNetworksArgsForCall(0)
 				Expect(actualInstanceNetworks).To(BeEquivalentTo(expectedInstanceNetworks))
 			})
 		})
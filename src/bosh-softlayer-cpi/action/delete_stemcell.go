@@ -1,6 +1,9 @@
 package action
 
 import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	"bosh-softlayer-cpi/api"
 	stemcell_service "bosh-softlayer-cpi/softlayer/stemcell_service"
 )
 
@@ -16,5 +19,13 @@ func NewDeleteStemcell(
 }
 
 func (a DeleteStemcellAction) Run(stemcellCID StemcellCID) (interface{}, error) {
+	err := a.stemcellService.Delete(stemcellCID.Int())
+	if err != nil {
+		if _, ok := err.(api.CloudError); ok {
+			return nil, err
+		}
+		return nil, bosherr.WrapErrorf(err, "Deleting stemcell '%s'", stemcellCID)
+	}
+
 	return nil, nil
 }
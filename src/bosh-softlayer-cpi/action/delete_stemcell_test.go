@@ -1,6 +1,8 @@
 package action_test
 
 import (
+	"errors"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -29,6 +31,17 @@ var _ = Describe("DeleteStemcell", func() {
 		It("deletes the stemcell", func() {
 			_, err = deleteStemcell.Run(stemcellID)
 			Expect(err).NotTo(HaveOccurred())
+			Expect(imageService.DeleteCallCount()).To(Equal(1))
+			Expect(imageService.DeleteArgsForCall(0)).To(Equal(12345678))
+		})
+
+		It("returns an error if stemcellService delete call returns an error", func() {
+			imageService.DeleteReturns(errors.New("fake-stemcell-service-error"))
+
+			_, err = deleteStemcell.Run(stemcellID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-stemcell-service-error"))
+			Expect(imageService.DeleteCallCount()).To(Equal(1))
 		})
 	})
 })
@@ -27,12 +27,29 @@ func NewDeleteVM(
 }
 
 func (dv DeleteVMAction) Run(vmCID VMCID) (interface{}, error) {
+	// Unroute the global IPs of vip networks, so that they can be routed to another VM
+	if err := dv.vmService.UnrouteGlobalIps(vmCID.Int()); err != nil {
+		if _, ok := err.(api.CloudError); !ok {
+			return nil, bosherr.WrapErrorf(err, "Unrouting global ips of vm '%s'", vmCID)
+		}
+	}
+
 	// Delete the VM
+	vmFound := true
 	if err := dv.vmService.Delete(vmCID.Int(), dv.softlayerOptions.EnableVps); err != nil {
-		if _, ok := err.(api.CloudError); ok {
-			return nil, nil
+		if _, ok := err.(api.CloudError); !ok {
+			return nil, bosherr.WrapErrorf(err, "Deleting vm '%s'", vmCID)
 		}
-		return nil, bosherr.WrapErrorf(err, "Deleting vm '%s'", vmCID)
+		vmFound = false
+	}
+
+	// Free the static IPs of manual networks only once the VM is gone, so that no other VM gets them meanwhile
+	if err := dv.vmService.ReleaseStaticIps(vmCID.Int()); err != nil {
+		return nil, bosherr.WrapErrorf(err, "Releasing static ips of vm '%s'", vmCID)
+	}
+
+	if !vmFound {
+		return nil, nil
 	}
 
 	// Delete the VM agent settings
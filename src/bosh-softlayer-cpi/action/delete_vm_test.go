@@ -50,6 +50,47 @@ var _ = Describe("DeleteVM", func() {
 			Expect(registryClient.DeleteCalled).To(BeTrue())
 		})
 
+		It("releases the static ips of the vm once it is deleted", func() {
+			vmService.ReleaseStaticIpsStub = func(int) error {
+				Expect(vmService.DeleteCallCount()).To(Equal(1))
+				return nil
+			}
+
+			_, err = deleteVM.Run(vmCID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmService.ReleaseStaticIpsCallCount()).To(Equal(1))
+			Expect(vmService.ReleaseStaticIpsArgsForCall(0)).To(Equal(12345678))
+		})
+
+		It("returns an error if vmService release static ips call returns an error", func() {
+			vmService.ReleaseStaticIpsReturns(
+				errors.New("fake-vm-service-error"),
+			)
+
+			_, err = deleteVM.Run(vmCID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Releasing static ips of vm '12345678'"))
+			Expect(registryClient.DeleteCalled).To(BeFalse())
+		})
+
+		It("unroutes the global ips of the vm", func() {
+			_, err = deleteVM.Run(vmCID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vmService.UnrouteGlobalIpsCallCount()).To(Equal(1))
+			Expect(vmService.UnrouteGlobalIpsArgsForCall(0)).To(Equal(12345678))
+		})
+
+		It("returns an error if vmService unroute global ips call returns an error", func() {
+			vmService.UnrouteGlobalIpsReturns(
+				errors.New("fake-vm-service-error"),
+			)
+
+			_, err = deleteVM.Run(vmCID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Unrouting global ips of vm '12345678'"))
+			Expect(vmService.DeleteCallCount()).To(Equal(0))
+		})
+
 		It("returns an error if vmService delete call returns an error", func() {
 			vmService.DeleteReturns(
 				errors.New("fake-vm-service-error"),
@@ -59,10 +100,11 @@ var _ = Describe("DeleteVM", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("fake-vm-service-error"))
 			Expect(vmService.DeleteCallCount()).To(Equal(1))
+			Expect(vmService.ReleaseStaticIpsCallCount()).To(Equal(0))
 			Expect(registryClient.DeleteCalled).To(BeFalse())
 		})
 
-		It("return nil if vmService delete call returns an api error", func() {
+		It("return nil and releases the static ips if vmService delete call returns an api error", func() {
 			vmService.DeleteReturns(
 				api.NewVMNotFoundError(vmCID.String()),
 			)
@@ -70,6 +112,8 @@ var _ = Describe("DeleteVM", func() {
 			_, err = deleteVM.Run(vmCID)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(vmService.DeleteCallCount()).To(Equal(1))
+			Expect(vmService.ReleaseStaticIpsCallCount()).To(Equal(1))
+			Expect(registryClient.DeleteCalled).To(BeFalse())
 		})
 
 		It("returns an error if registryClient delete call returns an error", func() {
@@ -10,6 +10,8 @@ import (
 type FakeFactory struct {
 	registeredActions    map[string]*FakeAction
 	registeredActionErrs map[string]error
+
+	CreateContext bgcaction.CallContext
 }
 
 func NewFakeFactory() *FakeFactory {
@@ -19,7 +21,8 @@ func NewFakeFactory() *FakeFactory {
 	}
 }
 
-func (f *FakeFactory) Create(method string) (bgcaction.Action, error) {
+func (f *FakeFactory) Create(method string, context bgcaction.CallContext) (bgcaction.Action, error) {
+	f.CreateContext = context
 	if err := f.registeredActionErrs[method]; err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package action
 
 type InfoResult struct {
+	ApiVersion      int      `json:"api_version"`
 	StemcellFormats []string `json:"stemcell_formats"`
 }
 
@@ -10,6 +11,7 @@ func NewInfo() Info { return Info{} }
 
 func (Info) Run() (InfoResult, error) {
 	return InfoResult{
+		ApiVersion: MaxSupportedApiVersion,
 		StemcellFormats: []string{
 			"softlayer-light",
 			"softlayer-ovf",
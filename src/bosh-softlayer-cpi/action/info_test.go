@@ -30,5 +30,11 @@ var _ = Describe("Info", func() {
 			})
 
 		})
+
+		It("advertises the supported CPI API version", func() {
+			response, err := info.Run()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.ApiVersion).To(Equal(2))
+		})
 	})
 })
@@ -6,6 +6,7 @@ import (
 	"bosh-softlayer-cpi/softlayer/virtual_guest_service"
 	"fmt"
 	"github.com/softlayer/softlayer-go/datatypes"
+	"sort"
 )
 
 const (
@@ -38,6 +39,17 @@ func (ns Networks) AsInstanceServiceNetworks(publicNetworkVlan *datatypes.Networ
 	return networks
 }
 
+// sortedNames lists the network names in order, so that the first VLAN of a kind is always the same one
+func (ns Networks) sortedNames() []string {
+	names := make([]string, 0, len(ns))
+	for name := range ns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 func (ns Networks) HasManualNetwork() bool {
 	for _, network := range ns {
 		if network.IsManual() {
@@ -53,6 +65,16 @@ func (n Network) IsManual() bool {
 }
 
 func parseCloudProperties(networks instance.Networks, netName string, network Network, publicNetworkVlan *datatypes.Network_Vlan) {
+	if network.Type == "vip" {
+		// The global IP of a vip network is routed to the public network of the VM
+		networks[netName] = instance.Network{
+			Type: network.Type,
+			IP:   network.IP,
+			DNS:  network.DNS,
+		}
+		return
+	}
+
 	if len(network.CloudProperties.SubnetIds) > 0 {
 		for index, subnetId := range network.CloudProperties.SubnetIds {
 			var newNetName string
@@ -67,6 +89,7 @@ func parseCloudProperties(networks instance.Networks, netName string, network Ne
 					CloudProperties: instance.NetworkCloudProperties{
 						SubnetID:            subnetId,
 						SourcePolicyRouting: network.CloudProperties.SourcePolicyRouting,
+						Routes:              network.CloudProperties.Routes,
 					},
 					Default: network.Default,
 				}
@@ -84,6 +107,7 @@ func parseCloudProperties(networks instance.Networks, netName string, network Ne
 					DNS:     network.DNS,
 					CloudProperties: instance.NetworkCloudProperties{
 						SubnetID: subnetId,
+						Routes:   network.CloudProperties.Routes,
 					},
 				}
 			}
@@ -102,6 +126,7 @@ func parseCloudProperties(networks instance.Networks, netName string, network Ne
 					CloudProperties: instance.NetworkCloudProperties{
 						VlanID:              vlanId,
 						SourcePolicyRouting: network.CloudProperties.SourcePolicyRouting,
+						Routes:              network.CloudProperties.Routes,
 					},
 					Default: network.Default,
 				}
@@ -119,6 +144,7 @@ func parseCloudProperties(networks instance.Networks, netName string, network Ne
 					DNS:     network.DNS,
 					CloudProperties: instance.NetworkCloudProperties{
 						VlanID: vlanId,
+						Routes: network.CloudProperties.Routes,
 					},
 				}
 			}
@@ -114,5 +114,23 @@ var _ = Describe("Networks", func() {
 			ret := networks.HasManualNetwork()
 			Expect(ret).To(BeFalse())
 		})
+
+		It("Generate InstanceServiceNetworks with the routes of the networks", func() {
+			networks := Networks{
+				"fake-network-name": Network{
+					Type: "manual",
+					IP:   "10.20.10.10",
+					CloudProperties: NetworkCloudProperties{
+						VlanIds: []int{42345679},
+						Routes:  []string{"192.168.10.0/24"},
+					},
+				},
+			}
+			instanceNetworks := networks.AsInstanceServiceNetworks(publicVlan)
+			Expect(instanceNetworks["fake-network-name"].CloudProperties.Routes).To(Equal([]string{"192.168.10.0/24"}))
+
+			networks["fake-network-name"].CloudProperties.Routes[0] = "192.168.10.0"
+			Expect(networks.AsInstanceServiceNetworks(publicVlan).Validate()).To(MatchError(ContainSubstring("Route destination '192.168.10.0' is not a CIDR")))
+		})
 	})
 })
@@ -0,0 +1,32 @@
+package action
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	"bosh-softlayer-cpi/api"
+	"bosh-softlayer-cpi/softlayer/disk_service"
+)
+
+type ResizeDisk struct {
+	diskService disk.Service
+}
+
+func NewResizeDisk(
+	diskService disk.Service,
+) ResizeDisk {
+	return ResizeDisk{
+		diskService: diskService,
+	}
+}
+
+func (rd ResizeDisk) Run(diskCID DiskCID, newSize int) (interface{}, error) {
+	err := rd.diskService.Resize(diskCID.Int(), newSize)
+	if err != nil {
+		if _, ok := err.(api.CloudError); ok {
+			return nil, err
+		}
+		return nil, bosherr.WrapErrorf(err, "Resizing disk '%s' to size '%d'", diskCID, newSize)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,58 @@
+package action_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "bosh-softlayer-cpi/action"
+
+	"bosh-softlayer-cpi/api"
+	diskfakes "bosh-softlayer-cpi/softlayer/disk_service/fakes"
+)
+
+var _ = Describe("ResizeDisk", func() {
+	var (
+		err     error
+		diskCID DiskCID
+
+		diskService *diskfakes.FakeService
+
+		resizeDisk ResizeDisk
+	)
+
+	BeforeEach(func() {
+		diskCID = DiskCID(22345678)
+		diskService = &diskfakes.FakeService{}
+		resizeDisk = NewResizeDisk(diskService)
+	})
+
+	Describe("Run", func() {
+		It("resizes the disk", func() {
+			_, err = resizeDisk.Run(diskCID, 40960)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diskService.ResizeCallCount()).To(Equal(1))
+			actualID, actualSize := diskService.ResizeArgsForCall(0)
+			Expect(actualID).To(Equal(22345678))
+			Expect(actualSize).To(Equal(40960))
+		})
+
+		It("returns a NotSupported error if the disk can not be resized in place", func() {
+			diskService.ResizeReturns(api.NotSupportedError{})
+
+			_, err = resizeDisk.Run(diskCID, 40960)
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(Equal(api.NotSupportedError{}))
+		})
+
+		It("returns an error if diskService resize call returns an error", func() {
+			diskService.ResizeReturns(errors.New("fake-disk-service-error"))
+
+			_, err = resizeDisk.Run(diskCID, 40960)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-disk-service-error"))
+			Expect(diskService.ResizeCallCount()).To(Equal(1))
+		})
+	})
+})
@@ -8,7 +8,8 @@ import (
 )
 
 type SetDiskMetadata struct {
-	diskService disk.Service
+	diskService  disk.Service
+	directorUUID string
 }
 
 func NewSetDiskMetadata(
@@ -19,7 +20,16 @@ func NewSetDiskMetadata(
 	}
 }
 
+func (sdm SetDiskMetadata) WithContext(context CallContext) Action {
+	sdm.directorUUID = context.DirectorUUID
+	return sdm
+}
+
 func (sdm SetDiskMetadata) Run(DiskCID DiskCID, diskMetadata DiskMetadata) (interface{}, error) {
+	if sdm.directorUUID != "" {
+		diskMetadata["director_uuid"] = sdm.directorUUID
+	}
+
 	if err := sdm.diskService.SetMetadata(DiskCID.Int(), disk.Metadata(diskMetadata)); err != nil {
 		if _, ok := err.(api.CloudError); ok {
 			return nil, err
@@ -47,6 +47,15 @@ var _ = Describe("SetVMMetadata", func() {
 			Expect(actualMetadata).To(Equal(disk.Metadata(diskMetadata)))
 		})
 
+		It("stamps the director uuid from the request context into the disk metadata", func() {
+			setDiskMetadata = setDiskMetadata.WithContext(CallContext{DirectorUUID: "fake-director-uuid"}).(SetDiskMetadata)
+
+			_, err = setDiskMetadata.Run(diskCID, diskMetadata)
+			Expect(err).NotTo(HaveOccurred())
+			_, actualMetadata := diskService.SetMetadataArgsForCall(0)
+			Expect(actualMetadata).To(HaveKeyWithValue("director_uuid", "fake-director-uuid"))
+		})
+
 		It("returns an error if vmService set metadata call returns an error", func() {
 			diskService.SetMetadataReturns(
 				errors.New("fake-vm-service-error"),
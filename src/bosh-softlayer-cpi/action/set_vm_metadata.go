@@ -8,7 +8,8 @@ import (
 )
 
 type SetVMMetadata struct {
-	vmService instance.Service
+	vmService    instance.Service
+	directorUUID string
 }
 
 func NewSetVMMetadata(
@@ -19,7 +20,16 @@ func NewSetVMMetadata(
 	}
 }
 
+func (svm SetVMMetadata) WithContext(context CallContext) Action {
+	svm.directorUUID = context.DirectorUUID
+	return svm
+}
+
 func (svm SetVMMetadata) Run(vmCID VMCID, vmMetadata VMMetadata) (interface{}, error) {
+	if svm.directorUUID != "" {
+		vmMetadata["director_uuid"] = svm.directorUUID
+	}
+
 	if err := svm.vmService.SetMetadata(vmCID.Int(), instance.Metadata(vmMetadata)); err != nil {
 		if _, ok := err.(api.CloudError); ok {
 			return nil, err
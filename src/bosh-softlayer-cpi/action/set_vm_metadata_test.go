@@ -50,6 +50,15 @@ var _ = Describe("SetVMMetadata", func() {
 			Expect(actualMetadata).To(Equal(instance.Metadata(vmMetadata)))
 		})
 
+		It("tags the vm with the director uuid from the request context", func() {
+			setVMMetadata = setVMMetadata.WithContext(CallContext{DirectorUUID: "fake-director-uuid"}).(SetVMMetadata)
+
+			_, err = setVMMetadata.Run(vmCID, vmMetadata)
+			Expect(err).NotTo(HaveOccurred())
+			_, actualMetadata := vmService.SetMetadataArgsForCall(0)
+			Expect(actualMetadata).To(HaveKeyWithValue("director_uuid", "fake-director-uuid"))
+		})
+
 		It("returns an error if vmService set metadata call returns an error", func() {
 			vmService.SetMetadataReturns(
 				errors.New("fake-vm-service-error"),
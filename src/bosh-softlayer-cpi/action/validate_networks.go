@@ -0,0 +1,346 @@
+package action
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"github.com/softlayer/softlayer-go/datatypes"
+
+	boslc "bosh-softlayer-cpi/softlayer/client"
+	instance "bosh-softlayer-cpi/softlayer/virtual_guest_service"
+	"bosh-softlayer-cpi/util"
+)
+
+// maxStaticIps bounds the addresses a single static range of the cloud config may expand to
+const maxStaticIps = 65536
+
+// CloudConfig holds the sections of a cloud config that validate_networks checks
+type CloudConfig struct {
+	AZs      []CloudConfigAZ      `json:"azs,omitempty"`
+	Networks []CloudConfigNetwork `json:"networks"`
+}
+
+type CloudConfigAZ struct {
+	Name            string                `json:"name"`
+	CloudProperties AvailabilityZoneProps `json:"cloud_properties"`
+}
+
+type AvailabilityZoneProps struct {
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+type CloudConfigNetwork struct {
+	Name    string              `json:"name"`
+	Type    string              `json:"type"`
+	Subnets []CloudConfigSubnet `json:"subnets,omitempty"`
+
+	// Dynamic networks may set their cloud properties on the network instead of on subnets
+	CloudProperties NetworkCloudProperties `json:"cloud_properties,omitempty"`
+}
+
+type CloudConfigSubnet struct {
+	Range           string                 `json:"range,omitempty"`
+	Static          []string               `json:"static,omitempty"`
+	AZ              string                 `json:"az,omitempty"`
+	AZs             []string               `json:"azs,omitempty"`
+	CloudProperties NetworkCloudProperties `json:"cloud_properties,omitempty"`
+}
+
+// NetworksReport is the result of validate_networks, with one check per VLAN, subnet and list of
+// static IPs of the cloud config networks
+type NetworksReport struct {
+	Valid  bool           `json:"valid"`
+	Checks []NetworkCheck `json:"checks"`
+}
+
+type NetworkCheck struct {
+	Network string `json:"network"`
+	Range   string `json:"range,omitempty"`
+	// Kind is "vlan", "subnet", "static_ips" or "network" for errors in the cloud config itself
+	Kind string `json:"kind"`
+	Id   int    `json:"id,omitempty"`
+
+	Datacenter   string `json:"datacenter,omitempty"`
+	NetworkSpace string `json:"network_space,omitempty"`
+	StaticIps    int    `json:"static_ips,omitempty"`
+	UsableIps    int    `json:"usable_ips,omitempty"`
+
+	Passed bool     `json:"passed"`
+	Errors []string `json:"errors"`
+}
+
+func (c *NetworkCheck) fail(format string, args ...interface{}) {
+	c.Passed = false
+	c.Errors = append(c.Errors, fmt.Sprintf(format, args...))
+}
+
+type ValidateNetworks struct {
+	vmService instance.Service
+}
+
+func NewValidateNetworks(
+	vmService instance.Service,
+) ValidateNetworks {
+	return ValidateNetworks{
+		vmService: vmService,
+	}
+}
+
+// Run checks the VLANs and subnets of the cloud config networks before anything is deployed on
+// them. Problems are reported in the checks rather than returned as errors.
+func (vn ValidateNetworks) Run(cloudConfig CloudConfig) (NetworksReport, error) {
+	datacenters := map[string]string{}
+	for _, az := range cloudConfig.AZs {
+		datacenters[az.Name] = az.CloudProperties.Datacenter
+	}
+
+	report := NetworksReport{Valid: true, Checks: []NetworkCheck{}}
+	for _, nw := range cloudConfig.Networks {
+		subnets := nw.Subnets
+		if len(subnets) == 0 {
+			subnets = []CloudConfigSubnet{{CloudProperties: nw.CloudProperties}}
+		}
+
+		for _, subnet := range subnets {
+			report.Checks = append(report.Checks, vn.validateSubnet(nw, subnet, datacenters)...)
+		}
+	}
+
+	for _, check := range report.Checks {
+		if !check.Passed {
+			report.Valid = false
+		}
+	}
+
+	return report, nil
+}
+
+func (vn ValidateNetworks) validateSubnet(nw CloudConfigNetwork, subnet CloudConfigSubnet, datacenters map[string]string) []NetworkCheck {
+	configCheck := NetworkCheck{Network: nw.Name, Range: subnet.Range, Kind: "network", Passed: true, Errors: []string{}}
+
+	switch nw.Type {
+	case "manual", "dynamic", "":
+	case "vip":
+		// Global IPs are not tied to a VLAN or a datacenter
+		return nil
+	default:
+		configCheck.fail("Unknown network type '%s'", nw.Type)
+		return []NetworkCheck{configCheck}
+	}
+
+	// Datacenters the VLANs and subnets have to be in, none when the AZs do not state one
+	expectedDatacenters := map[string]bool{}
+	for _, az := range append([]string{subnet.AZ}, subnet.AZs...) {
+		if az == "" {
+			continue
+		}
+		datacenter, found := datacenters[az]
+		if !found {
+			configCheck.fail("Unknown az '%s'", az)
+			continue
+		}
+		if datacenter != "" {
+			expectedDatacenters[datacenter] = true
+		}
+	}
+
+	// Manual networks have to be on VLANs of the same network space as their range
+	var ipRange *net.IPNet
+	expectedSpace := ""
+	if subnet.Range != "" {
+		_, parsed, err := net.ParseCIDR(subnet.Range)
+		if err != nil {
+			configCheck.fail("Range '%s' is not a CIDR", subnet.Range)
+		} else {
+			ipRange = parsed
+			expectedSpace = "PUBLIC"
+			if util.IsPrivateSubnet(ipRange.IP) {
+				expectedSpace = "PRIVATE"
+			}
+		}
+	}
+
+	if len(subnet.CloudProperties.VlanIds) == 0 && len(subnet.CloudProperties.SubnetIds) == 0 {
+		configCheck.fail("No vlan_ids or subnet_ids in cloud_properties")
+	}
+
+	checks := []NetworkCheck{}
+	var portableSubnets []datatypes.Network_Subnet
+
+	for _, vlanId := range subnet.CloudProperties.VlanIds {
+		check := NetworkCheck{Network: nw.Name, Range: subnet.Range, Kind: "vlan", Id: vlanId, Passed: true, Errors: []string{}}
+
+		vlan, err := vn.vmService.GetVlan(vlanId, boslc.NETWORK_PREFLIGHT_VLAN_MASK)
+		if err != nil {
+			check.fail("%s", err.Error())
+			checks = append(checks, check)
+			continue
+		}
+
+		if vlan.PrimaryRouter != nil && vlan.PrimaryRouter.Datacenter != nil && vlan.PrimaryRouter.Datacenter.Name != nil {
+			check.Datacenter = *vlan.PrimaryRouter.Datacenter.Name
+		}
+		if vlan.NetworkSpace != nil {
+			check.NetworkSpace = *vlan.NetworkSpace
+		}
+		validateLocation(&check, expectedDatacenters, expectedSpace)
+
+		for _, vlanSubnet := range vlan.Subnets {
+			if instance.IsPortableSubnet(vlanSubnet) {
+				portableSubnets = append(portableSubnets, vlanSubnet)
+			}
+		}
+		checks = append(checks, check)
+	}
+
+	for _, subnetId := range subnet.CloudProperties.SubnetIds {
+		check := NetworkCheck{Network: nw.Name, Range: subnet.Range, Kind: "subnet", Id: subnetId, Passed: true, Errors: []string{}}
+
+		slSubnet, err := vn.vmService.GetSubnet(subnetId, boslc.NETWORK_PREFLIGHT_SUBNET_MASK)
+		if err != nil {
+			check.fail("%s", err.Error())
+			checks = append(checks, check)
+			continue
+		}
+
+		if slSubnet.Datacenter != nil && slSubnet.Datacenter.Name != nil {
+			check.Datacenter = *slSubnet.Datacenter.Name
+		}
+		if slSubnet.AddressSpace != nil {
+			check.NetworkSpace = *slSubnet.AddressSpace
+		}
+		validateLocation(&check, expectedDatacenters, expectedSpace)
+
+		if instance.IsPortableSubnet(*slSubnet) {
+			portableSubnets = append(portableSubnets, *slSubnet)
+		} else if len(subnet.Static) > 0 {
+			check.fail("Subnet '%d' is not a portable subnet and cannot hold static ips", subnetId)
+		}
+		checks = append(checks, check)
+	}
+
+	if nw.Type == "manual" && len(subnet.Static) > 0 {
+		checks = append(checks, validateStaticIps(nw.Name, subnet, ipRange, portableSubnets))
+	}
+
+	if !configCheck.Passed {
+		checks = append([]NetworkCheck{configCheck}, checks...)
+	}
+
+	return checks
+}
+
+func validateLocation(check *NetworkCheck, expectedDatacenters map[string]bool, expectedSpace string) {
+	if len(expectedDatacenters) > 0 && !expectedDatacenters[check.Datacenter] {
+		check.fail("%s '%d' is in datacenter '%s', not in the datacenter of its az", strings.Title(check.Kind), check.Id, check.Datacenter)
+	}
+
+	switch {
+	case expectedSpace != "" && check.NetworkSpace != expectedSpace:
+		check.fail("%s '%d' is on the %s network, the range is %s", strings.Title(check.Kind), check.Id, check.NetworkSpace, expectedSpace)
+	case check.NetworkSpace != "PRIVATE" && check.NetworkSpace != "PUBLIC":
+		check.fail("%s '%d' has unknown network space '%s'", strings.Title(check.Kind), check.Id, check.NetworkSpace)
+	}
+}
+
+// validateStaticIps checks that every static IP of the subnet is a free address of one of its portable subnets,
+// neither reserved in SoftLayer nor taken by another VM
+func validateStaticIps(network string, subnet CloudConfigSubnet, ipRange *net.IPNet, portableSubnets []datatypes.Network_Subnet) NetworkCheck {
+	check := NetworkCheck{Network: network, Range: subnet.Range, Kind: "static_ips", Passed: true, Errors: []string{}}
+
+	usable := map[string]bool{}
+	for _, portableSubnet := range portableSubnets {
+		for _, ipAddress := range portableSubnet.IpAddresses {
+			if ipAddress.IpAddress == nil {
+				continue
+			}
+			ip := net.ParseIP(*ipAddress.IpAddress)
+			if ip == nil || (ipRange != nil && !ipRange.Contains(ip)) {
+				continue
+			}
+			if instance.IsTrue(ipAddress.IsNetwork) || instance.IsTrue(ipAddress.IsGateway) || instance.IsTrue(ipAddress.IsBroadcast) || instance.IsTrue(ipAddress.IsReserved) {
+				continue
+			}
+			// Addresses the CPI already reserved or assigned to a VM are taken
+			if instance.IsStaticIpInUse(ipAddress) {
+				continue
+			}
+			usable[ip.String()] = true
+		}
+	}
+	check.UsableIps = len(usable)
+
+	staticIps, err := expandStaticIps(subnet.Static)
+	if err != nil {
+		check.fail("%s", err.Error())
+		return check
+	}
+	check.StaticIps = len(staticIps)
+
+	if check.StaticIps > check.UsableIps {
+		check.fail("%d static ips declared, only %d usable ips in the portable subnets", check.StaticIps, check.UsableIps)
+	}
+	for _, ip := range staticIps {
+		if ipRange != nil && !ipRange.Contains(ip) {
+			check.fail("Static ip '%s' is not in range '%s'", ip, subnet.Range)
+		} else if !usable[ip.String()] {
+			check.fail("Static ip '%s' is not a usable ip of the portable subnets", ip)
+		}
+	}
+
+	return check
+}
+
+// expandStaticIps turns the static IPs of a cloud config subnet, single IPv4 or IPv6 addresses or
+// ranges such as "10.0.0.10 - 10.0.0.20", into a list of addresses
+func expandStaticIps(entries []string) ([]net.IP, error) {
+	var ips []net.IP
+
+	for _, entry := range entries {
+		bounds := strings.Split(entry, "-")
+		first := parseStaticIp(bounds[0])
+		last := first
+		if len(bounds) == 2 {
+			last = parseStaticIp(bounds[1])
+		}
+		if len(bounds) > 2 || first == nil || last == nil || len(first) != len(last) || bytes.Compare(first, last) > 0 {
+			return nil, bosherr.Errorf("Static ip '%s' is not an ip address or range", entry)
+		}
+
+		for ip := first; bytes.Compare(ip, last) <= 0; ip = nextIp(ip) {
+			if len(ips) == maxStaticIps {
+				return nil, bosherr.Errorf("More than %d static ips", maxStaticIps)
+			}
+			ips = append(ips, ip)
+		}
+	}
+
+	return ips, nil
+}
+
+// parseStaticIp parses an IPv4 address to its 4 bytes form and an IPv6 address to its 16 bytes one,
+// so that both bounds of a range compare byte by byte
+func parseStaticIp(s string) net.IP {
+	ip := net.ParseIP(strings.TrimSpace(s))
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4
+	}
+
+	return ip
+}
+
+func nextIp(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+
+	return next
+}
@@ -0,0 +1,272 @@
+package action_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/sl"
+
+	. "bosh-softlayer-cpi/action"
+
+	boslc "bosh-softlayer-cpi/softlayer/client"
+	instance "bosh-softlayer-cpi/softlayer/virtual_guest_service"
+	instancefakes "bosh-softlayer-cpi/softlayer/virtual_guest_service/fakes"
+)
+
+var _ = Describe("ValidateNetworks", func() {
+	var (
+		vmService *instancefakes.FakeService
+
+		cloudConfig      CloudConfig
+		validateNetworks ValidateNetworks
+	)
+
+	BeforeEach(func() {
+		vmService = &instancefakes.FakeService{}
+		validateNetworks = NewValidateNetworks(vmService)
+
+		vmService.GetVlanStub = func(id int, mask string) (*datatypes.Network_Vlan, error) {
+			switch id {
+			case 1292653:
+				return &datatypes.Network_Vlan{
+					Id:            sl.Int(id),
+					NetworkSpace:  sl.String("PRIVATE"),
+					PrimaryRouter: &datatypes.Hardware_Router{Hardware_Switch: datatypes.Hardware_Switch{Hardware: datatypes.Hardware{Datacenter: &datatypes.Location{Name: sl.String("lon02")}}}},
+					Subnets: []datatypes.Network_Subnet{
+						{
+							Id:                sl.Int(514990),
+							SubnetType:        sl.String("SECONDARY_ON_VLAN"),
+							NetworkIdentifier: sl.String("10.40.207.168"),
+							Cidr:              sl.Int(29),
+							IpAddresses: []datatypes.Network_Subnet_IpAddress{
+								{IpAddress: sl.String("10.40.207.168"), IsNetwork: sl.Bool(true)},
+								{IpAddress: sl.String("10.40.207.169"), IsGateway: sl.Bool(true)},
+								{IpAddress: sl.String("10.40.207.170")},
+								{IpAddress: sl.String("10.40.207.171")},
+								{IpAddress: sl.String("10.40.207.172"), IsReserved: sl.Bool(true)},
+							},
+						},
+					},
+				}, nil
+			case 1292654:
+				return &datatypes.Network_Vlan{
+					Id:            sl.Int(id),
+					NetworkSpace:  sl.String("PUBLIC"),
+					PrimaryRouter: &datatypes.Hardware_Router{Hardware_Switch: datatypes.Hardware_Switch{Hardware: datatypes.Hardware{Datacenter: &datatypes.Location{Name: sl.String("lon02")}}}},
+				}, nil
+			}
+			return &datatypes.Network_Vlan{}, errors.New("Failed to get vlan details")
+		}
+
+		cloudConfig = CloudConfig{
+			AZs: []CloudConfigAZ{
+				{Name: "z1", CloudProperties: AvailabilityZoneProps{Datacenter: "lon02"}},
+				{Name: "z2", CloudProperties: AvailabilityZoneProps{Datacenter: "dal10"}},
+			},
+			Networks: []CloudConfigNetwork{
+				{
+					Name: "default",
+					Type: "manual",
+					Subnets: []CloudConfigSubnet{
+						{
+							Range:  "10.40.207.168/29",
+							Static: []string{"10.40.207.170 - 10.40.207.171"},
+							AZ:     "z1",
+							CloudProperties: NetworkCloudProperties{
+								VlanIds: []int{1292653},
+							},
+						},
+					},
+				},
+				{
+					Name: "dynamic",
+					Type: "dynamic",
+					CloudProperties: NetworkCloudProperties{
+						VlanIds: []int{1292654, 1292653},
+					},
+				},
+			},
+		}
+	})
+
+	Describe("Run", func() {
+		It("reports valid networks", func() {
+			report, err := validateNetworks.Run(cloudConfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Valid).To(BeTrue())
+			Expect(report.Checks).To(HaveLen(4))
+			Expect(report.Checks[0]).To(Equal(NetworkCheck{
+				Network:      "default",
+				Range:        "10.40.207.168/29",
+				Kind:         "vlan",
+				Id:           1292653,
+				Datacenter:   "lon02",
+				NetworkSpace: "PRIVATE",
+				Passed:       true,
+				Errors:       []string{},
+			}))
+			Expect(report.Checks[1]).To(Equal(NetworkCheck{
+				Network:   "default",
+				Range:     "10.40.207.168/29",
+				Kind:      "static_ips",
+				StaticIps: 2,
+				UsableIps: 2,
+				Passed:    true,
+				Errors:    []string{},
+			}))
+
+			_, mask := vmService.GetVlanArgsForCall(0)
+			Expect(mask).To(Equal(boslc.NETWORK_PREFLIGHT_VLAN_MASK))
+		})
+
+		It("reports vlans that do not exist", func() {
+			cloudConfig.Networks[0].Subnets[0].CloudProperties.VlanIds = []int{1292600}
+
+			report, err := validateNetworks.Run(cloudConfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Valid).To(BeFalse())
+			Expect(report.Checks[0].Passed).To(BeFalse())
+			Expect(report.Checks[0].Errors).To(ConsistOf(ContainSubstring("Failed to get vlan details")))
+		})
+
+		It("reports vlans in another datacenter than the az", func() {
+			cloudConfig.Networks[0].Subnets[0].AZ = "z2"
+
+			report, err := validateNetworks.Run(cloudConfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Valid).To(BeFalse())
+			Expect(report.Checks[0].Errors).To(ConsistOf("Vlan '1292653' is in datacenter 'lon02', not in the datacenter of its az"))
+		})
+
+		It("reports vlans on another network space than the range", func() {
+			cloudConfig.Networks[0].Subnets[0].Range = "169.50.10.0/29"
+			cloudConfig.Networks[0].Subnets[0].Static = nil
+
+			report, err := validateNetworks.Run(cloudConfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Valid).To(BeFalse())
+			Expect(report.Checks[0].Errors).To(ConsistOf("Vlan '1292653' is on the PRIVATE network, the range is PUBLIC"))
+		})
+
+		It("reports static ips without room in the portable subnets", func() {
+			cloudConfig.Networks[0].Subnets[0].Static = []string{"10.40.207.170 - 10.40.207.172"}
+
+			report, err := validateNetworks.Run(cloudConfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Valid).To(BeFalse())
+			Expect(report.Checks[1].StaticIps).To(Equal(3))
+			Expect(report.Checks[1].UsableIps).To(Equal(2))
+			Expect(report.Checks[1].Errors).To(ConsistOf(
+				"3 static ips declared, only 2 usable ips in the portable subnets",
+				"Static ip '10.40.207.172' is not a usable ip of the portable subnets",
+			))
+		})
+
+		It("reports static ips already taken by other vms", func() {
+			vmService.GetVlanStub = func(id int, mask string) (*datatypes.Network_Vlan, error) {
+				return &datatypes.Network_Vlan{
+					Id:            sl.Int(id),
+					NetworkSpace:  sl.String("PRIVATE"),
+					PrimaryRouter: &datatypes.Hardware_Router{Hardware_Switch: datatypes.Hardware_Switch{Hardware: datatypes.Hardware{Datacenter: &datatypes.Location{Name: sl.String("lon02")}}}},
+					Subnets: []datatypes.Network_Subnet{
+						{
+							Id:                sl.Int(514990),
+							SubnetType:        sl.String("SECONDARY_ON_VLAN"),
+							NetworkIdentifier: sl.String("10.40.207.168"),
+							Cidr:              sl.Int(29),
+							IpAddresses: []datatypes.Network_Subnet_IpAddress{
+								{IpAddress: sl.String("10.40.207.170"), Note: sl.String(instance.StaticIpNote(22345678))},
+								{IpAddress: sl.String("10.40.207.171"), Note: sl.String("kept by the network team")},
+							},
+						},
+					},
+				}, nil
+			}
+			cloudConfig.Networks = cloudConfig.Networks[:1]
+
+			report, err := validateNetworks.Run(cloudConfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Valid).To(BeFalse())
+			Expect(report.Checks[1].UsableIps).To(Equal(1))
+			Expect(report.Checks[1].Errors).To(ConsistOf(
+				"2 static ips declared, only 1 usable ips in the portable subnets",
+				"Static ip '10.40.207.170' is not a usable ip of the portable subnets",
+			))
+		})
+
+		It("checks the static ips of ipv6 networks", func() {
+			vmService.GetSubnetStub = func(id int, mask string) (*datatypes.Network_Subnet, error) {
+				return &datatypes.Network_Subnet{
+					Id:                sl.Int(id),
+					AddressSpace:      sl.String("PUBLIC"),
+					SubnetType:        sl.String("SUBNET_ON_VLAN"),
+					NetworkIdentifier: sl.String("2607:f0d0:1002:51::"),
+					Cidr:              sl.Int(64),
+					Datacenter:        &datatypes.Location_Datacenter{Location: datatypes.Location{Name: sl.String("lon02")}},
+					IpAddresses: []datatypes.Network_Subnet_IpAddress{
+						{IpAddress: sl.String("2607:f0d0:1002:51::"), IsNetwork: sl.Bool(true)},
+						{IpAddress: sl.String("2607:f0d0:1002:51::1"), IsGateway: sl.Bool(true)},
+						{IpAddress: sl.String("2607:f0d0:1002:51::a")},
+						{IpAddress: sl.String("2607:f0d0:1002:51::b")},
+					},
+				}, nil
+			}
+			cloudConfig.Networks = []CloudConfigNetwork{
+				{
+					Name: "ipv6",
+					Type: "manual",
+					Subnets: []CloudConfigSubnet{
+						{
+							Range:  "2607:f0d0:1002:51::/64",
+							Static: []string{"2607:f0d0:1002:51::a - 2607:f0d0:1002:51::c"},
+							AZ:     "z1",
+							CloudProperties: NetworkCloudProperties{
+								SubnetIds: []int{1563278},
+							},
+						},
+					},
+				},
+			}
+
+			report, err := validateNetworks.Run(cloudConfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Valid).To(BeFalse())
+			Expect(report.Checks[0].Passed).To(BeTrue())
+			Expect(report.Checks[1].StaticIps).To(Equal(3))
+			Expect(report.Checks[1].UsableIps).To(Equal(2))
+			Expect(report.Checks[1].Errors).To(ConsistOf(
+				"3 static ips declared, only 2 usable ips in the portable subnets",
+				"Static ip '2607:f0d0:1002:51::c' is not a usable ip of the portable subnets",
+			))
+
+			_, mask := vmService.GetSubnetArgsForCall(0)
+			Expect(mask).To(Equal(boslc.NETWORK_PREFLIGHT_SUBNET_MASK))
+		})
+
+		It("reports static ip ranges mixing ipv4 and ipv6", func() {
+			cloudConfig.Networks[0].Subnets[0].Static = []string{"10.40.207.170 - 2607:f0d0:1002:51::c"}
+
+			report, err := validateNetworks.Run(cloudConfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Valid).To(BeFalse())
+			Expect(report.Checks[1].Errors).To(ConsistOf("Static ip '10.40.207.170 - 2607:f0d0:1002:51::c' is not an ip address or range"))
+		})
+
+		It("reports errors in the cloud config itself", func() {
+			cloudConfig.Networks[0].Subnets[0].AZ = "z3"
+			cloudConfig.Networks[0].Subnets[0].CloudProperties.VlanIds = nil
+
+			report, err := validateNetworks.Run(cloudConfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Valid).To(BeFalse())
+			Expect(report.Checks[0].Kind).To(Equal("network"))
+			Expect(report.Checks[0].Errors).To(ConsistOf(
+				"Unknown az 'z3'",
+				"No vlan_ids or subnet_ids in cloud_properties",
+			))
+		})
+	})
+})
@@ -3,6 +3,9 @@ package dispatcher
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 
 	bslaction "bosh-softlayer-cpi/action"
 	bslapi "bosh-softlayer-cpi/api"
@@ -21,7 +24,90 @@ type Request struct {
 	Method    string        `json:"method"`
 	Arguments []interface{} `json:"arguments"`
 
-	// context key is ignored
+	Context    RequestContext `json:"context"`
+	ApiVersion int            `json:"api_version"`
+}
+
+type RequestContext struct {
+	DirectorUUID string                  `json:"director_uuid"`
+	RequestID    string                  `json:"request_id"`
+	VM           RequestContextVM        `json:"vm"`
+	SoftLayer    RequestContextSoftLayer `json:"softlayer"`
+	DryRun       bool                    `json:"dry_run"`
+}
+
+type RequestContextVM struct {
+	Stemcell RequestContextStemcell `json:"stemcell"`
+}
+
+type RequestContextStemcell struct {
+	ApiVersion int `json:"api_version"`
+}
+
+type RequestContextSoftLayer struct {
+	Username    string `json:"username"`
+	ApiKey      string `json:"api_key"`
+	ApiEndpoint string `json:"api_endpoint"`
+}
+
+// String keeps the API key out of the logged request
+func (r RequestContextSoftLayer) String() string {
+	apiKey := ""
+	if r.ApiKey != "" {
+		apiKey = "<redacted>"
+	}
+
+	return fmt.Sprintf("{%s %s %s}", r.Username, apiKey, r.ApiEndpoint)
+}
+
+func (r Request) CallContext() bslaction.CallContext {
+	return bslaction.CallContext{
+		ApiVersions:  bslaction.NewApiVersions(r.ApiVersion, r.Context.VM.Stemcell.ApiVersion),
+		RequestID:    r.Context.RequestID,
+		DirectorUUID: r.Context.DirectorUUID,
+		SoftLayer: bslaction.SoftLayerOverrides{
+			Username:    r.Context.SoftLayer.Username,
+			ApiKey:      r.Context.SoftLayer.ApiKey,
+			ApiEndpoint: r.Context.SoftLayer.ApiEndpoint,
+		},
+		DryRun: r.Context.DryRun,
+	}
+}
+
+// LogFields names the CPI method and the VM and disk CIDs its arguments refer to
+func (r Request) LogFields() logger.RequestFields {
+	fields := logger.RequestFields{Method: r.Method}
+	switch r.Method {
+	case "delete_vm", "has_vm", "reboot_vm", "set_vm_metadata", "configure_networks", "get_disks":
+		fields.VMCID = r.argument(0)
+	case "attach_disk", "detach_disk":
+		fields.VMCID = r.argument(0)
+		fields.DiskCID = r.argument(1)
+	case "create_disk":
+		fields.VMCID = r.argument(2)
+	case "delete_disk", "has_disk", "resize_disk", "set_disk_metadata", "snapshot_disk":
+		fields.DiskCID = r.argument(0)
+	}
+
+	return fields
+}
+
+func (r Request) argument(i int) string {
+	if i >= len(r.Arguments) || r.Arguments[i] == nil {
+		return ""
+	}
+
+	return fmt.Sprint(r.Arguments[i])
+}
+
+// createdCID returns the CID in the result of create_vm or create_disk, which API v2 puts first in a list
+func createdCID(result interface{}) string {
+	if list, ok := result.([]interface{}); ok && len(list) > 0 {
+		result = list[0]
+	}
+
+	cid, _ := result.(string)
+	return cid
 }
 
 type Response struct {
@@ -46,8 +132,12 @@ type JSON struct {
 	actionFactory bslaction.Factory
 	caller        Caller
 	logger        logger.Logger
+	requestLog    RequestLog
 }
 
+// RequestLog returns the log output of the current request to be included in the response
+type RequestLog func() string
+
 func NewJSON(
 	actionFactory bslaction.Factory,
 	caller Caller,
@@ -60,6 +150,12 @@ func NewJSON(
 	}
 }
 
+// WithRequestLog makes every response carry the log output of its request
+func (c JSON) WithRequestLog(requestLog RequestLog) JSON {
+	c.requestLog = requestLog
+	return c
+}
+
 func (c JSON) Dispatch(reqBytes []byte) []byte {
 	var req Request
 
@@ -70,6 +166,14 @@ func (c JSON) Dispatch(reqBytes []byte) []byte {
 		return c.buildCpiError("Must provide valid JSON payload")
 	}
 
+	// Correlate the rest of the log lines with the director request, the softlayer-go trace included
+	if req.Context.RequestID != "" {
+		c.logger.ChangeSerialTagPrefix(req.Context.RequestID)
+	}
+
+	fields := req.LogFields()
+	c.logger.ChangeRequestFields(fields)
+
 	c.logger.DebugWithDetails(jsonLogTag, "Deserialized request", req)
 
 	if req.Method == "" {
@@ -80,7 +184,7 @@ func (c JSON) Dispatch(reqBytes []byte) []byte {
 		return c.buildCpiError("Must provide arguments key")
 	}
 
-	action, err := c.actionFactory.Create(req.Method)
+	action, err := c.actionFactory.Create(req.Method, req.CallContext())
 	if err != nil {
 		return c.buildNotImplementedError()
 	}
@@ -90,8 +194,18 @@ func (c JSON) Dispatch(reqBytes []byte) []byte {
 		return c.buildCloudError(err)
 	}
 
+	switch req.Method {
+	case "create_vm":
+		fields.VMCID = createdCID(result)
+		c.logger.ChangeRequestFields(fields)
+	case "create_disk":
+		fields.DiskCID = createdCID(result)
+		c.logger.ChangeRequestFields(fields)
+	}
+
 	resp := Response{
 		Result: result,
+		Log:    c.log(),
 	}
 
 	c.logger.DebugWithDetails(jsonLogTag, "Deserialized response", resp)
@@ -109,18 +223,24 @@ func (c JSON) Dispatch(reqBytes []byte) []byte {
 func (c JSON) buildCloudError(err error) []byte {
 	respErr := Response{
 		Error: &ResponseError{},
+		Log:   c.log(),
 	}
 
-	if typedErr, ok := err.(bslapi.CloudError); ok {
-		respErr.Error.Type = typedErr.Type()
-	} else {
-		respErr.Error.Type = jsonCloudErrorType
+	respErr.Error.Type = jsonCloudErrorType
+	for _, cause := range c.errorCauses(err) {
+		if typedErr, ok := cause.(bslapi.CloudError); ok {
+			respErr.Error.Type = typedErr.Type()
+			break
+		}
 	}
 
 	respErr.Error.Message = err.Error()
 
-	if typedErr, ok := err.(bslapi.RetryableError); ok {
-		respErr.Error.CanRetry = typedErr.CanRetry()
+	for _, cause := range c.errorCauses(err) {
+		if typedErr, ok := cause.(bslapi.RetryableError); ok {
+			respErr.Error.CanRetry = typedErr.CanRetry()
+			break
+		}
 	}
 
 	respErrBytes, err := json.Marshal(respErr)
@@ -133,12 +253,37 @@ func (c JSON) buildCloudError(err error) []byte {
 	return respErrBytes
 }
 
+func (c JSON) log() string {
+	if c.requestLog == nil {
+		return ""
+	}
+
+	return c.requestLog()
+}
+
+// errorCauses returns err followed by the causes it wraps, outermost first
+func (c JSON) errorCauses(err error) []error {
+	causes := []error{}
+	for err != nil {
+		causes = append(causes, err)
+
+		complexErr, ok := err.(bosherr.ComplexError)
+		if !ok {
+			break
+		}
+		err = complexErr.Cause
+	}
+
+	return causes
+}
+
 func (c JSON) buildCpiError(message string) []byte {
 	respErr := Response{
 		Error: &ResponseError{
 			Type:    jsonCpiErrorType,
 			Message: message,
 		},
+		Log: c.log(),
 	}
 
 	respErrBytes, err := json.Marshal(respErr)
@@ -157,6 +302,7 @@ func (c JSON) buildNotImplementedError() []byte {
 			Type:    jsonNotImplementedErrorType,
 			Message: "Must call implemented method",
 		},
+		Log: c.log(),
 	}
 
 	respErrBytes, err := json.Marshal(respErr)
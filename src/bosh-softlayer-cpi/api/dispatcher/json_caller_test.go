@@ -7,6 +7,8 @@ import (
 	. "github.com/onsi/gomega"
 
 	. "bosh-softlayer-cpi/api/dispatcher"
+
+	bslapi "bosh-softlayer-cpi/api"
 )
 
 type valueType struct {
@@ -102,6 +104,20 @@ var _ = Describe("JSONCaller", func() {
 			Expect(action.SliceArgs).To(Equal([]string{"a", "b", "c"}))
 		})
 
+		It("returns typed errors from action unchanged", func() {
+			expectedErr := bslapi.NewVMCreationFailedError("fake-reason", true)
+
+			action := &actionWithGoodRunMethod{Err: expectedErr}
+
+			_, err := caller.Call(action, []interface{}{
+				"setup",
+				123,
+				map[string]interface{}{"user": "rob", "pwd": "rob123", "id": 12},
+				[]interface{}{"a", "b", "c"},
+			})
+			Expect(err).To(Equal(expectedErr))
+		})
+
 		It("returns error if actions not enough arguments", func() {
 			expectedValue := valueType{ID: 13, Success: true}
 
@@ -10,8 +10,10 @@ import (
 
 	. "bosh-softlayer-cpi/api/dispatcher"
 
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 
+	bslaction "bosh-softlayer-cpi/action"
 	fakeaction "bosh-softlayer-cpi/action/fakes"
 	bgcapi "bosh-softlayer-cpi/api"
 	fakedisp "bosh-softlayer-cpi/api/dispatcher/fakes"
@@ -68,6 +70,70 @@ var _ = Describe("JSON", func() {
 				}))
 			})
 
+			It("passes the request context and api version to the action factory", func() {
+				dispatcher.Dispatch([]byte(`{
+          "method":"fake-action",
+          "arguments":[],
+          "api_version":2,
+          "context":{"director_uuid":"fake-director-uuid","vm":{"stemcell":{"api_version":2}}}
+        }`))
+				Expect(actionFactory.CreateContext).To(Equal(bslaction.CallContext{
+					ApiVersions:  bslaction.ApiVersions{Contract: 2, Stemcell: 2},
+					DirectorUUID: "fake-director-uuid",
+				}))
+			})
+
+			It("passes the director uuid, request id and SoftLayer overrides to the action factory", func() {
+				dispatcher.Dispatch([]byte(`{
+          "method":"fake-action",
+          "arguments":[],
+          "context":{
+            "director_uuid":"fake-director-uuid",
+            "request_id":"fake-request-id",
+            "softlayer":{"username":"fake-username","api_key":"fake-api-key"}
+          }
+        }`))
+				Expect(actionFactory.CreateContext.DirectorUUID).To(Equal("fake-director-uuid"))
+				Expect(actionFactory.CreateContext.RequestID).To(Equal("fake-request-id"))
+				Expect(actionFactory.CreateContext.SoftLayer).To(Equal(bslaction.SoftLayerOverrides{
+					Username: "fake-username",
+					ApiKey:   "fake-api-key",
+				}))
+			})
+
+			It("passes the dry_run flag to the action factory", func() {
+				dispatcher.Dispatch([]byte(`{"method":"fake-action","arguments":[],"context":{"dry_run":true}}`))
+				Expect(actionFactory.CreateContext.DryRun).To(BeTrue())
+			})
+
+			It("prefixes log lines with the director request id", func() {
+				dispatcher.Dispatch([]byte(`{"method":"fake-action","arguments":[],"context":{"request_id":"fake-request-id"}}`))
+				Expect(logger.GetSerialTagPrefix()).To(Equal("fake-request-id"))
+			})
+
+			It("puts the CPI method and the CIDs it refers to in JSON log lines", func() {
+				var out bytes.Buffer
+				logger = cpiLog.NewJSONLogger(boshlog.LevelDebug, "", &out, &out)
+				dispatcher = NewJSON(actionFactory, caller, bgcapi.MultiLogger{Logger: logger, LogBuff: &bytes.Buffer{}})
+				actionFactory.RegisterAction("attach_disk", action)
+				actionFactory.RegisterAction("create_vm", action)
+
+				dispatcher.Dispatch([]byte(`{"method":"attach_disk","arguments":[1234,"5678"],"context":{"request_id":"fake-request-id"}}`))
+				Expect(out.String()).To(ContainSubstring(`"request_id":"fake-request-id","method":"attach_disk","vm_cid":"1234","disk_cid":"5678"`))
+
+				out.Reset()
+				caller.CallResult = []interface{}{"4321", map[string]interface{}{}}
+				dispatcher.Dispatch([]byte(`{"method":"create_vm","arguments":["fake-agent-id"],"api_version":2}`))
+				Expect(out.String()).To(ContainSubstring(`"method":"create_vm","vm_cid":"4321","message":"Deserialized response`))
+			})
+
+			It("defaults to API v1 when api_version is not provided", func() {
+				dispatcher.Dispatch([]byte(`{"method":"fake-action","arguments":[]}`))
+				Expect(actionFactory.CreateContext).To(Equal(bslaction.CallContext{
+					ApiVersions: bslaction.ApiVersions{Contract: 1, Stemcell: 1},
+				}))
+			})
+
 			Context("when running action succeeds", func() {
 				Context("when result can be serialized", func() {
 					BeforeEach(func() {
@@ -82,6 +148,17 @@ var _ = Describe("JSON", func() {
 							"log": ""
 						}`))
 					})
+
+					It("returns serialized result with the request log", func() {
+						dispatcher = dispatcher.WithRequestLog(func() string { return "fake-request-log" })
+
+						response := dispatcher.Dispatch([]byte(`{"method":"fake-action","arguments":["fake-arg"]}`))
+						Expect(response).To(MatchJSON(`{
+							"result": "fake-result",
+							"error": null,
+							"log": "fake-request-log"
+						}`))
+					})
 				})
 
 				Context("when result cannot be serialized", func() {
@@ -105,6 +182,26 @@ var _ = Describe("JSON", func() {
 			})
 
 			Context("when running action fails", func() {
+				Context("when the dispatcher has a request log", func() {
+					BeforeEach(func() {
+						caller.CallErr = errors.New("fake-run-err")
+						dispatcher = dispatcher.WithRequestLog(func() string { return "fake-request-log" })
+					})
+
+					It("returns error with the request log", func() {
+						response := dispatcher.Dispatch([]byte(`{"method":"fake-action","arguments":["fake-arg"]}`))
+						Expect(response).To(MatchJSON(`{
+							"result": null,
+              "error": {
+                "type":"Bosh::Clouds::CloudError",
+                "message":"fake-run-err",
+                "ok_to_retry": false
+              },
+              "log": "fake-request-log"
+            }`))
+					})
+				})
+
 				Context("when action error is a CloudError", func() {
 					BeforeEach(func() {
 						caller.CallErr = fakeapi.NewFakeCloudError("fake-type", "fake-message")
@@ -162,6 +259,25 @@ var _ = Describe("JSON", func() {
 					})
 				})
 
+				Context("when action error wraps a CloudError that can be retried", func() {
+					BeforeEach(func() {
+						caller.CallErr = bosherr.WrapError(bgcapi.NewVMCreationFailedError("fake-reason", true), "fake-wrapper")
+					})
+
+					It("returns error with the type of the cause and ok_to_retry set to true", func() {
+						response := dispatcher.Dispatch([]byte(`{"method":"fake-action","arguments":["fake-arg"]}`))
+						Expect(response).To(MatchJSON(`{
+							"result": null,
+              "error": {
+                "type":"Bosh::Clouds::VMCreationFailed",
+                "message":"fake-wrapper: VM failed to create: fake-reason",
+                "ok_to_retry": true
+              },
+              "log": ""
+            }`))
+					})
+				})
+
 				Context("when action error is neither CloudError or RetryableError", func() {
 					BeforeEach(func() {
 						caller.CallErr = errors.New("fake-run-err")
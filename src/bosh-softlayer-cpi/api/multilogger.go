@@ -2,11 +2,39 @@ package api
 
 import (
 	"bytes"
+	"fmt"
+	"strings"
 
 	"bosh-softlayer-cpi/logger"
 )
 
+// DefaultRequestLogMaxSize caps the log returned to the director when no size is configured
+const DefaultRequestLogMaxSize = 256 * 1024
+
 type MultiLogger struct {
 	logger.Logger
 	LogBuff *bytes.Buffer
 }
+
+// RequestLog returns the collected log with secrets redacted. When it is larger than maxSize bytes,
+// the oldest lines are dropped first.
+func (l MultiLogger) RequestLog(maxSize int) string {
+	if l.LogBuff == nil {
+		return ""
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultRequestLogMaxSize
+	}
+
+	log := logger.RedactSecrets(l.LogBuff.String())
+	if len(log) <= maxSize {
+		return log
+	}
+
+	truncated := log[len(log)-maxSize:]
+	if i := strings.Index(truncated, "\n"); i >= 0 && i < len(truncated)-1 {
+		truncated = truncated[i+1:]
+	}
+
+	return fmt.Sprintf("[truncated %d bytes]\n%s", len(log)-len(truncated), truncated)
+}
@@ -0,0 +1,53 @@
+package api_test
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+
+	. "bosh-softlayer-cpi/api"
+	cpiLog "bosh-softlayer-cpi/logger"
+)
+
+var _ = Describe("MultiLogger", func() {
+	var (
+		logBuff     *bytes.Buffer
+		multiLogger MultiLogger
+	)
+
+	BeforeEach(func() {
+		logBuff = &bytes.Buffer{}
+		multiLogger = MultiLogger{Logger: cpiLog.NewLogger(boshlog.LevelNone, ""), LogBuff: logBuff}
+	})
+
+	Describe("RequestLog", func() {
+		It("returns the collected log with secrets redacted", func() {
+			logBuff.WriteString("line 1\n")
+			logBuff.WriteString(`{"api_key":"fake-api-key"}` + "\n")
+
+			Expect(multiLogger.RequestLog(0)).To(Equal("line 1\n" + `{"api_key":"<redacted>"}` + "\n"))
+		})
+
+		It("drops the oldest lines when the log exceeds the size cap", func() {
+			logBuff.WriteString("line 1\nline 2\nline 3\n")
+
+			Expect(multiLogger.RequestLog(10)).To(Equal("[truncated 14 bytes]\nline 3\n"))
+		})
+
+		It("caps the log at the default size when no size is given", func() {
+			logBuff.WriteString(strings.Repeat("fake-log-line\n", DefaultRequestLogMaxSize/10))
+
+			Expect(len(multiLogger.RequestLog(0))).To(BeNumerically("<=", DefaultRequestLogMaxSize+len("[truncated 0000000 bytes]\n")))
+		})
+
+		It("returns an empty log without buffer", func() {
+			multiLogger.LogBuff = nil
+
+			Expect(multiLogger.RequestLog(0)).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,142 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"runtime/debug"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	bslcdisp "bosh-softlayer-cpi/api/dispatcher"
+	"bosh-softlayer-cpi/logger"
+)
+
+const socketLogTag = "Socket"
+
+// DispatcherFactory builds the dispatcher of a single request, with its own logger, response log
+// and metrics, so that requests can be served concurrently
+type DispatcherFactory func() bslcdisp.Dispatcher
+
+// Socket serves dispatchers over a Unix domain socket. A client writes one request, closes its
+// write side and reads the response until the connection is closed.
+type Socket struct {
+	path          string
+	newDispatcher DispatcherFactory
+	logger        logger.Logger
+}
+
+func NewSocket(
+	path string,
+	newDispatcher DispatcherFactory,
+	logger logger.Logger,
+) Socket {
+	return Socket{
+		path:          path,
+		newDispatcher: newDispatcher,
+		logger:        logger,
+	}
+}
+
+// Listen creates the socket, replacing a stale one left behind by a previous server
+func (t Socket) Listen() (net.Listener, error) {
+	if err := os.Remove(t.path); err != nil && !os.IsNotExist(err) {
+		return nil, bosherr.WrapErrorf(err, "Removing stale socket '%s'", t.path)
+	}
+
+	listener, err := net.Listen("unix", t.path)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Listening on socket '%s'", t.path)
+	}
+
+	if err = os.Chmod(t.path, 0600); err != nil {
+		listener.Close()
+		return nil, bosherr.WrapErrorf(err, "Restricting permissions of socket '%s'", t.path)
+	}
+
+	return listener, nil
+}
+
+// Serve handles connections until the listener is closed
+func (t Socket) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				t.logger.Warn(socketLogTag, "Accepting connection: %s", err)
+				continue
+			}
+			return bosherr.WrapError(err, "Accepting connection")
+		}
+
+		go t.serveConn(conn)
+	}
+}
+
+func (t Socket) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	reqBytes, err := ioutil.ReadAll(conn)
+	if err != nil {
+		t.logger.Error(socketLogTag, "Failed reading from connection: %s", err)
+		return
+	}
+
+	_, err = conn.Write(t.dispatch(reqBytes))
+	if err != nil {
+		t.logger.Error(socketLogTag, "Failed writing to connection: %s", err)
+	}
+}
+
+// dispatch serves the request with a new dispatcher. A panic only fails its own request, the
+// server keeps serving the others.
+func (t Socket) dispatch(reqBytes []byte) (respBytes []byte) {
+	defer func() {
+		if e := recover(); e != nil {
+			t.logger.ErrorWithDetails(socketLogTag, "Panic: %s", fmt.Sprint(e), debug.Stack())
+			respBytes = panicResponse(e)
+		}
+	}()
+
+	return t.newDispatcher().Dispatch(reqBytes)
+}
+
+func panicResponse(e interface{}) []byte {
+	respBytes, err := json.Marshal(bslcdisp.Response{
+		Error: &bslcdisp.ResponseError{
+			Type:    "Bosh::Clouds::CpiError",
+			Message: fmt.Sprintf("Panic: %s", e),
+		},
+	})
+	if err != nil {
+		return []byte{}
+	}
+
+	return respBytes
+}
+
+// Forward sends the request read from in to the server listening on path and writes its response to out
+func Forward(path string, in io.Reader, out io.Writer) error {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Connecting to socket '%s'", path)
+	}
+	defer conn.Close()
+
+	if _, err = io.Copy(conn, in); err != nil {
+		return bosherr.WrapError(err, "Writing request to socket")
+	}
+
+	if err = conn.(*net.UnixConn).CloseWrite(); err != nil {
+		return bosherr.WrapError(err, "Closing write side of socket")
+	}
+
+	if _, err = io.Copy(out, conn); err != nil {
+		return bosherr.WrapError(err, "Reading response from socket")
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package transport_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "bosh-softlayer-cpi/api/transport"
+
+	bslcdisp "bosh-softlayer-cpi/api/dispatcher"
+	fakedisp "bosh-softlayer-cpi/api/dispatcher/fakes"
+	cpilog "bosh-softlayer-cpi/logger"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+)
+
+type dispatcherFunc func([]byte) []byte
+
+func (f dispatcherFunc) Dispatch(reqBytes []byte) []byte {
+	return f(reqBytes)
+}
+
+var _ = Describe("Socket", func() {
+	var (
+		tmpDir        string
+		socketPath    string
+		dispatcher    *fakedisp.FakeDispatcher
+		newDispatcher DispatcherFactory
+		logger        cpilog.Logger
+		socket        Socket
+		listener      net.Listener
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "cpi-socket")
+		Expect(err).ToNot(HaveOccurred())
+		socketPath = filepath.Join(tmpDir, "cpi.sock")
+
+		dispatcher = &fakedisp.FakeDispatcher{}
+		newDispatcher = func() bslcdisp.Dispatcher { return dispatcher }
+		logger = cpilog.NewLogger(boshlog.LevelNone, "")
+		socket = NewSocket(socketPath, func() bslcdisp.Dispatcher { return newDispatcher() }, logger)
+	})
+
+	AfterEach(func() {
+		if listener != nil {
+			listener.Close()
+		}
+		os.RemoveAll(tmpDir)
+	})
+
+	Describe("Listen", func() {
+		It("creates the socket only accessible by its owner", func() {
+			var err error
+			listener, err = socket.Listen()
+			Expect(err).ToNot(HaveOccurred())
+
+			info, err := os.Stat(socketPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+		})
+
+		It("replaces a stale socket", func() {
+			err := ioutil.WriteFile(socketPath, []byte{}, 0600)
+			Expect(err).ToNot(HaveOccurred())
+
+			listener, err = socket.Listen()
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Describe("Serve", func() {
+		BeforeEach(func() {
+			var err error
+			listener, err = socket.Listen()
+			Expect(err).ToNot(HaveOccurred())
+
+			go socket.Serve(listener)
+		})
+
+		It("dispatches requests forwarded to the socket", func() {
+			dispatcher.DispatchRespBytes = []byte("fake-bytes-out")
+
+			out := &bytes.Buffer{}
+			err := Forward(socketPath, strings.NewReader("fake-bytes-in"), out)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(dispatcher.DispatchReqBytes).To(Equal([]byte("fake-bytes-in")))
+			Expect(out.String()).To(Equal("fake-bytes-out"))
+		})
+
+		It("serves several requests", func() {
+			for _, req := range []string{"fake-bytes-in-1", "fake-bytes-in-2"} {
+				dispatcher.DispatchRespBytes = []byte(req + "-out")
+
+				out := &bytes.Buffer{}
+				err := Forward(socketPath, strings.NewReader(req), out)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(out.String()).To(Equal(req + "-out"))
+			}
+		})
+
+		It("dispatches each request with a new dispatcher", func() {
+			dispatched := 0
+			newDispatcher = func() bslcdisp.Dispatcher {
+				dispatched++
+				return dispatcher
+			}
+
+			for i := 0; i < 2; i++ {
+				err := Forward(socketPath, strings.NewReader("fake-bytes-in"), &bytes.Buffer{})
+				Expect(err).ToNot(HaveOccurred())
+			}
+			Expect(dispatched).To(Equal(2))
+		})
+
+		It("serves requests concurrently", func() {
+			release := make(chan struct{})
+			newDispatcher = func() bslcdisp.Dispatcher {
+				return dispatcherFunc(func(reqBytes []byte) []byte {
+					if string(reqBytes) == "fake-slow-bytes-in" {
+						<-release
+					}
+					return append(reqBytes, []byte("-out")...)
+				})
+			}
+
+			slowOut := &bytes.Buffer{}
+			slowDone := make(chan error, 1)
+			go func() {
+				slowDone <- Forward(socketPath, strings.NewReader("fake-slow-bytes-in"), slowOut)
+			}()
+
+			out := &bytes.Buffer{}
+			err := Forward(socketPath, strings.NewReader("fake-bytes-in"), out)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out.String()).To(Equal("fake-bytes-in-out"))
+			Consistently(slowDone).ShouldNot(Receive())
+
+			close(release)
+			Eventually(slowDone).Should(Receive(BeNil()))
+			Expect(slowOut.String()).To(Equal("fake-slow-bytes-in-out"))
+		})
+
+		It("answers with a CPI error when dispatching panics and keeps serving", func() {
+			newDispatcher = func() bslcdisp.Dispatcher {
+				return dispatcherFunc(func(reqBytes []byte) []byte {
+					panic("fake-panic")
+				})
+			}
+
+			out := &bytes.Buffer{}
+			err := Forward(socketPath, strings.NewReader("fake-bytes-in"), out)
+			Expect(err).ToNot(HaveOccurred())
+
+			var resp bslcdisp.Response
+			Expect(json.Unmarshal(out.Bytes(), &resp)).To(Succeed())
+			Expect(resp.Error.Type).To(Equal("Bosh::Clouds::CpiError"))
+			Expect(resp.Error.Message).To(ContainSubstring("fake-panic"))
+
+			newDispatcher = func() bslcdisp.Dispatcher { return dispatcher }
+			dispatcher.DispatchRespBytes = []byte("fake-bytes-out")
+
+			out = &bytes.Buffer{}
+			err = Forward(socketPath, strings.NewReader("fake-bytes-in"), out)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out.String()).To(Equal("fake-bytes-out"))
+		})
+
+		It("returns an error when serving stops", func() {
+			serveListener, err := net.Listen("unix", filepath.Join(tmpDir, "other.sock"))
+			Expect(err).ToNot(HaveOccurred())
+			serveListener.Close()
+
+			err = socket.Serve(serveListener)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Forward", func() {
+		It("returns an error when no server listens on the socket", func() {
+			err := Forward(socketPath, strings.NewReader("fake-bytes-in"), &bytes.Buffer{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Connecting to socket"))
+		})
+	})
+})
@@ -0,0 +1,20 @@
+package audit
+
+import (
+	"path/filepath"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+type Options struct {
+	// File the billing events are appended to as JSON lines, none are recorded if empty
+	File string `json:"file"`
+}
+
+func (o Options) Validate() error {
+	if o.File != "" && !filepath.IsAbs(o.File) {
+		return bosherr.Errorf("Audit 'file' must be an absolute path, got '%s'", o.File)
+	}
+
+	return nil
+}
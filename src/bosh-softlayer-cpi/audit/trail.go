@@ -0,0 +1,138 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// Kinds of billing events
+const (
+	EventOrder  = "order"
+	EventCancel = "cancel"
+	// Not a billing event itself: set_vm_metadata tagged a virtual guest ordered earlier,
+	// so that its order can be attributed to the deployment, job and index
+	EventMetadata = "metadata"
+)
+
+// Kinds of resources billed
+const (
+	ResourceVirtualGuest = "virtual_guest"
+	ResourceBlockVolume  = "block_volume"
+)
+
+// ItemPrice is an item ordered or billed, with its recurring fees
+type ItemPrice struct {
+	ID          int     `json:"id,omitempty"`
+	Category    string  `json:"category,omitempty"`
+	Description string  `json:"description,omitempty"`
+	HourlyFee   float64 `json:"hourly_fee,omitempty"`
+	MonthlyFee  float64 `json:"monthly_fee,omitempty"`
+}
+
+// Metadata is the BOSH metadata of the resource, where known
+type Metadata struct {
+	Deployment string `json:"deployment,omitempty"`
+	Job        string `json:"job,omitempty"`
+	Index      string `json:"index,omitempty"`
+}
+
+type Event struct {
+	Timestamp     string      `json:"timestamp"`
+	Event         string      `json:"event"`
+	Resource      string      `json:"resource"`
+	ResourceID    int         `json:"resource_id,omitempty"`
+	OrderID       int         `json:"order_id,omitempty"`
+	BillingItemID int         `json:"billing_item_id,omitempty"`
+	ItemPrices    []ItemPrice `json:"item_prices,omitempty"`
+	// CPI method and director request id of the call that caused the event
+	Method    string `json:"method,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	Metadata
+}
+
+// Trail appends billing events as JSON lines. A nil Trail records nothing.
+type Trail struct {
+	mutex sync.Mutex
+	out   io.Writer
+	clock clock.Clock
+}
+
+func NewTrail(out io.Writer) *Trail {
+	return &Trail{out: out, clock: clock.NewClock()}
+}
+
+// OpenTrail opens the file of the options for appending, or returns a nil Trail when there is none
+func OpenTrail(options Options) (*Trail, error) {
+	if options.File == "" {
+		return nil, nil
+	}
+
+	file, err := os.OpenFile(options.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Opening audit file '%s'", options.File)
+	}
+
+	return NewTrail(file), nil
+}
+
+// WithClock returns a copy of the trail timestamping events with the given clock
+func (t *Trail) WithClock(clock clock.Clock) *Trail {
+	return &Trail{out: t.out, clock: clock}
+}
+
+// Enabled tells whether events are recorded, so that callers can skip looking up their details
+func (t *Trail) Enabled() bool {
+	return t != nil
+}
+
+// Record appends the event in a single write, so that the lines of concurrent CPI processes do not interleave
+func (t *Trail) Record(event Event) error {
+	if t == nil {
+		return nil
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	event.Timestamp = t.clock.Now().UTC().Format(time.RFC3339Nano)
+	line, err := json.Marshal(event)
+	if err != nil {
+		return bosherr.WrapError(err, "Marshalling audit event")
+	}
+
+	if _, err = t.out.Write(append(line, '\n')); err != nil {
+		return bosherr.WrapError(err, "Writing audit event")
+	}
+
+	return nil
+}
+
+// MetadataFromTags reads the deployment, job and index from "key:value" pairs, as set_vm_metadata
+// tags virtual guests and set_disk_metadata writes the notes of volumes
+func MetadataFromTags(tags []string) Metadata {
+	metadata := Metadata{}
+	for _, tag := range tags {
+		parts := strings.SplitN(strings.TrimSpace(tag), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		switch parts[0] {
+		case "deployment":
+			metadata.Deployment = parts[1]
+		case "job":
+			metadata.Job = parts[1]
+		case "index", "instance_index":
+			metadata.Index = parts[1]
+		}
+	}
+
+	return metadata
+}
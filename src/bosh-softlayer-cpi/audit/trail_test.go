@@ -0,0 +1,134 @@
+package audit_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "bosh-softlayer-cpi/audit"
+	"bosh-softlayer-cpi/test_helpers"
+)
+
+var _ = Describe("Trail", func() {
+	var (
+		out   bytes.Buffer
+		trail *Trail
+	)
+
+	BeforeEach(func() {
+		out.Reset()
+		trail = NewTrail(&out).WithClock(test_helpers.NewFakeClock(time.Date(2017, 6, 1, 10, 0, 0, 0, time.UTC)))
+	})
+
+	It("appends each event as a JSON line", func() {
+		Expect(trail.Record(Event{
+			Event:      EventOrder,
+			Resource:   ResourceVirtualGuest,
+			ResourceID: 1234,
+			OrderID:    5678,
+			ItemPrices: []ItemPrice{{ID: 1, Category: "guest_core", Description: "2 x 2.0 GHz Cores", HourlyFee: 0.06}},
+			Method:     "create_vm",
+			RequestID:  "fake-request-id",
+			Metadata:   Metadata{Deployment: "fake-deployment", Job: "fake-job", Index: "0"},
+		})).To(Succeed())
+		Expect(trail.Record(Event{Event: EventCancel, Resource: ResourceBlockVolume, ResourceID: 4321, BillingItemID: 8765})).To(Succeed())
+
+		lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		Expect(lines).To(HaveLen(2))
+		Expect(lines[0]).To(MatchJSON(`{
+			"timestamp": "2017-06-01T10:00:00Z",
+			"event": "order",
+			"resource": "virtual_guest",
+			"resource_id": 1234,
+			"order_id": 5678,
+			"item_prices": [{"id": 1, "category": "guest_core", "description": "2 x 2.0 GHz Cores", "hourly_fee": 0.06}],
+			"method": "create_vm",
+			"request_id": "fake-request-id",
+			"deployment": "fake-deployment",
+			"job": "fake-job",
+			"index": "0"
+		}`))
+		Expect(lines[1]).To(MatchJSON(`{
+			"timestamp": "2017-06-01T10:00:00Z",
+			"event": "cancel",
+			"resource": "block_volume",
+			"resource_id": 4321,
+			"billing_item_id": 8765
+		}`))
+	})
+
+	It("records nothing when nil", func() {
+		var nilTrail *Trail
+		Expect(nilTrail.Enabled()).To(BeFalse())
+		Expect(nilTrail.Record(Event{Event: EventOrder})).To(Succeed())
+	})
+
+	Describe("OpenTrail", func() {
+		var (
+			dir string
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "audit")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("appends to the file", func() {
+			path := filepath.Join(dir, "billing.log")
+			Expect(ioutil.WriteFile(path, []byte("{\"event\":\"order\"}\n"), 0640)).To(Succeed())
+
+			trail, err := OpenTrail(Options{File: path})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(trail.Enabled()).To(BeTrue())
+			Expect(trail.Record(Event{Event: EventCancel})).To(Succeed())
+
+			content, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+			Expect(lines).To(HaveLen(2))
+			Expect(lines[1]).To(ContainSubstring(`"event":"cancel"`))
+		})
+
+		It("returns a nil trail without a file", func() {
+			trail, err := OpenTrail(Options{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(trail.Enabled()).To(BeFalse())
+		})
+
+		It("returns error if the file cannot be opened", func() {
+			_, err := OpenTrail(Options{File: filepath.Join(dir, "missing", "billing.log")})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Opening audit file"))
+		})
+	})
+
+	Describe("MetadataFromTags", func() {
+		It("reads the deployment, job and index of vm tags and disk notes", func() {
+			Expect(MetadataFromTags([]string{"director:fake-director", "deployment:fake-deployment", "job:fake-job", "index:1"})).To(Equal(
+				Metadata{Deployment: "fake-deployment", Job: "fake-job", Index: "1"},
+			))
+			Expect(MetadataFromTags(strings.Split("deployment:fake-deployment,instance_index:2,bad-tag", ","))).To(Equal(
+				Metadata{Deployment: "fake-deployment", Index: "2"},
+			))
+		})
+	})
+
+	Describe("Options", func() {
+		It("returns error if the file is not an absolute path", func() {
+			err := Options{File: "billing.log"}.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Audit 'file' must be an absolute path"))
+		})
+	})
+})
@@ -6,6 +6,9 @@ import (
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 	boshsys "github.com/cloudfoundry/bosh-utils/system"
 
+	"bosh-softlayer-cpi/audit"
+	"bosh-softlayer-cpi/logger"
+	"bosh-softlayer-cpi/metrics"
 	"bosh-softlayer-cpi/registry"
 	boslconfig "bosh-softlayer-cpi/softlayer/config"
 )
@@ -23,6 +26,9 @@ type CPIProperties struct {
 	SoftLayer boslconfig.Config
 	Agent     registry.AgentOptions
 	Registry  registry.ClientOptions
+	Log       logger.Options
+	Metrics   metrics.Options
+	Audit     audit.Options
 }
 
 func NewConfigFromPath(configFile string, fs boshsys.FileSystem) (Config, error) {
@@ -40,6 +46,7 @@ func NewConfigFromPath(configFile string, fs boshsys.FileSystem) (Config, error)
 	if err = json.Unmarshal(bytes, &config); err != nil {
 		return config, bosherr.WrapError(err, "Unmarshalling config contents")
 	}
+	config.Cloud.Properties.Log = config.Cloud.Properties.Log.WithLegacyTrace(config.Cloud.Properties.SoftLayer.Trace)
 
 	if err = config.Validate(); err != nil {
 		return config, bosherr.WrapError(err, "Validating config")
@@ -58,6 +65,7 @@ func NewConfigFromString(configString string) (Config, error) {
 	if err = json.Unmarshal([]byte(configString), &config); err != nil {
 		return config, bosherr.WrapError(err, "Unmarshalling config contents")
 	}
+	config.Cloud.Properties.Log = config.Cloud.Properties.Log.WithLegacyTrace(config.Cloud.Properties.SoftLayer.Trace)
 
 	if err = config.Validate(); err != nil {
 		return config, bosherr.WrapError(err, "Validating config")
@@ -76,6 +84,15 @@ func (c Config) Validate() error {
 	if err := c.Cloud.Properties.Agent.Validate(); err != nil {
 		return bosherr.WrapError(err, "Validating agent configuration")
 	}
+	if err := c.Cloud.Properties.Log.Validate(); err != nil {
+		return bosherr.WrapError(err, "Validating log configuration")
+	}
+	if err := c.Cloud.Properties.Metrics.Validate(); err != nil {
+		return bosherr.WrapError(err, "Validating metrics configuration")
+	}
+	if err := c.Cloud.Properties.Audit.Validate(); err != nil {
+		return bosherr.WrapError(err, "Validating audit configuration")
+	}
 	//if err := c.Cloud.Properties.Registry.Validate(); err != nil {
 	//	return bosherr.WrapError(err, "Validating registry configuration")
 	//}
@@ -2,6 +2,8 @@ package config_test
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -83,6 +85,173 @@ var _ = Describe("NewConfigFromPath", func() {
 		Expect(err.Error()).To(ContainSubstring("Unmarshalling config"))
 	})
 
+	It("reads the softlayer timeouts as duration strings or seconds", func() {
+		err := fs.WriteFileString("/config.json", `{
+			"cloud": {
+				"plugin": "softlayer",
+				"properties": {
+					"softlayer": {
+						"username": "fake-username",
+						"api_key": "fake-api-key",
+						"timeouts": {"create_instance": "30m", "transaction_poll_interval": 1}
+					},
+					"agent": {"mbus": "fake-mbus", "blobstore": {"provider": "local"}}
+				}
+			}
+		}`)
+		Expect(err).ToNot(HaveOccurred())
+
+		cfg, err := config.NewConfigFromPath("/config.json", fs)
+		Expect(err).ToNot(HaveOccurred())
+
+		timeouts := cfg.Cloud.Properties.SoftLayer.Timeouts
+		Expect(timeouts.CreateInstance.Duration).To(Equal(30 * time.Minute))
+		Expect(timeouts.TransactionPollInterval.Duration).To(Equal(1 * time.Second))
+		Expect(timeouts.EditInstance.Duration).To(BeZero())
+
+		timeouts = timeouts.WithDefaults()
+		Expect(timeouts.CreateInstance.Duration).To(Equal(30 * time.Minute))
+		Expect(timeouts.EditInstance.Duration).To(Equal(30 * time.Minute))
+		Expect(timeouts.ReloadInstance.Duration).To(Equal(4 * time.Hour))
+	})
+
+	It("returns error if a softlayer timeout is not a duration", func() {
+		err := fs.WriteFileString("/config.json", `{"cloud": {"properties": {"softlayer": {"timeouts": {"cancel_instance": "ten minutes"}}}}}`)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = config.NewConfigFromPath("/config.json", fs)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Parsing duration 'ten minutes'"))
+	})
+
+	It("reads the softlayer retry policy", func() {
+		err := fs.WriteFileString("/config.json", `{
+			"cloud": {
+				"plugin": "softlayer",
+				"properties": {
+					"softlayer": {
+						"username": "fake-username",
+						"api_key": "fake-api-key",
+						"retry": {"max_retries": 8, "max_delay": "5m"}
+					},
+					"agent": {"mbus": "fake-mbus", "blobstore": {"provider": "local"}}
+				}
+			}
+		}`)
+		Expect(err).ToNot(HaveOccurred())
+
+		cfg, err := config.NewConfigFromPath("/config.json", fs)
+		Expect(err).ToNot(HaveOccurred())
+
+		retry := cfg.Cloud.Properties.SoftLayer.Retry.WithDefaults()
+		Expect(*retry.MaxRetries).To(Equal(8))
+		Expect(retry.MaxDelay.Duration).To(Equal(5 * time.Minute))
+		Expect(retry.InitialDelay.Duration).To(Equal(1 * time.Second))
+		Expect(retry.Multiplier).To(Equal(2.0))
+		Expect(*retry.Jitter).To(Equal(0.2))
+	})
+
+	It("keeps an explicit zero softlayer retry count and jitter", func() {
+		err := fs.WriteFileString("/config.json", `{
+			"cloud": {
+				"plugin": "softlayer",
+				"properties": {
+					"softlayer": {
+						"username": "fake-username",
+						"api_key": "fake-api-key",
+						"retry": {"max_retries": 0, "jitter": 0}
+					},
+					"agent": {"mbus": "fake-mbus", "blobstore": {"provider": "local"}}
+				}
+			}
+		}`)
+		Expect(err).ToNot(HaveOccurred())
+
+		cfg, err := config.NewConfigFromPath("/config.json", fs)
+		Expect(err).ToNot(HaveOccurred())
+
+		retry := cfg.Cloud.Properties.SoftLayer.Retry.WithDefaults()
+		Expect(*retry.MaxRetries).To(Equal(0))
+		Expect(*retry.Jitter).To(Equal(0.0))
+		Expect(retry.InitialDelay.Duration).To(Equal(1 * time.Second))
+
+		delay := retry.Delay(1)
+		Expect(delay).To(Equal(2 * time.Second))
+	})
+
+	It("keeps an explicit zero softlayer create instance delay", func() {
+		err := fs.WriteFileString("/config.json", `{
+			"cloud": {
+				"plugin": "softlayer",
+				"properties": {
+					"softlayer": {
+						"username": "fake-username",
+						"api_key": "fake-api-key",
+						"timeouts": {"create_instance_delay": 0}
+					},
+					"agent": {"mbus": "fake-mbus", "blobstore": {"provider": "local"}}
+				}
+			}
+		}`)
+		Expect(err).ToNot(HaveOccurred())
+
+		cfg, err := config.NewConfigFromPath("/config.json", fs)
+		Expect(err).ToNot(HaveOccurred())
+
+		timeouts := cfg.Cloud.Properties.SoftLayer.Timeouts.WithDefaults()
+		Expect(timeouts.CreateInstanceDelay.Duration).To(BeZero())
+		Expect(timeouts.CreateInstance.Duration).To(Equal(4 * time.Hour))
+
+		timeouts = boslconfig.Timeouts{}.WithDefaults()
+		Expect(timeouts.CreateInstanceDelay.Duration).To(Equal(90 * time.Second))
+	})
+
+	It("reads the softlayer private routes", func() {
+		err := fs.WriteFileString("/config.json", `{
+			"cloud": {
+				"plugin": "softlayer",
+				"properties": {
+					"softlayer": {
+						"username": "fake-username",
+						"api_key": "fake-api-key",
+						"private_routes": ["10.0.0.0/8", "192.168.0.0/16"]
+					},
+					"agent": {"mbus": "fake-mbus", "blobstore": {"provider": "local"}}
+				}
+			}
+		}`)
+		Expect(err).ToNot(HaveOccurred())
+
+		cfg, err := config.NewConfigFromPath("/config.json", fs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.Cloud.Properties.SoftLayer.PrivateRoutesOrDefault()).To(Equal([]string{"10.0.0.0/8", "192.168.0.0/16"}))
+		Expect(validSoftLayerConfig.PrivateRoutesOrDefault()).To(Equal(boslconfig.DefaultPrivateRoutes))
+	})
+
+	It("traces softlayer calls only when the legacy trace flag or a trace level is set", func() {
+		configJSON := `{
+			"cloud": {
+				"plugin": "softlayer",
+				"properties": {
+					"softlayer": {"username": "fake-username", "api_key": "fake-api-key"%s},
+					"agent": {"mbus": "fake-mbus", "blobstore": {"provider": "local"}}%s
+				}
+			}
+		}`
+
+		cfg, err := config.NewConfigFromString(fmt.Sprintf(configJSON, "", ""))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.Cloud.Properties.Log.TraceSoftLayer()).To(BeFalse())
+
+		cfg, err = config.NewConfigFromString(fmt.Sprintf(configJSON, `, "trace": true`, ""))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.Cloud.Properties.Log.TraceSoftLayer()).To(BeTrue())
+
+		cfg, err = config.NewConfigFromString(fmt.Sprintf(configJSON, `, "trace": true`, `, "log": {"softlayer_trace_level": "none"}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cfg.Cloud.Properties.Log.TraceSoftLayer()).To(BeFalse())
+	})
+
 	It("returns error if file cannot be read", func() {
 		err := fs.WriteFileString("/config.json", "{}")
 		Expect(err).ToNot(HaveOccurred())
@@ -110,6 +279,71 @@ var _ = Describe("Config", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("returns error if a softlayer timeout is negative", func() {
+			config.Cloud.Properties.SoftLayer.Timeouts.CancelInstance = boslconfig.NewDuration(-time.Minute)
+
+			err := config.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Timeout 'cancel_instance' must not be negative"))
+		})
+
+		It("returns error if the softlayer retry jitter is out of range", func() {
+			jitter := 1.5
+			config.Cloud.Properties.SoftLayer.Retry.Jitter = &jitter
+
+			err := config.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Retry 'jitter' must be between 0 and 1"))
+		})
+
+		It("returns error if a softlayer private route is not a CIDR", func() {
+			config.Cloud.Properties.SoftLayer.PrivateRoutes = []string{"166.8.0.0"}
+
+			err := config.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Private route destination '166.8.0.0' is not a CIDR"))
+		})
+
+		It("returns error if the log format is unknown", func() {
+			config.Cloud.Properties.Log.Format = "xml"
+
+			err := config.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Unknown log format 'xml'"))
+		})
+
+		It("returns error if a log redact pattern does not compile", func() {
+			config.Cloud.Properties.Log.RedactPatterns = []string{"fake-("}
+
+			err := config.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Compiling secret pattern 'fake-('"))
+		})
+
+		It("returns error if the log level is unknown", func() {
+			config.Cloud.Properties.Log.Level = "verbose"
+
+			err := config.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Parsing log level"))
+		})
+
+		It("returns error if the syslog endpoint is not local", func() {
+			config.Cloud.Properties.Log.Syslog.Address = "10.0.0.1:514"
+
+			err := config.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Syslog address '10.0.0.1:514' must be on the local host"))
+		})
+
+		It("returns error if the audit file is relative", func() {
+			config.Cloud.Properties.Audit.File = "billing.log"
+
+			err := config.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Validating audit configuration"))
+		})
+
 		It("returns error if softlayer section is not valid", func() {
 			config.Cloud.Properties.SoftLayer.Username = ""
 
@@ -119,3 +353,19 @@ var _ = Describe("Config", func() {
 		})
 	})
 })
+
+var _ = Describe("RetryPolicy", func() {
+	It("grows the delay exponentially up to the max delay", func() {
+		policy := boslconfig.RetryPolicy{
+			InitialDelay: boslconfig.NewDuration(time.Second),
+			MaxDelay:     boslconfig.NewDuration(5 * time.Second),
+			Multiplier:   2,
+		}
+
+		Expect(policy.Delay(0)).To(Equal(time.Second))
+		Expect(policy.Delay(1)).To(Equal(2 * time.Second))
+		Expect(policy.Delay(2)).To(Equal(4 * time.Second))
+		Expect(policy.Delay(3)).To(Equal(5 * time.Second))
+		Expect(policy.Delay(100)).To(Equal(5 * time.Second))
+	})
+})
@@ -0,0 +1,205 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshretry "github.com/cloudfoundry/bosh-utils/retrystrategy"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// RequestFields describe the CPI call being served. JSON log lines carry them in their own fields.
+type RequestFields struct {
+	Method  string
+	VMCID   string
+	DiskCID string
+}
+
+type jsonLine struct {
+	Timestamp string `json:"timestamp"`
+	Level     string `json:"level"`
+	Tag       string `json:"tag"`
+	RequestID string `json:"request_id,omitempty"`
+	Method    string `json:"method,omitempty"`
+	VMCID     string `json:"vm_cid,omitempty"`
+	DiskCID   string `json:"disk_cid,omitempty"`
+	Message   string `json:"message"`
+}
+
+// jsonLogger writes one JSON object per line. The serial tag prefix, e.g. the director request id,
+// goes to the request_id field instead of being baked into the tag. Secrets are masked in the message
+// before it is encoded, since escaping the quotes of JSON payloads would hide them from RedactSecrets.
+type jsonLogger struct {
+	level       boshlog.LogLevel
+	out         io.Writer
+	err         io.Writer
+	mutex       sync.Mutex
+	forcedDebug bool
+
+	threadPrefix string
+	fields       RequestFields
+}
+
+// NewJSONLogger writes debug and info lines to out, warnings and errors to err
+func NewJSONLogger(level boshlog.LogLevel, serialTagPrefix string, out, err io.Writer) Logger {
+	return &jsonLogger{
+		level:        level,
+		out:          out,
+		err:          err,
+		threadPrefix: serialTagPrefix,
+	}
+}
+
+// GetBoshLogger returns the logger itself, so that bosh-utils components write JSON lines too
+func (l *jsonLogger) GetBoshLogger() boshlog.Logger {
+	return l
+}
+
+func (l *jsonLogger) GetSerialTagPrefix() string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.threadPrefix
+}
+
+func (l *jsonLogger) ChangeSerialTagPrefix(serialTagPrefix string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.threadPrefix = serialTagPrefix
+}
+
+func (l *jsonLogger) ChangeRequestFields(fields RequestFields) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.fields = fields
+}
+
+func (l *jsonLogger) RequestFields() RequestFields {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.fields
+}
+
+func (l *jsonLogger) Debug(tag, msg string, args ...interface{}) {
+	l.write(boshlog.LevelDebug, "DEBUG", l.out, tag, msg, args...)
+}
+
+func (l *jsonLogger) DebugWithDetails(tag, msg string, args ...interface{}) {
+	msg = msg + "\n********************\n%s\n********************"
+	l.Debug(tag, msg, args...)
+}
+
+func (l *jsonLogger) Info(tag, msg string, args ...interface{}) {
+	l.write(boshlog.LevelInfo, "INFO", l.out, tag, msg, args...)
+}
+
+func (l *jsonLogger) Warn(tag, msg string, args ...interface{}) {
+	l.write(boshlog.LevelWarn, "WARN", l.err, tag, msg, args...)
+}
+
+func (l *jsonLogger) Error(tag, msg string, args ...interface{}) {
+	l.write(boshlog.LevelError, "ERROR", l.err, tag, msg, args...)
+}
+
+func (l *jsonLogger) ErrorWithDetails(tag, msg string, args ...interface{}) {
+	msg = msg + "\n********************\n%s\n********************"
+	l.Error(tag, msg, args...)
+}
+
+func (l *jsonLogger) HandlePanic(tag string) {
+	if e := recover(); e != nil {
+		l.ErrorWithDetails(tag, "Panic: %s", fmt.Sprint(e), debug.Stack())
+		os.Exit(2)
+	}
+}
+
+func (l *jsonLogger) ToggleForcedDebug() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.forcedDebug = !l.forcedDebug
+}
+
+func (l *jsonLogger) Flush() error { return nil }
+
+func (l *jsonLogger) FlushTimeout(time.Duration) error { return nil }
+
+// ChangeRetryStrategyLogTag leaves the tag alone, since the request id has its own field
+func (l *jsonLogger) ChangeRetryStrategyLogTag(retryStrategy *boshretry.RetryStrategy) error {
+	return nil
+}
+
+func (l *jsonLogger) write(level boshlog.LogLevel, levelName string, w io.Writer, tag, msg string, args ...interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.level > level && !l.forcedDebug {
+		return
+	}
+
+	line, err := json.Marshal(jsonLine{
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		Level:     levelName,
+		Tag:       tag,
+		RequestID: l.threadPrefix,
+		Method:    l.fields.Method,
+		VMCID:     l.fields.VMCID,
+		DiskCID:   l.fields.DiskCID,
+		Message:   RedactSecrets(fmt.Sprintf(msg, args...)),
+	})
+	if err != nil {
+		return
+	}
+
+	w.Write(append(line, '\n'))
+}
+
+// lineWriter logs every line written to it, e.g. by a *log.Logger of a library
+type lineWriter struct {
+	logger Logger
+	tag    string
+	level  boshlog.LogLevel
+}
+
+// NewLineWriter returns a writer logging each line at the given level with the given tag. A leading
+// "[prefix] " is dropped, since the logger adds its own.
+func NewLineWriter(logger Logger, tag string, level boshlog.LogLevel) io.Writer {
+	return lineWriter{logger: logger, tag: tag, level: level}
+}
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		if strings.HasPrefix(line, "[") {
+			if i := strings.Index(line, "] "); i >= 0 {
+				line = line[i+2:]
+			}
+		}
+
+		switch w.level {
+		case boshlog.LevelDebug:
+			w.logger.Debug(w.tag, "%s", line)
+		case boshlog.LevelInfo:
+			w.logger.Info(w.tag, "%s", line)
+		case boshlog.LevelWarn:
+			w.logger.Warn(w.tag, "%s", line)
+		case boshlog.LevelError:
+			w.logger.Error(w.tag, "%s", line)
+		}
+	}
+
+	return len(p), nil
+}
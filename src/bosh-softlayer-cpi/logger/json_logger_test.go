@@ -0,0 +1,145 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshretry "github.com/cloudfoundry/bosh-utils/retrystrategy"
+
+	. "bosh-softlayer-cpi/logger"
+)
+
+var _ = Describe("JSONLogger", func() {
+	var (
+		out    bytes.Buffer
+		errOut bytes.Buffer
+		logger Logger
+	)
+
+	lines := func(buf *bytes.Buffer) []map[string]string {
+		result := []map[string]string{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			entry := map[string]string{}
+			Expect(json.Unmarshal([]byte(line), &entry)).To(Succeed())
+			result = append(result, entry)
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		out.Reset()
+		errOut.Reset()
+		logger = NewJSONLogger(boshlog.LevelInfo, "fake-serial", &out, &errOut)
+	})
+
+	It("writes one JSON object per line", func() {
+		logger.Info("fake-tag", "fake-message %d", 1)
+		logger.Info("fake-tag", "fake-message\nwith two lines")
+
+		entries := lines(&out)
+		Expect(entries).To(HaveLen(2))
+		Expect(entries[0]["level"]).To(Equal("INFO"))
+		Expect(entries[0]["tag"]).To(Equal("fake-tag"))
+		Expect(entries[0]["request_id"]).To(Equal("fake-serial"))
+		Expect(entries[0]["message"]).To(Equal("fake-message 1"))
+		Expect(entries[1]["message"]).To(Equal("fake-message\nwith two lines"))
+
+		timestamp, err := time.Parse(time.RFC3339Nano, entries[0]["timestamp"])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(timestamp).To(BeTemporally("~", time.Now(), time.Minute))
+	})
+
+	It("writes warnings and errors to the error writer and skips levels below its own", func() {
+		logger.Debug("fake-tag", "fake-debug")
+		logger.Warn("fake-tag", "fake-warning")
+		logger.Error("fake-tag", "fake-error")
+
+		Expect(out.String()).To(BeEmpty())
+		entries := lines(&errOut)
+		Expect(entries).To(HaveLen(2))
+		Expect(entries[0]["level"]).To(Equal("WARN"))
+		Expect(entries[1]["level"]).To(Equal("ERROR"))
+	})
+
+	It("adds the request id and the CPI call fields once known", func() {
+		logger.ChangeSerialTagPrefix("fake-request-id")
+		logger.ChangeRequestFields(RequestFields{Method: "attach_disk", VMCID: "1234", DiskCID: "5678"})
+		logger.Info("fake-tag", "fake-message")
+
+		entry := lines(&out)[0]
+		Expect(entry["request_id"]).To(Equal("fake-request-id"))
+		Expect(entry["method"]).To(Equal("attach_disk"))
+		Expect(entry["vm_cid"]).To(Equal("1234"))
+		Expect(entry["disk_cid"]).To(Equal("5678"))
+		Expect(logger.GetSerialTagPrefix()).To(Equal("fake-request-id"))
+	})
+
+	It("leaves out the CIDs it does not know", func() {
+		logger.ChangeRequestFields(RequestFields{Method: "create_stemcell"})
+		logger.Info("fake-tag", "fake-message")
+
+		Expect(out.String()).NotTo(ContainSubstring("vm_cid"))
+		Expect(out.String()).NotTo(ContainSubstring("disk_cid"))
+	})
+
+	It("redacts secrets in the message before encoding it", func() {
+		logger.Info("fake-tag", "Settings %s", `{"api_key":"fake-api-key","username":"fake-username"}`)
+
+		Expect(out.String()).NotTo(ContainSubstring("fake-api-key"))
+		Expect(lines(&out)[0]["message"]).To(Equal(`Settings {"api_key":"<redacted>","username":"fake-username"}`))
+	})
+
+	It("writes JSON lines through its bosh-utils logger", func() {
+		logger.GetBoshLogger().Info("fake-bosh-tag", "fake-message")
+
+		entry := lines(&out)[0]
+		Expect(entry["tag"]).To(Equal("fake-bosh-tag"))
+		Expect(entry["request_id"]).To(Equal("fake-serial"))
+	})
+
+	It("keeps the log tag of retry strategies", func() {
+		retryStrategy := boshretry.NewAttemptRetryStrategy(1, time.Millisecond, nil, logger.GetBoshLogger())
+		Expect(logger.ChangeRetryStrategyLogTag(&retryStrategy)).To(Succeed())
+	})
+
+	Describe("NewLineWriter", func() {
+		It("logs each line written by a *log.Logger without its prefix", func() {
+			logger = NewJSONLogger(boshlog.LevelDebug, "fake-serial", &out, &errOut)
+			libLogger := log.New(NewLineWriter(logger, "fake-lib", boshlog.LevelDebug), "[fake-serial:fake-lib] ", 0)
+			libLogger.Print("first line\nsecond line")
+
+			entries := lines(&out)
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[0]["tag"]).To(Equal("fake-lib"))
+			Expect(entries[0]["level"]).To(Equal("DEBUG"))
+			Expect(entries[0]["message"]).To(Equal("first line"))
+			Expect(entries[1]["message"]).To(Equal("second line"))
+		})
+
+		It("logs the lines at its level", func() {
+			libLogger := log.New(NewLineWriter(logger, "fake-lib", boshlog.LevelWarn), "", 0)
+			libLogger.Print("fake-line")
+
+			Expect(out.String()).To(BeEmpty())
+			Expect(lines(&errOut)[0]["level"]).To(Equal("WARN"))
+		})
+
+		It("drops the lines at level none", func() {
+			libLogger := log.New(NewLineWriter(logger, "fake-lib", boshlog.LevelNone), "", 0)
+			libLogger.Print("fake-line")
+
+			Expect(out.String()).To(BeEmpty())
+			Expect(errOut.String()).To(BeEmpty())
+		})
+	})
+})
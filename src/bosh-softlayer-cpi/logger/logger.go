@@ -23,16 +23,20 @@ type Logger interface {
 	Flush() error
 	GetBoshLogger() boshlog.Logger
 	GetSerialTagPrefix() string
+	ChangeSerialTagPrefix(serialTagPrefix string)
+	ChangeRequestFields(fields RequestFields)
+	RequestFields() RequestFields
 	ChangeRetryStrategyLogTag(retryStrategy *boshretry.RetryStrategy) error
 }
 
 type logger struct {
 	boshlogger   boshlog.Logger
 	threadPrefix string
+	fields       RequestFields
 }
 
 func New(level boshlog.LogLevel, serialTagPrefix string, out, err *log.Logger) Logger {
-	Logger := boshlog.New(level, out, err)
+	Logger := redactingBoshLogger{boshlog.New(level, out, err)}
 	return &logger{
 		boshlogger:   Logger,
 		threadPrefix: serialTagPrefix,
@@ -60,6 +64,20 @@ func (l *logger) GetSerialTagPrefix() string {
 	return l.threadPrefix
 }
 
+// ChangeSerialTagPrefix replaces the prefix of all subsequent log lines, e.g. with the director request id
+func (l *logger) ChangeSerialTagPrefix(serialTagPrefix string) {
+	l.threadPrefix = serialTagPrefix
+}
+
+// ChangeRequestFields keeps the fields for RequestFields, text lines only carry the serial tag prefix
+func (l *logger) ChangeRequestFields(fields RequestFields) {
+	l.fields = fields
+}
+
+func (l *logger) RequestFields() RequestFields {
+	return l.fields
+}
+
 func (l *logger) Debug(tag, msg string, args ...interface{}) {
 	tag = fmt.Sprintf("%s:%s", l.threadPrefix, tag)
 	l.boshlogger.Debug(tag, msg, args...)
@@ -97,6 +115,38 @@ func (l *logger) HandlePanic(tag string) {
 
 func (l *logger) Flush() error { return l.boshlogger.Flush() }
 
+// redactingBoshLogger masks secrets in the messages of a bosh-utils logger, so that components
+// logging through GetBoshLogger() are redacted as well
+type redactingBoshLogger struct {
+	boshlog.Logger
+}
+
+func (l redactingBoshLogger) Debug(tag, msg string, args ...interface{}) {
+	l.Logger.Debug(tag, "%s", RedactSecrets(fmt.Sprintf(msg, args...)))
+}
+
+func (l redactingBoshLogger) DebugWithDetails(tag, msg string, args ...interface{}) {
+	msg = msg + "\n********************\n%s\n********************"
+	l.Debug(tag, msg, args...)
+}
+
+func (l redactingBoshLogger) Info(tag, msg string, args ...interface{}) {
+	l.Logger.Info(tag, "%s", RedactSecrets(fmt.Sprintf(msg, args...)))
+}
+
+func (l redactingBoshLogger) Warn(tag, msg string, args ...interface{}) {
+	l.Logger.Warn(tag, "%s", RedactSecrets(fmt.Sprintf(msg, args...)))
+}
+
+func (l redactingBoshLogger) Error(tag, msg string, args ...interface{}) {
+	l.Logger.Error(tag, "%s", RedactSecrets(fmt.Sprintf(msg, args...)))
+}
+
+func (l redactingBoshLogger) ErrorWithDetails(tag, msg string, args ...interface{}) {
+	msg = msg + "\n********************\n%s\n********************"
+	l.Error(tag, msg, args...)
+}
+
 // it's unfriendly to change RetryStrategy().logtag
 func (l *logger) ChangeRetryStrategyLogTag(retryStrategy *boshretry.RetryStrategy) error {
 	defer func() {
@@ -79,6 +79,14 @@ var _ = Describe("Logger", func() {
 
 	})
 
+	It("change serial tag prefix of cpi logger", func() {
+		logger.ChangeSerialTagPrefix("fake-request-id")
+		Expect(logger.GetSerialTagPrefix()).To(Equal("fake-request-id"))
+
+		logger.Info("LoggerUnitTest", "It is from cpi logger.")
+		Expect(out.String()).To(MatchRegexp("\\[fake-request-id:LoggerUnitTest\\]"))
+	})
+
 	It("get serial tag prefix of cpi logger", func() {
 		serialTagPrefix := logger.GetSerialTagPrefix()
 		Expect(serialTagPrefix).To(ContainSubstring("fake-tag-prefix"))
@@ -116,6 +124,19 @@ var _ = Describe("Logger", func() {
 		Expect(out.String()).To(ContainSubstring("********************"))
 	})
 
+	It("redacts secrets, also through its bosh-utils logger", func() {
+		logger.Info("LoggerUnitTest", "Settings %s", `{"password":"fake-password"}`)
+		logger.GetBoshLogger().Debug("LoggerUnitTest", "Endpoint https://admin:%s@fake-registry", "fake-registry-password")
+		logger.GetBoshLogger().ErrorWithDetails("LoggerUnitTest", "Failed", "api_key=fake-api-key")
+
+		Expect(out.String()).To(ContainSubstring(`{"password":"<redacted>"}`))
+		Expect(out.String()).To(ContainSubstring("https://admin:<redacted>@fake-registry"))
+		Expect(out.String()).To(ContainSubstring("api_key=<redacted>"))
+		Expect(out.String()).NotTo(ContainSubstring("fake-password"))
+		Expect(out.String()).NotTo(ContainSubstring("fake-registry-password"))
+		Expect(out.String()).NotTo(ContainSubstring("fake-api-key"))
+	})
+
 	It("call ChangeRetryStrategyLogTag", func() {
 		execStmtRetryable := boshretry.NewRetryable(
 			func() (bool, error) {
@@ -0,0 +1,184 @@
+package logger
+
+import (
+	"net"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+)
+
+const (
+	DefaultFileMaxSize    = 10 * 1024 * 1024
+	DefaultFileMaxBackups = 5
+	DefaultSyslogTag      = "bosh-softlayer-cpi"
+)
+
+type Options struct {
+	// Maximum size in bytes of the log returned to the director with each response
+	ResponseLogMaxSize int `json:"response_log_max_size"`
+	// "text" (default) for the bosh-utils format, or "json" for one JSON object per line
+	Format string `json:"format"`
+	// Regular expressions of extra secrets to mask in the log, see SetExtraSecretPatterns
+	RedactPatterns []string `json:"redact_patterns"`
+	// "debug" (default), "info", "warn", "error" or "none"
+	Level string `json:"level"`
+	// Level of the HTTP trace of softlayer-go, "none" by default. The trace is only logged when
+	// this level is enabled by Level.
+	SoftLayerTraceLevel string `json:"softlayer_trace_level"`
+
+	File   FileOptions   `json:"file"`
+	Syslog SyslogOptions `json:"syslog"`
+}
+
+// FileOptions configure a log file rotated by size. No file is written without a path.
+type FileOptions struct {
+	Path string `json:"path"`
+	// Size in bytes after which the file is rotated, DefaultFileMaxSize if 0
+	MaxSize int64 `json:"max_size"`
+	// Number of rotated files kept as path.1, path.2..., DefaultFileMaxBackups if 0
+	MaxBackups int `json:"max_backups"`
+}
+
+// SyslogOptions configure a syslog endpoint on the local host. Nothing is sent without an address.
+type SyslogOptions struct {
+	// "udp" (default) or "tcp"
+	Network string `json:"network"`
+	Address string `json:"address"`
+	Tag     string `json:"tag"`
+}
+
+func (o Options) Validate() error {
+	switch o.Format {
+	case "", FormatText, FormatJSON:
+	default:
+		return bosherr.Errorf("Unknown log format '%s', expected '%s' or '%s'", o.Format, FormatText, FormatJSON)
+	}
+
+	if _, err := compileSecretPatterns(o.RedactPatterns); err != nil {
+		return err
+	}
+
+	if _, err := parseLevel(o.Level); err != nil {
+		return bosherr.WrapError(err, "Parsing log level")
+	}
+
+	if _, err := parseLevel(o.SoftLayerTraceLevel); err != nil {
+		return bosherr.WrapError(err, "Parsing softlayer trace level")
+	}
+
+	if err := o.File.Validate(); err != nil {
+		return bosherr.WrapError(err, "Validating log file")
+	}
+
+	if err := o.Syslog.Validate(); err != nil {
+		return bosherr.WrapError(err, "Validating syslog")
+	}
+
+	return nil
+}
+
+// LogLevel is the configured level of the CPI log
+func (o Options) LogLevel() boshlog.LogLevel {
+	level, _ := parseLevel(o.Level)
+	return level
+}
+
+// TraceLevel is the configured level of the softlayer-go HTTP trace
+func (o Options) TraceLevel() boshlog.LogLevel {
+	if o.SoftLayerTraceLevel == "" {
+		return boshlog.LevelNone
+	}
+
+	level, _ := parseLevel(o.SoftLayerTraceLevel)
+	return level
+}
+
+// WithLegacyTrace keeps the deprecated softlayer.trace flag working: without a trace level of
+// its own, the trace is logged at debug level when the flag is set
+func (o Options) WithLegacyTrace(trace bool) Options {
+	if o.SoftLayerTraceLevel == "" && trace {
+		o.SoftLayerTraceLevel = "debug"
+	}
+
+	return o
+}
+
+// TraceSoftLayer tells whether the softlayer-go HTTP trace makes it into the log at all
+func (o Options) TraceSoftLayer() bool {
+	return o.TraceLevel() != boshlog.LevelNone && o.TraceLevel() >= o.LogLevel()
+}
+
+func parseLevel(level string) (boshlog.LogLevel, error) {
+	if level == "" {
+		return boshlog.LevelDebug, nil
+	}
+
+	return boshlog.Levelify(level)
+}
+
+func (o FileOptions) Validate() error {
+	if o.MaxSize < 0 {
+		return bosherr.Errorf("File 'max_size' must not be negative, got %d", o.MaxSize)
+	}
+
+	if o.MaxBackups < 0 {
+		return bosherr.Errorf("File 'max_backups' must not be negative, got %d", o.MaxBackups)
+	}
+
+	return nil
+}
+
+func (o FileOptions) WithDefaults() FileOptions {
+	if o.MaxSize == 0 {
+		o.MaxSize = DefaultFileMaxSize
+	}
+	if o.MaxBackups == 0 {
+		o.MaxBackups = DefaultFileMaxBackups
+	}
+
+	return o
+}
+
+func (o SyslogOptions) Validate() error {
+	switch o.Network {
+	case "", "udp", "tcp":
+	default:
+		return bosherr.Errorf("Unknown syslog network '%s', expected 'udp' or 'tcp'", o.Network)
+	}
+
+	if o.Address == "" {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(o.Address)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Parsing syslog address '%s'", o.Address)
+	}
+
+	if !isLocalHost(host) {
+		return bosherr.Errorf("Syslog address '%s' must be on the local host", o.Address)
+	}
+
+	return nil
+}
+
+func (o SyslogOptions) WithDefaults() SyslogOptions {
+	if o.Network == "" {
+		o.Network = "udp"
+	}
+	if o.Tag == "" {
+		o.Tag = DefaultSyslogTag
+	}
+
+	return o
+}
+
+func isLocalHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,70 @@
+package logger_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+
+	. "bosh-softlayer-cpi/logger"
+)
+
+var _ = Describe("Options", func() {
+	It("logs at debug level and does not trace by default", func() {
+		options := Options{}
+
+		Expect(options.Validate()).To(Succeed())
+		Expect(options.LogLevel()).To(Equal(boshlog.LevelDebug))
+		Expect(options.TraceLevel()).To(Equal(boshlog.LevelNone))
+		Expect(options.TraceSoftLayer()).To(BeFalse())
+	})
+
+	It("traces at debug level when only the legacy trace flag is set", func() {
+		Expect(Options{}.WithLegacyTrace(true).TraceLevel()).To(Equal(boshlog.LevelDebug))
+		Expect(Options{}.WithLegacyTrace(true).TraceSoftLayer()).To(BeTrue())
+		Expect(Options{}.WithLegacyTrace(false).TraceSoftLayer()).To(BeFalse())
+		Expect(Options{SoftLayerTraceLevel: "none"}.WithLegacyTrace(true).TraceSoftLayer()).To(BeFalse())
+	})
+
+	It("parses levels regardless of case", func() {
+		options := Options{Level: "INFO", SoftLayerTraceLevel: "warn"}
+
+		Expect(options.Validate()).To(Succeed())
+		Expect(options.LogLevel()).To(Equal(boshlog.LevelInfo))
+		Expect(options.TraceLevel()).To(Equal(boshlog.LevelWarn))
+	})
+
+	It("traces softlayer-go only when the trace level is enabled", func() {
+		Expect(Options{Level: "info"}.TraceSoftLayer()).To(BeFalse())
+		Expect(Options{Level: "info", SoftLayerTraceLevel: "info"}.TraceSoftLayer()).To(BeTrue())
+		Expect(Options{SoftLayerTraceLevel: "none"}.TraceSoftLayer()).To(BeFalse())
+	})
+
+	It("returns error if a level is unknown", func() {
+		err := Options{SoftLayerTraceLevel: "verbose"}.Validate()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Parsing softlayer trace level"))
+	})
+
+	It("returns error if the log file limits are negative", func() {
+		err := Options{File: FileOptions{Path: "/fake-path", MaxBackups: -1}}.Validate()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("File 'max_backups' must not be negative"))
+	})
+
+	It("accepts syslog endpoints on the local host only", func() {
+		Expect(Options{Syslog: SyslogOptions{Address: "127.0.0.1:514"}}.Validate()).To(Succeed())
+		Expect(Options{Syslog: SyslogOptions{Network: "tcp", Address: "localhost:514"}}.Validate()).To(Succeed())
+		Expect(Options{Syslog: SyslogOptions{Address: "[::1]:514"}}.Validate()).To(Succeed())
+
+		err := Options{Syslog: SyslogOptions{Address: "syslog.example.com:514"}}.Validate()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("must be on the local host"))
+	})
+
+	It("returns error if the syslog network is unknown", func() {
+		err := Options{Syslog: SyslogOptions{Network: "unix", Address: "127.0.0.1:514"}}.Validate()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Unknown syslog network 'unix'"))
+	})
+})
@@ -0,0 +1,166 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log/syslog"
+	"os"
+	"sync"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// Sinks are the destinations of the log besides stderr: debug and info lines go to Out,
+// warnings and errors to Err
+type Sinks struct {
+	Out []io.Writer
+	Err []io.Writer
+
+	closers []io.Closer
+}
+
+// OpenSinks opens the log file and connects to the syslog endpoint that are configured
+func OpenSinks(options Options) (Sinks, error) {
+	sinks := Sinks{}
+
+	if options.File.Path != "" {
+		file := options.File.WithDefaults()
+		rotating, err := NewRotatingFile(file.Path, file.MaxSize, file.MaxBackups)
+		if err != nil {
+			return Sinks{}, err
+		}
+
+		sinks.Out = append(sinks.Out, rotating)
+		sinks.Err = append(sinks.Err, rotating)
+		sinks.closers = append(sinks.closers, rotating)
+	}
+
+	if options.Syslog.Address != "" {
+		endpoint := options.Syslog.WithDefaults()
+		writer, err := syslog.Dial(endpoint.Network, endpoint.Address, syslog.LOG_INFO|syslog.LOG_USER, endpoint.Tag)
+		if err != nil {
+			sinks.Close()
+			return Sinks{}, bosherr.WrapErrorf(err, "Connecting to syslog at '%s'", endpoint.Address)
+		}
+
+		sinks.Out = append(sinks.Out, syslogWriter{writer.Info})
+		sinks.Err = append(sinks.Err, syslogWriter{writer.Err})
+		sinks.closers = append(sinks.closers, writer)
+	}
+
+	return sinks, nil
+}
+
+func (s Sinks) Close() error {
+	var firstErr error
+	for _, closer := range s.closers {
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// syslogWriter sends each write as a message of the severity of its send function
+type syslogWriter struct {
+	send func(string) error
+}
+
+func (w syslogWriter) Write(p []byte) (int, error) {
+	if err := w.send(string(p)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// rotatingFile appends to a file, moving it to path.1 once it would grow beyond maxSize and
+// shifting the older files up to path.<maxBackups>
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mutex sync.Mutex
+	file  *os.File
+	size  int64
+}
+
+func NewRotatingFile(path string, maxSize int64, maxBackups int) (io.WriteCloser, error) {
+	f := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	return n, err
+}
+
+func (f *rotatingFile) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.file.Close()
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Opening log file '%s'", f.path)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return bosherr.WrapErrorf(err, "Checking size of log file '%s'", f.path)
+	}
+
+	f.file = file
+	f.size = info.Size()
+
+	return nil
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return bosherr.WrapErrorf(err, "Closing log file '%s'", f.path)
+	}
+
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		err := os.Rename(f.backupPath(i), f.backupPath(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return bosherr.WrapErrorf(err, "Rotating log file '%s'", f.backupPath(i))
+		}
+	}
+
+	if f.maxBackups > 0 {
+		err := os.Rename(f.path, f.backupPath(1))
+		if err != nil && !os.IsNotExist(err) {
+			return bosherr.WrapErrorf(err, "Rotating log file '%s'", f.path)
+		}
+	} else if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+		return bosherr.WrapErrorf(err, "Removing log file '%s'", f.path)
+	}
+
+	return f.open()
+}
+
+func (f *rotatingFile) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", f.path, i)
+}
@@ -0,0 +1,103 @@
+package logger_test
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "bosh-softlayer-cpi/logger"
+)
+
+var _ = Describe("Sinks", func() {
+	var (
+		dir string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "logger-sinks")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	readFile := func(path string) string {
+		content, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		return string(content)
+	}
+
+	Describe("NewRotatingFile", func() {
+		It("appends to the file and rotates it once it would grow beyond its size", func() {
+			path := filepath.Join(dir, "cpi.log")
+			Expect(ioutil.WriteFile(path, []byte("old\n"), 0640)).To(Succeed())
+
+			file, err := NewRotatingFile(path, 10, 2)
+			Expect(err).NotTo(HaveOccurred())
+			defer file.Close()
+
+			for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+				_, err = file.Write([]byte(line))
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(readFile(path)).To(Equal("fourth\n"))
+			Expect(readFile(path + ".1")).To(Equal("third\n"))
+			Expect(readFile(path + ".2")).To(Equal("second\n"))
+			Expect(path + ".3").NotTo(BeAnExistingFile())
+		})
+
+		It("returns error if the file cannot be opened", func() {
+			_, err := NewRotatingFile(filepath.Join(dir, "missing", "cpi.log"), 10, 2)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Opening log file"))
+		})
+	})
+
+	Describe("OpenSinks", func() {
+		It("opens nothing by default", func() {
+			sinks, err := OpenSinks(Options{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sinks.Out).To(BeEmpty())
+			Expect(sinks.Err).To(BeEmpty())
+		})
+
+		It("writes to the log file and sends to syslog by severity", func() {
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+
+			path := filepath.Join(dir, "cpi.log")
+			sinks, err := OpenSinks(Options{
+				File:   FileOptions{Path: path},
+				Syslog: SyslogOptions{Address: conn.LocalAddr().String()},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			defer sinks.Close()
+			Expect(sinks.Out).To(HaveLen(2))
+			Expect(sinks.Err).To(HaveLen(2))
+
+			for _, w := range sinks.Err {
+				_, err = w.Write([]byte("fake-error\n"))
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(readFile(path)).To(Equal("fake-error\n"))
+
+			buf := make([]byte, 1024)
+			conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			n, _, err := conn.ReadFrom(buf)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(buf[:n])).To(HavePrefix("<11>"))
+			Expect(string(buf[:n])).To(ContainSubstring(DefaultSyslogTag))
+			Expect(string(buf[:n])).To(ContainSubstring("fake-error"))
+		})
+	})
+})
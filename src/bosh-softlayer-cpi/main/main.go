@@ -1,13 +1,16 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
@@ -21,22 +24,33 @@ import (
 	"bosh-softlayer-cpi/api"
 	"bosh-softlayer-cpi/api/dispatcher"
 	"bosh-softlayer-cpi/api/transport"
+	"bosh-softlayer-cpi/audit"
 	"bosh-softlayer-cpi/config"
 	cpiLog "bosh-softlayer-cpi/logger"
+	"bosh-softlayer-cpi/metrics"
 	"bosh-softlayer-cpi/softlayer/client"
+	boslconfig "bosh-softlayer-cpi/softlayer/config"
 	vpsClient "bosh-softlayer-cpi/softlayer/vps_service/client"
 	"bosh-softlayer-cpi/softlayer/vps_service/client/vm"
 )
 
-const logTagMain = "main"
+const (
+	logTagMain    = "main"
+	logTagMetrics = "metrics"
+)
 
 var (
 	configPathOpt = flag.String("configFile", "", "Path to configuration file")
+	socketPathOpt = flag.String("socket", "", "Serve requests on this Unix socket instead of stdin/stdout")
 )
 
 func main() {
-	logger, fs, uuid, outLogger := basicDeps()
-	cmdRunner := boshsys.NewExecCmdRunner(logger.GetBoshLogger())
+	os.Exit(run())
+}
+
+// run serves the CPI and returns the exit code, so that the log sinks are closed on every path
+func run() int {
+	logger, fs, uuid := basicDeps()
 	defer logger.HandlePanic("Main")
 
 	flag.Parse()
@@ -44,77 +58,308 @@ func main() {
 	cfg, err := config.NewConfigFromPath(*configPathOpt, fs)
 	if err != nil {
 		logger.Error(logTagMain, "Loading config %s", err.Error())
-		os.Exit(1)
+		return 1
 	}
 
-	dispatch := buildDispatcher(cfg, logger, outLogger, uuid, cmdRunner)
+	if err = cpiLog.SetExtraSecretPatterns(cfg.Cloud.Properties.Log.RedactPatterns); err != nil {
+		logger.Error(logTagMain, "Configuring log redaction %s", err.Error())
+		return 1
+	}
 
+	sinks, err := cpiLog.OpenSinks(cfg.Cloud.Properties.Log)
+	if err != nil {
+		logger.Error(logTagMain, "Configuring log %s", err.Error())
+		return 1
+	}
+	defer sinks.Close()
+
+	if *socketPathOpt != "" {
+		// The server answers no request itself, only the loggers of its requests collect a response log
+		logger.LogBuff = nil
+	}
+
+	logger, fs, outLogger := configuredDeps(logger, cfg.Cloud.Properties.Log, sinks)
+	cmdRunner := boshsys.NewExecCmdRunner(logger.GetBoshLogger())
+
+	trail, err := audit.OpenTrail(cfg.Cloud.Properties.Audit)
+	if err != nil {
+		logger.Error(logTagMain, "Opening audit trail %s", err.Error())
+		return 1
+	}
+
+	metricsSink := metrics.NewSink(cfg.Cloud.Properties.Metrics, fs)
+	conns := newSoftlayerConnections()
+
+	if *socketPathOpt != "" {
+		newDispatcher := requestDispatchers(cfg, sinks, uuid, cmdRunner, trail, metricsSink, conns)
+		err = serveSocket(*socketPathOpt, newDispatcher, logger)
+		if err != nil {
+			logger.Error(logTagMain, "Serving on socket %s", err)
+			return 1
+		}
+		return 0
+	}
+
+	dispatch := buildDispatcher(cfg, logger, outLogger, uuid, cmdRunner, trail, metricsSink, conns)
 	cli := transport.NewCLI(os.Stdin, os.Stdout, dispatch, logger)
 
 	err = cli.ServeOnce()
 	if err != nil {
 		logger.Error(logTagMain, "Serving once %s", err)
-		os.Exit(1)
+		return 1
+	}
+
+	return 0
+}
+
+// requestDispatchers builds the dispatcher of each request served on the socket. Config, audit trail,
+// sinks and connections are kept for the life of the server, each request gets its own logger,
+// response log and metrics recorder.
+func requestDispatchers(
+	cfg config.Config,
+	sinks cpiLog.Sinks,
+	uuidGen boshuuid.Generator,
+	cmdRunner boshsys.CmdRunner,
+	trail *audit.Trail,
+	metricsSink metrics.Sink,
+	conns *softlayerConnections,
+) transport.DispatcherFactory {
+	return func() dispatcher.Dispatcher {
+		reqLogger, _, reqOutLogger := configuredDeps(newMultiLogger(), cfg.Cloud.Properties.Log, sinks)
+		return buildDispatcher(cfg, reqLogger, reqOutLogger, uuidGen, cmdRunner, trail, metricsSink, conns)
 	}
 }
 
-func basicDeps() (cpiLog.Logger, boshsys.FileSystem, boshuuid.Generator, *log.Logger) {
+// serveSocket keeps the config and connections in memory and serves requests until the process
+// is terminated
+func serveSocket(path string, newDispatcher transport.DispatcherFactory, logger cpiLog.Logger) error {
+	socket := transport.NewSocket(path, newDispatcher, logger)
+	listener, err := socket.Listen()
+	if err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		logger.Info(logTagMain, "Received signal %s, stop serving on socket '%s'", sig, path)
+		listener.Close()
+	}()
+
+	logger.Info(logTagMain, "Serving on socket '%s'", path)
+	err = socket.Serve(listener)
+	if isClosedListenerErr(err) {
+		return nil
+	}
+
+	return err
+}
+
+func isClosedListenerErr(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "use of closed network connection")
+}
+
+func basicDeps() (api.MultiLogger, boshsys.FileSystem, boshuuid.Generator) {
+	multiLogger := newMultiLogger()
+	fs := boshsys.NewOsFileSystem(multiLogger.GetBoshLogger())
+
+	uuidGen := boshuuid.NewGenerator()
+
+	return multiLogger, fs, uuidGen
+}
+
+// newMultiLogger logs at debug level to stderr, collecting the log for the response in a new buffer
+// under a new serial prefix
+func newMultiLogger() api.MultiLogger {
 	var logBuff bytes.Buffer
-	multiWriter := io.MultiWriter(os.Stderr, bufio.NewWriter(&logBuff))
+	multiWriter := io.MultiWriter(os.Stderr, &logBuff)
 	nanos := fmt.Sprintf("%09d", time.Now().Nanosecond())
 
-	clientLogger := log.New(multiWriter, nanos, log.LstdFlags) // For softlayer_client
 	outLogger := log.New(multiWriter, "", log.LstdFlags)
-	errLogger := log.New(os.Stderr, "", log.LstdFlags)
+	errLogger := log.New(multiWriter, "", log.LstdFlags)
 
 	cpiLogger := cpiLog.New(boshlog.LevelDebug, nanos, outLogger, errLogger)
-	multiLogger := api.MultiLogger{Logger: cpiLogger, LogBuff: &logBuff}
+	return api.MultiLogger{Logger: cpiLogger, LogBuff: &logBuff}
+}
+
+// configuredDeps rebuilds the loggers with the format, level and sinks of the config, keeping
+// the serial prefix and the log collected for the response. The returned *log.Logger is for softlayer-go.
+func configuredDeps(logger api.MultiLogger, options cpiLog.Options, sinks cpiLog.Sinks) (api.MultiLogger, boshsys.FileSystem, *log.Logger) {
+	// Warnings and errors go to the response log too
+	console := io.Writer(os.Stderr)
+	if logger.LogBuff != nil {
+		console = io.MultiWriter(os.Stderr, logger.LogBuff)
+	}
+
+	outWriter := io.MultiWriter(append([]io.Writer{console}, sinks.Out...)...)
+	errWriter := io.MultiWriter(append([]io.Writer{console}, sinks.Err...)...)
+
+	var cpiLogger cpiLog.Logger
+	if options.Format == cpiLog.FormatJSON {
+		cpiLogger = cpiLog.NewJSONLogger(options.LogLevel(), logger.GetSerialTagPrefix(), outWriter, errWriter)
+	} else {
+		outLogger := log.New(outWriter, "", log.LstdFlags)
+		errLogger := log.New(errWriter, "", log.LstdFlags)
+		cpiLogger = cpiLog.New(options.LogLevel(), logger.GetSerialTagPrefix(), outLogger, errLogger)
+	}
+	multiLogger := api.MultiLogger{Logger: cpiLogger, LogBuff: logger.LogBuff}
 	fs := boshsys.NewOsFileSystem(cpiLogger.GetBoshLogger())
 
-	uuidGen := boshuuid.NewGenerator()
+	// softlayer-go logs its HTTP trace through a *log.Logger, turn each of its lines into a log line at the trace level
+	clientLogger := log.New(cpiLog.NewLineWriter(cpiLogger, client.SoftlayerGoLogTag, options.TraceLevel()), "", 0)
 
-	return multiLogger, fs, uuidGen, clientLogger
+	return multiLogger, fs, clientLogger
 }
 
 func buildDispatcher(
 	config config.Config,
-	logger cpiLog.Logger,
+	logger api.MultiLogger,
 	outLogger *log.Logger,
 	uuidGen boshuuid.Generator,
 	cmdRunner boshsys.CmdRunner,
+	trail *audit.Trail,
+	metricsSink metrics.Sink,
+	conns *softlayerConnections,
 ) dispatcher.Dispatcher {
-	var softlayerAPIEndpoint string
-	if config.Cloud.Properties.SoftLayer.ApiEndpoint != "" {
-		softlayerAPIEndpoint = config.Cloud.Properties.SoftLayer.ApiEndpoint
-	} else {
-		softlayerAPIEndpoint = client.SoftlayerAPIEndpointPublicDefault
+	recorder := metrics.NewRecorder()
+
+	// Requests may override the SoftLayer properties, so clients are built on demand on the connections
+	// of their properties
+	clientBuilder := func(softlayerConfig boslconfig.Config) client.Client {
+		return buildSoftlayerClient(softlayerConfig, conns.get(softlayerConfig), logger, outLogger, config.Cloud.Properties.Log.TraceSoftLayer(), recorder, trail)
+	}
+
+	actionFactory := action.NewConcreteFactory(
+		clientBuilder(config.Cloud.Properties.SoftLayer),
+		uuidGen,
+		config,
+		logger,
+	).WithClientBuilder(clientBuilder)
+
+	caller := dispatcher.NewJSONCaller()
+
+	requestLog := func() string {
+		return logger.RequestLog(config.Cloud.Properties.Log.ResponseLogMaxSize)
+	}
+
+	return metricsDispatcher{
+		Dispatcher: dispatcher.NewJSON(actionFactory, caller, logger).WithRequestLog(requestLog),
+		recorder:   recorder,
+		sink:       metricsSink,
+		logger:     logger,
+	}
+}
+
+// metricsDispatcher logs and exports the SoftLayer API calls and wait loops of each request
+type metricsDispatcher struct {
+	dispatcher.Dispatcher
+	recorder *metrics.Recorder
+	sink     metrics.Sink
+	logger   cpiLog.Logger
+}
+
+func (d metricsDispatcher) Dispatch(reqBytes []byte) []byte {
+	d.recorder.Reset()
+	respBytes := d.Dispatcher.Dispatch(reqBytes)
+
+	snapshot := d.recorder.Snapshot()
+	d.logger.Info(logTagMetrics, "%s", snapshot.Summary())
+	if err := d.sink.Export(snapshot); err != nil {
+		d.logger.Warn(logTagMetrics, "Exporting metrics: %s", err)
+	}
+
+	return respBytes
+}
+
+// softlayerConnections keeps the VPS and Swift connections for the life of the process, one per
+// account and endpoints, so that the requests served on a socket share them. Swift connections keep
+// their authentication token.
+type softlayerConnections struct {
+	lock  sync.Mutex
+	conns map[softlayerConnectionKey]softlayerConnection
+}
+
+type softlayerConnectionKey struct {
+	vpsAddress    string
+	swiftEndpoint string
+	swiftUsername string
+	apiKey        string
+}
+
+type softlayerConnection struct {
+	vps   *vm.Client
+	swift *swift.Connection
+}
+
+func newSoftlayerConnections() *softlayerConnections {
+	return &softlayerConnections{
+		conns: map[softlayerConnectionKey]softlayerConnection{},
 	}
+}
+
+// get returns the connections of the SoftLayer config, opening them on first use
+func (c *softlayerConnections) get(softlayerConfig boslconfig.Config) softlayerConnection {
+	key := softlayerConnectionKey{
+		swiftEndpoint: softlayerConfig.SwiftEndpoint,
+		swiftUsername: softlayerConfig.SwiftUsername,
+		apiKey:        softlayerConfig.ApiKey,
+	}
+	if softlayerConfig.EnableVps {
+		key.vpsAddress = fmt.Sprintf("%s:%d", softlayerConfig.VpsHost, softlayerConfig.VpsPort)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
-	softLayerClient := client.NewSoftlayerClientSession(softlayerAPIEndpoint, config.Cloud.Properties.SoftLayer.Username, config.Cloud.Properties.SoftLayer.ApiKey, true, 300, 3, 60, outLogger)
+	conn, found := c.conns[key]
+	if found {
+		return conn
+	}
 
-	var vps *vm.Client
-	if config.Cloud.Properties.SoftLayer.EnableVps {
-		vps = vpsClient.New(httptransport.New(fmt.Sprintf("%s:%d", config.Cloud.Properties.SoftLayer.VpsHost, config.Cloud.Properties.SoftLayer.VpsPort),
-			"v2", []string{"https"}), strfmt.Default).VM
+	if key.vpsAddress != "" {
+		conn.vps = vpsClient.New(httptransport.New(key.vpsAddress, "v2", []string{"https"}), strfmt.Default).VM
 	}
 
 	//Swift Object Storage
-	var swiftClient *swift.Connection
-	if config.Cloud.Properties.SoftLayer.SwiftEndpoint != "" {
-		swiftClient = client.NewSwiftClient(config.Cloud.Properties.SoftLayer.SwiftEndpoint, config.Cloud.Properties.SoftLayer.SwiftUsername, config.Cloud.Properties.SoftLayer.ApiKey, 120, 3)
+	if key.swiftEndpoint != "" {
+		conn.swift = client.NewSwiftClient(key.swiftEndpoint, key.swiftUsername, key.apiKey, 120, 3)
 	}
 
-	repClientFactory := client.NewClientFactory(client.NewSoftLayerClientManager(softLayerClient, vps, swiftClient, logger))
-	cli := repClientFactory.CreateClient()
+	c.conns[key] = conn
+	return conn
+}
 
-	actionFactory := action.NewConcreteFactory(
-		cli,
-		uuidGen,
-		config,
-		logger,
-	)
+// buildSoftlayerClient builds the client of a request on shared connections. The session is cheap
+// and of the request only, so that it traces to the log and records to the metrics of the request.
+func buildSoftlayerClient(
+	softlayerConfig boslconfig.Config,
+	conn softlayerConnection,
+	logger cpiLog.Logger,
+	outLogger *log.Logger,
+	trace bool,
+	recorder *metrics.Recorder,
+	trail *audit.Trail,
+) client.Client {
+	var softlayerAPIEndpoint string
+	if softlayerConfig.ApiEndpoint != "" {
+		softlayerAPIEndpoint = softlayerConfig.ApiEndpoint
+	} else {
+		softlayerAPIEndpoint = client.SoftlayerAPIEndpointPublicDefault
+	}
 
-	caller := dispatcher.NewJSONCaller()
+	// The session traces through a logger of its own, writing to the log of the request that built it
+	sessionLogger := log.New(outLogger.Writer(), logger.GetSerialTagPrefix(), outLogger.Flags())
+	softLayerClient := client.NewSoftlayerClientSession(softlayerAPIEndpoint, softlayerConfig.Username, softlayerConfig.ApiKey, trace, 300, 3, 60, sessionLogger)
+	// Rate limited retries are recorded as separate calls
+	transportHandler := client.NewMetricsTransportHandler(softLayerClient.TransportHandler, recorder)
+	softLayerClient.TransportHandler = client.NewRetryTransportHandler(transportHandler, softlayerConfig.Retry, logger)
 
-	return dispatcher.NewJSON(actionFactory, caller, logger)
+	clientManager := client.NewSoftLayerClientManager(softLayerClient, conn.vps, conn.swift, logger).
+		WithTimeouts(softlayerConfig.Timeouts).
+		WithRetryPolicy(softlayerConfig.Retry).
+		WithMetrics(recorder).
+		WithAudit(trail)
+	repClientFactory := client.NewClientFactory(clientManager)
+	return repClientFactory.CreateClient()
 }
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	boshuuid "github.com/cloudfoundry/bosh-utils/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"bosh-softlayer-cpi/api/dispatcher"
+	"bosh-softlayer-cpi/api/transport"
+	"bosh-softlayer-cpi/config"
+	cpiLog "bosh-softlayer-cpi/logger"
+	"bosh-softlayer-cpi/metrics"
+	boslconfig "bosh-softlayer-cpi/softlayer/config"
+)
+
+var _ = Describe("configuredDeps", func() {
+	It("collects warnings and errors in the response log", func() {
+		logger, _, _ := configuredDeps(newMultiLogger(), cpiLog.Options{}, cpiLog.Sinks{})
+
+		logger.Debug("fake-tag", "fake-debug-message")
+		logger.Warn("fake-tag", "fake-warn-message")
+		logger.Error("fake-tag", "fake-error-message")
+
+		Expect(logger.LogBuff.String()).To(ContainSubstring("fake-debug-message"))
+		Expect(logger.LogBuff.String()).To(ContainSubstring("fake-warn-message"))
+		Expect(logger.LogBuff.String()).To(ContainSubstring("fake-error-message"))
+	})
+
+	It("collects warnings in the response log of the basic logger", func() {
+		logger := newMultiLogger()
+
+		logger.Warn("fake-tag", "fake-warn-message")
+
+		Expect(logger.LogBuff.String()).To(ContainSubstring("fake-warn-message"))
+	})
+
+	It("collects warnings in the response log with the json format", func() {
+		logger, _, _ := configuredDeps(newMultiLogger(), cpiLog.Options{Format: cpiLog.FormatJSON}, cpiLog.Sinks{})
+
+		logger.Warn("fake-tag", "fake-warn-message")
+
+		Expect(logger.LogBuff.String()).To(ContainSubstring("fake-warn-message"))
+	})
+})
+
+var _ = Describe("requestDispatchers", func() {
+	var (
+		server     *ghttp.Server
+		tmpDir     string
+		socketPath string
+		listener   net.Listener
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		vgBytes, err := ioutil.ReadFile(filepath.Join("..", "test_fixtures", "services", "SoftLayer_Virtual_Guest_getObject.json"))
+		Expect(err).ToNot(HaveOccurred())
+		server.RouteToHandler("GET", regexp.MustCompile(`/SoftLayer_Virtual_Guest/\d+\.json`), ghttp.RespondWith(http.StatusOK, vgBytes))
+
+		cfg := config.Config{
+			Cloud: config.Cloud{
+				Plugin: "softlayer",
+				Properties: config.CPIProperties{
+					SoftLayer: boslconfig.Config{
+						Username:    "fake-username",
+						ApiKey:      "fake-api-key",
+						ApiEndpoint: server.URL(),
+					},
+					Log: cpiLog.Options{Level: "debug", SoftLayerTraceLevel: "debug"},
+				},
+			},
+		}
+		logger := cpiLog.NewLogger(boshlog.LevelNone, "")
+		cmdRunner := boshsys.NewExecCmdRunner(logger.GetBoshLogger())
+		metricsSink := metrics.NewSink(metrics.Options{}, boshsys.NewOsFileSystem(logger.GetBoshLogger()))
+
+		tmpDir, err = ioutil.TempDir("", "cpi-main")
+		Expect(err).ToNot(HaveOccurred())
+		socketPath = filepath.Join(tmpDir, "cpi.sock")
+
+		newDispatcher := requestDispatchers(cfg, cpiLog.Sinks{}, boshuuid.NewGenerator(), cmdRunner, nil, metricsSink, newSoftlayerConnections())
+		socket := transport.NewSocket(socketPath, newDispatcher, logger)
+		listener, err = socket.Listen()
+		Expect(err).ToNot(HaveOccurred())
+		go socket.Serve(listener)
+	})
+
+	AfterEach(func() {
+		listener.Close()
+		os.RemoveAll(tmpDir)
+		server.Close()
+	})
+
+	// Run with -race to catch state shared by the requests
+	It("serves concurrent requests on the socket, each with its own log", func() {
+		var wg sync.WaitGroup
+		responses := make([]dispatcher.Response, 10)
+		errs := make([]error, len(responses))
+
+		for i := range responses {
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				req := fmt.Sprintf(`{"method":"has_vm","arguments":["%d"],"context":{"director_uuid":"fake-director-uuid","request_id":"fake-request-%d"}}`, 1000+i, i)
+				out := &bytes.Buffer{}
+				if errs[i] = transport.Forward(socketPath, strings.NewReader(req), out); errs[i] == nil {
+					errs[i] = json.Unmarshal(out.Bytes(), &responses[i])
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		for i, resp := range responses {
+			Expect(errs[i]).ToNot(HaveOccurred())
+			Expect(resp.Error).To(BeNil())
+			Expect(resp.Result).To(BeTrue())
+			Expect(resp.Log).To(ContainSubstring(fmt.Sprintf("SoftLayer_Virtual_Guest::getObject id=%d", 1000+i)))
+			for j := range responses {
+				if j != i {
+					Expect(resp.Log).ToNot(ContainSubstring(fmt.Sprintf("getObject id=%d", 1000+j)))
+				}
+			}
+		}
+		Expect(server.ReceivedRequests()).To(HaveLen(len(responses)))
+	})
+})
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"net"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+)
+
+const DefaultPrefix = "softlayer_cpi"
+
+type Options struct {
+	// Prometheus text file updated after each request, e.g. in the directory of node-exporter's textfile collector
+	Textfile string `json:"textfile"`
+	// host:port of a statsd server receiving the metrics of each request over UDP
+	Statsd string `json:"statsd"`
+	// Prefix of the metric names, softlayer_cpi by default
+	Prefix string `json:"prefix"`
+}
+
+func (o Options) Validate() error {
+	if o.Statsd != "" {
+		if _, _, err := net.SplitHostPort(o.Statsd); err != nil {
+			return bosherr.WrapErrorf(err, "Parsing statsd address '%s'", o.Statsd)
+		}
+	}
+
+	return nil
+}
+
+// Sink exports the metrics of a request
+type Sink interface {
+	Export(snapshot Snapshot) error
+}
+
+type multiSink []Sink
+
+func (s multiSink) Export(snapshot Snapshot) error {
+	for _, sink := range s {
+		if err := sink.Export(snapshot); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NewSink returns a sink exporting to every target set in the options, or to none
+func NewSink(options Options, fs boshsys.FileSystem) Sink {
+	prefix := options.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+
+	sinks := multiSink{}
+	if options.Textfile != "" {
+		sinks = append(sinks, NewTextfileSink(options.Textfile, prefix, fs))
+	}
+	if options.Statsd != "" {
+		sinks = append(sinks, NewStatsdSink(options.Statsd, prefix))
+	}
+
+	return sinks
+}
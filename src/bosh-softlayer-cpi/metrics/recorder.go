@@ -0,0 +1,220 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Recorder collects the SoftLayer API calls and the wait loops of a CPI request.
+// A nil Recorder records nothing.
+type Recorder struct {
+	mutex sync.Mutex
+	calls map[callKey]*CallStats
+	waits map[string]*WaitStats
+}
+
+type callKey struct {
+	service string
+	method  string
+}
+
+// Timings are the durations observed for a call or a wait loop
+type Timings []time.Duration
+
+func (t Timings) Count() int {
+	return len(t)
+}
+
+func (t Timings) Total() time.Duration {
+	var total time.Duration
+	for _, d := range t {
+		total += d
+	}
+	return total
+}
+
+func (t Timings) Max() time.Duration {
+	var max time.Duration
+	for _, d := range t {
+		if d > max {
+			max = d
+		}
+	}
+	return max
+}
+
+type CallStats struct {
+	Service string
+	Method  string
+	Timings Timings
+	// Failed calls by error class
+	Errors map[string]int
+}
+
+type WaitStats struct {
+	Name     string
+	Timings  Timings
+	Failures int
+}
+
+// Snapshot holds the calls sorted by service and method, and the waits sorted by name
+type Snapshot struct {
+	Calls []CallStats
+	Waits []WaitStats
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{
+		calls: map[callKey]*CallStats{},
+		waits: map[string]*WaitStats{},
+	}
+}
+
+// ObserveCall records a call to a SoftLayer API method, with the class of its error if it failed
+func (r *Recorder) ObserveCall(service string, method string, duration time.Duration, errorClass string) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := callKey{service, method}
+	stats, found := r.calls[key]
+	if !found {
+		stats = &CallStats{Service: service, Method: method, Errors: map[string]int{}}
+		r.calls[key] = stats
+	}
+
+	stats.Timings = append(stats.Timings, duration)
+	if errorClass != "" {
+		stats.Errors[errorClass]++
+	}
+}
+
+// ObserveWait records how long a wait loop took, and whether it gave up
+func (r *Recorder) ObserveWait(name string, duration time.Duration, failed bool) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stats, found := r.waits[name]
+	if !found {
+		stats = &WaitStats{Name: name}
+		r.waits[name] = stats
+	}
+
+	stats.Timings = append(stats.Timings, duration)
+	if failed {
+		stats.Failures++
+	}
+}
+
+// Reset forgets everything recorded so far, e.g. before serving the next request
+func (r *Recorder) Reset() {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.calls = map[callKey]*CallStats{}
+	r.waits = map[string]*WaitStats{}
+}
+
+func (r *Recorder) Snapshot() Snapshot {
+	snapshot := Snapshot{Calls: []CallStats{}, Waits: []WaitStats{}}
+	if r == nil {
+		return snapshot
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, stats := range r.calls {
+		call := *stats
+		call.Timings = append(Timings{}, stats.Timings...)
+		call.Errors = map[string]int{}
+		for class, count := range stats.Errors {
+			call.Errors[class] = count
+		}
+		snapshot.Calls = append(snapshot.Calls, call)
+	}
+	sort.Slice(snapshot.Calls, func(i, j int) bool {
+		if snapshot.Calls[i].Service != snapshot.Calls[j].Service {
+			return snapshot.Calls[i].Service < snapshot.Calls[j].Service
+		}
+		return snapshot.Calls[i].Method < snapshot.Calls[j].Method
+	})
+
+	for _, stats := range r.waits {
+		wait := *stats
+		wait.Timings = append(Timings{}, stats.Timings...)
+		snapshot.Waits = append(snapshot.Waits, wait)
+	}
+	sort.Slice(snapshot.Waits, func(i, j int) bool {
+		return snapshot.Waits[i].Name < snapshot.Waits[j].Name
+	})
+
+	return snapshot
+}
+
+// Summary describes the snapshot for the CPI log, the calls that took longest first
+func (s Snapshot) Summary() string {
+	var count, failed int
+	var total time.Duration
+	for _, call := range s.Calls {
+		count += call.Timings.Count()
+		total += call.Timings.Total()
+		for _, n := range call.Errors {
+			failed += n
+		}
+	}
+
+	lines := []string{fmt.Sprintf("%d SoftLayer API calls in %s, %d failed", count, total, failed)}
+
+	calls := append([]CallStats{}, s.Calls...)
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].Timings.Total() > calls[j].Timings.Total()
+	})
+	for _, call := range calls {
+		line := fmt.Sprintf("  %s::%s count=%d total=%s max=%s", call.Service, call.Method, call.Timings.Count(), call.Timings.Total(), call.Timings.Max())
+		if len(call.Errors) > 0 {
+			line += " errors=" + formatErrors(call.Errors)
+		}
+		lines = append(lines, line)
+	}
+
+	for _, wait := range s.Waits {
+		lines = append(lines, fmt.Sprintf("  wait %s count=%d total=%s failed=%d", wait.Name, wait.Timings.Count(), wait.Timings.Total(), wait.Failures))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func formatErrors(errors map[string]int) string {
+	classes := sortedClasses(errors)
+	counts := make([]string, len(classes))
+	for i, class := range classes {
+		counts[i] = fmt.Sprintf("%s:%d", class, errors[class])
+	}
+
+	return strings.Join(counts, ",")
+}
+
+func sortedClasses(errors map[string]int) []string {
+	classes := make([]string, 0, len(errors))
+	for class := range errors {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	return classes
+}
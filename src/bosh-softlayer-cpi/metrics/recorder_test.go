@@ -0,0 +1,76 @@
+package metrics_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"time"
+
+	"bosh-softlayer-cpi/metrics"
+)
+
+var _ = Describe("Recorder", func() {
+	var recorder *metrics.Recorder
+
+	BeforeEach(func() {
+		recorder = metrics.NewRecorder()
+	})
+
+	It("groups the calls by service and method", func() {
+		recorder.ObserveCall("SoftLayer_Virtual_Guest", "getObject", 2*time.Second, "")
+		recorder.ObserveCall("SoftLayer_Account", "getCurrentUser", time.Second, "")
+		recorder.ObserveCall("SoftLayer_Virtual_Guest", "getObject", 3*time.Second, "not_found")
+
+		snapshot := recorder.Snapshot()
+		Expect(snapshot.Calls).To(HaveLen(2))
+		Expect(snapshot.Calls[0].Service).To(Equal("SoftLayer_Account"))
+
+		call := snapshot.Calls[1]
+		Expect(call.Method).To(Equal("getObject"))
+		Expect(call.Timings.Count()).To(Equal(2))
+		Expect(call.Timings.Total()).To(Equal(5 * time.Second))
+		Expect(call.Timings.Max()).To(Equal(3 * time.Second))
+		Expect(call.Errors).To(Equal(map[string]int{"not_found": 1}))
+	})
+
+	It("records the wait loops and whether they gave up", func() {
+		recorder.ObserveWait("WaitOrderCompleted", time.Minute, false)
+		recorder.ObserveWait("WaitOrderCompleted", 2*time.Minute, true)
+
+		snapshot := recorder.Snapshot()
+		Expect(snapshot.Waits).To(HaveLen(1))
+		Expect(snapshot.Waits[0].Timings.Total()).To(Equal(3 * time.Minute))
+		Expect(snapshot.Waits[0].Failures).To(Equal(1))
+	})
+
+	It("forgets everything on reset", func() {
+		recorder.ObserveCall("SoftLayer_Account", "getCurrentUser", time.Second, "")
+		recorder.ObserveWait("WaitOrderCompleted", time.Minute, false)
+		recorder.Reset()
+
+		snapshot := recorder.Snapshot()
+		Expect(snapshot.Calls).To(BeEmpty())
+		Expect(snapshot.Waits).To(BeEmpty())
+	})
+
+	It("records nothing when nil", func() {
+		var nilRecorder *metrics.Recorder
+		nilRecorder.ObserveCall("SoftLayer_Account", "getCurrentUser", time.Second, "")
+		nilRecorder.ObserveWait("WaitOrderCompleted", time.Minute, false)
+
+		Expect(nilRecorder.Snapshot().Calls).To(BeEmpty())
+	})
+
+	It("summarizes the calls that took longest first", func() {
+		recorder.ObserveCall("SoftLayer_Account", "getCurrentUser", time.Second, "")
+		recorder.ObserveCall("SoftLayer_Virtual_Guest", "getObject", 3*time.Second, "server_error")
+		recorder.ObserveWait("WaitOrderCompleted", time.Minute, false)
+
+		Expect(recorder.Snapshot().Summary()).To(Equal(
+			"2 SoftLayer API calls in 4s, 1 failed\n" +
+				"  SoftLayer_Virtual_Guest::getObject count=1 total=3s max=3s errors=server_error:1\n" +
+				"  SoftLayer_Account::getCurrentUser count=1 total=1s max=1s\n" +
+				"  wait WaitOrderCompleted count=1 total=1m0s failed=0",
+		))
+	})
+})
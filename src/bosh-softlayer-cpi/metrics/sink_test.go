@@ -0,0 +1,113 @@
+package metrics_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+
+	"bosh-softlayer-cpi/metrics"
+)
+
+var _ = Describe("Sinks", func() {
+	var snapshot metrics.Snapshot
+
+	BeforeEach(func() {
+		recorder := metrics.NewRecorder()
+		recorder.ObserveCall("SoftLayer_Virtual_Guest", "getObject", 1500*time.Millisecond, "")
+		recorder.ObserveCall("SoftLayer_Virtual_Guest", "getObject", 500*time.Millisecond, "rate_limited")
+		recorder.ObserveWait("WaitOrderCompleted", time.Minute, true)
+		snapshot = recorder.Snapshot()
+	})
+
+	Describe("textfile", func() {
+		var fs *fakesys.FakeFileSystem
+
+		BeforeEach(func() {
+			fs = fakesys.NewFakeFileSystem()
+		})
+
+		It("writes the metrics in the Prometheus text format", func() {
+			sink := metrics.NewSink(metrics.Options{Textfile: "/metrics/cpi.prom"}, fs)
+			Expect(sink.Export(snapshot)).To(Succeed())
+
+			content, err := fs.ReadFileString("/metrics/cpi.prom")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(ContainSubstring("# TYPE softlayer_cpi_api_calls_total counter\n"))
+			Expect(content).To(ContainSubstring(`softlayer_cpi_api_calls_total{service="SoftLayer_Virtual_Guest",method="getObject"} 2` + "\n"))
+			Expect(content).To(ContainSubstring(`softlayer_cpi_api_call_errors_total{service="SoftLayer_Virtual_Guest",method="getObject",class="rate_limited"} 1` + "\n"))
+			Expect(content).To(ContainSubstring(`softlayer_cpi_api_call_duration_seconds_sum{service="SoftLayer_Virtual_Guest",method="getObject"} 2` + "\n"))
+			Expect(content).To(ContainSubstring(`softlayer_cpi_wait_duration_seconds_count{wait="WaitOrderCompleted"} 1` + "\n"))
+			Expect(content).To(ContainSubstring(`softlayer_cpi_wait_failures_total{wait="WaitOrderCompleted"} 1` + "\n"))
+			Expect(fs.FileExists("/metrics/cpi.prom.tmp")).To(BeFalse())
+		})
+
+		It("adds to the counters already in the file", func() {
+			sink := metrics.NewSink(metrics.Options{Textfile: "/metrics/cpi.prom", Prefix: "cpi"}, fs)
+			Expect(sink.Export(snapshot)).To(Succeed())
+			Expect(sink.Export(snapshot)).To(Succeed())
+
+			content, err := fs.ReadFileString("/metrics/cpi.prom")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(ContainSubstring(`cpi_api_calls_total{service="SoftLayer_Virtual_Guest",method="getObject"} 4` + "\n"))
+			Expect(content).To(ContainSubstring(`cpi_wait_duration_seconds_sum{wait="WaitOrderCompleted"} 120` + "\n"))
+			Expect(strings.Count(content, "# TYPE cpi_api_calls_total")).To(Equal(1))
+		})
+
+		It("keeps the counters of concurrent exports", func() {
+			sink := metrics.NewSink(metrics.Options{Textfile: "/metrics/cpi.prom", Prefix: "cpi"}, fs)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					Expect(sink.Export(snapshot)).To(Succeed())
+				}()
+			}
+			wg.Wait()
+
+			content, err := fs.ReadFileString("/metrics/cpi.prom")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(ContainSubstring(`cpi_api_calls_total{service="SoftLayer_Virtual_Guest",method="getObject"} 20` + "\n"))
+		})
+	})
+
+	Describe("statsd", func() {
+		It("sends counters and a timing per call over UDP", func() {
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+
+			sink := metrics.NewSink(metrics.Options{Statsd: conn.LocalAddr().String()}, fakesys.NewFakeFileSystem())
+			Expect(sink.Export(snapshot)).To(Succeed())
+
+			buf := make([]byte, 2048)
+			conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			n, _, err := conn.ReadFrom(buf)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strings.Split(string(buf[:n]), "\n")).To(Equal([]string{
+				"softlayer_cpi.api.SoftLayer_Virtual_Guest.getObject.calls:2|c",
+				"softlayer_cpi.api.SoftLayer_Virtual_Guest.getObject.errors.rate_limited:1|c",
+				"softlayer_cpi.api.SoftLayer_Virtual_Guest.getObject.duration:1500.000|ms",
+				"softlayer_cpi.api.SoftLayer_Virtual_Guest.getObject.duration:500.000|ms",
+				"softlayer_cpi.wait.WaitOrderCompleted.duration:60000.000|ms",
+				"softlayer_cpi.wait.WaitOrderCompleted.failures:1|c",
+			}))
+		})
+	})
+
+	Describe("Options", func() {
+		It("rejects a statsd address without port", func() {
+			err := metrics.Options{Statsd: "localhost"}.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Parsing statsd address 'localhost'"))
+		})
+	})
+})
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"time"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// Statsd servers commonly read datagrams of up to this size
+const statsdMaxPacketSize = 1432
+
+// statsdSink sends the metrics of each request to a statsd server, one timing per call and wait
+type statsdSink struct {
+	address string
+	prefix  string
+}
+
+func NewStatsdSink(address string, prefix string) Sink {
+	return statsdSink{address: address, prefix: prefix}
+}
+
+func (s statsdSink) Export(snapshot Snapshot) error {
+	lines := s.lines(snapshot)
+	if len(lines) == 0 {
+		return nil
+	}
+
+	conn, err := net.Dial("udp", s.address)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Connecting to statsd at '%s'", s.address)
+	}
+	defer conn.Close()
+
+	var packet bytes.Buffer
+	flush := func() error {
+		if packet.Len() == 0 {
+			return nil
+		}
+		_, err := conn.Write(packet.Bytes())
+		packet.Reset()
+		return err
+	}
+
+	for _, line := range lines {
+		if packet.Len() > 0 && packet.Len()+1+len(line) > statsdMaxPacketSize {
+			if err := flush(); err != nil {
+				return bosherr.WrapErrorf(err, "Sending metrics to statsd at '%s'", s.address)
+			}
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+
+	if err := flush(); err != nil {
+		return bosherr.WrapErrorf(err, "Sending metrics to statsd at '%s'", s.address)
+	}
+
+	return nil
+}
+
+func (s statsdSink) lines(snapshot Snapshot) []string {
+	lines := []string{}
+	for _, call := range snapshot.Calls {
+		name := fmt.Sprintf("%s.api.%s.%s", s.prefix, call.Service, call.Method)
+		lines = append(lines, fmt.Sprintf("%s.calls:%d|c", name, call.Timings.Count()))
+		for _, class := range sortedClasses(call.Errors) {
+			lines = append(lines, fmt.Sprintf("%s.errors.%s:%d|c", name, class, call.Errors[class]))
+		}
+		for _, d := range call.Timings {
+			lines = append(lines, fmt.Sprintf("%s.duration:%s|ms", name, milliseconds(d)))
+		}
+	}
+
+	for _, wait := range snapshot.Waits {
+		name := fmt.Sprintf("%s.wait.%s", s.prefix, wait.Name)
+		for _, d := range wait.Timings {
+			lines = append(lines, fmt.Sprintf("%s.duration:%s|ms", name, milliseconds(d)))
+		}
+		if wait.Failures > 0 {
+			lines = append(lines, fmt.Sprintf("%s.failures:%d|c", name, wait.Failures))
+		}
+	}
+
+	return lines
+}
+
+func milliseconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", float64(d)/float64(time.Millisecond))
+}
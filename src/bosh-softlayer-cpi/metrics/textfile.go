@@ -0,0 +1,175 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+)
+
+// Families written to the text file, in the order of textfileSink.families
+const (
+	callsFamily = iota
+	callErrorsFamily
+	callDurationFamily
+	waitDurationFamily
+	waitFailuresFamily
+)
+
+type family struct {
+	name  string
+	kind  string
+	help  string
+	names []string
+}
+
+// textfileSink keeps the counters of a Prometheus text file, adding the metrics of each request
+// to the ones already in the file, since every CPI call may run in a new process
+type textfileSink struct {
+	path     string
+	fs       boshsys.FileSystem
+	families []family
+
+	// Requests served concurrently by the socket server share the sink
+	lock *sync.Mutex
+}
+
+func NewTextfileSink(path string, prefix string, fs boshsys.FileSystem) Sink {
+	newFamily := func(name string, kind string, help string) family {
+		name = prefix + "_" + name
+		names := []string{name}
+		if kind == "summary" {
+			names = []string{name + "_sum", name + "_count"}
+		}
+		return family{name: name, kind: kind, help: help, names: names}
+	}
+
+	return textfileSink{
+		path: path,
+		fs:   fs,
+		lock: &sync.Mutex{},
+		families: []family{
+			newFamily("api_calls_total", "counter", "SoftLayer API calls made by the CPI."),
+			newFamily("api_call_errors_total", "counter", "Failed SoftLayer API calls by error class."),
+			newFamily("api_call_duration_seconds", "summary", "Duration of the SoftLayer API calls."),
+			newFamily("wait_duration_seconds", "summary", "Duration of the loops waiting on SoftLayer."),
+			newFamily("wait_failures_total", "counter", "Loops waiting on SoftLayer that gave up."),
+		},
+	}
+}
+
+func (s textfileSink) Export(snapshot Snapshot) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	series := map[string]float64{}
+	if s.fs.FileExists(s.path) {
+		content, err := s.fs.ReadFileString(s.path)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Reading metrics file '%s'", s.path)
+		}
+		series = s.parse(content)
+	}
+
+	for _, call := range snapshot.Calls {
+		labels := fmt.Sprintf(`service="%s",method="%s"`, call.Service, call.Method)
+		series[s.series(callsFamily, 0, labels)] += float64(call.Timings.Count())
+		for class, count := range call.Errors {
+			series[s.series(callErrorsFamily, 0, fmt.Sprintf(`%s,class="%s"`, labels, class))] += float64(count)
+		}
+		series[s.series(callDurationFamily, 0, labels)] += call.Timings.Total().Seconds()
+		series[s.series(callDurationFamily, 1, labels)] += float64(call.Timings.Count())
+	}
+
+	for _, wait := range snapshot.Waits {
+		labels := fmt.Sprintf(`wait="%s"`, wait.Name)
+		series[s.series(waitDurationFamily, 0, labels)] += wait.Timings.Total().Seconds()
+		series[s.series(waitDurationFamily, 1, labels)] += float64(wait.Timings.Count())
+		if wait.Failures > 0 {
+			series[s.series(waitFailuresFamily, 0, labels)] += float64(wait.Failures)
+		}
+	}
+
+	// Write next to the file and rename it, so that the collector never reads half a file
+	tmpPath := s.path + ".tmp"
+	if err := s.fs.WriteFileString(tmpPath, s.render(series)); err != nil {
+		return bosherr.WrapErrorf(err, "Writing metrics file '%s'", tmpPath)
+	}
+
+	if err := s.fs.Rename(tmpPath, s.path); err != nil {
+		return bosherr.WrapErrorf(err, "Renaming metrics file to '%s'", s.path)
+	}
+
+	return nil
+}
+
+// series names a series of the family, name picking _sum or _count for summaries
+func (s textfileSink) series(family int, name int, labels string) string {
+	return fmt.Sprintf("%s{%s}", s.families[family].names[name], labels)
+}
+
+// parse reads back the series of the families this sink writes, ignoring any other line
+func (s textfileSink) parse(content string) map[string]float64 {
+	series := map[string]float64{}
+	for _, line := range strings.Split(content, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		i := strings.LastIndex(line, " ")
+		if i < 0 || s.familyOf(line[:i]) < 0 {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(line[i+1:], 64)
+		if err != nil {
+			continue
+		}
+		series[line[:i]] = value
+	}
+
+	return series
+}
+
+func (s textfileSink) familyOf(series string) int {
+	name := series
+	if i := strings.Index(series, "{"); i >= 0 {
+		name = series[:i]
+	}
+
+	for i, family := range s.families {
+		for _, familyName := range family.names {
+			if name == familyName {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func (s textfileSink) render(series map[string]float64) string {
+	keys := make([]string, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var out bytes.Buffer
+	for i, family := range s.families {
+		fmt.Fprintf(&out, "# HELP %s %s\n", family.name, family.help)
+		fmt.Fprintf(&out, "# TYPE %s %s\n", family.name, family.kind)
+		for _, key := range keys {
+			if s.familyOf(key) == i {
+				fmt.Fprintf(&out, "%s %s\n", key, strconv.FormatFloat(series[key], 'g', -1, 64))
+			}
+		}
+	}
+
+	return out.String()
+}
@@ -10,6 +10,7 @@ type SoftlayerUserData struct {
 	Server   SoftlayerUserDataServerName       `json:"server"`
 	Registry SoftlayerUserDataRegistryEndpoint `json:"registry"`
 	DNS      SoftlayerUserDataDNSItems         `json:"dns,omitempty"`
+	Settings *AgentSettings                    `json:"settings,omitempty"`
 }
 
 type SoftlayerUserDataServerName struct {
@@ -80,6 +81,12 @@ func (o ClientOptions) EndpointWithCredentials() string {
 	return fmt.Sprintf("%s://%s:%s@%s:%d", "http", o.HTTPOptions.User, o.HTTPOptions.Password, o.Address, o.HTTPOptions.Port)
 }
 
+// IsConfigured indicates whether a BOSH Registry has been configured. When it is not, the agent
+// settings are delivered through the instance user metadata.
+func (o ClientOptions) IsConfigured() bool {
+	return o.Address != "" || o.Endpoint != ""
+}
+
 // Validate validates the Client options.
 func (o ClientOptions) Validate() error {
 	if o.Protocol == "" {
@@ -0,0 +1,109 @@
+package registry
+
+import (
+	"strconv"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	"bosh-softlayer-cpi/logger"
+)
+
+const metadataClientLogTag = "RegistryMetadataClient"
+
+// UserDataStore reads and writes the user metadata of a SoftLayer instance.
+type UserDataStore interface {
+	GetUserDataWithID(id int) (*SoftlayerUserData, bool, error)
+	SetUserDataWithID(id int, userData *SoftlayerUserData) error
+}
+
+// MetadataClient represents a BOSH Registry Client which keeps the agent settings
+// in the user metadata of the instance instead of a BOSH Registry.
+type MetadataClient struct {
+	store  UserDataStore
+	logger logger.Logger
+}
+
+// NewMetadataClient creates a new user metadata backed BOSH Registry Client.
+func NewMetadataClient(
+	store UserDataStore,
+	logger logger.Logger,
+) MetadataClient {
+	return MetadataClient{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Delete deletes the instance settings for a given instance ID. The user metadata goes away
+// together with the instance, so there is nothing to clean up.
+func (c MetadataClient) Delete(instanceID string) error {
+	c.logger.Debug(metadataClientLogTag, "Skipping deletion of agent settings in user metadata of instance '%s'", instanceID)
+	return nil
+}
+
+// Fetch gets the agent settings for a given instance ID.
+func (c MetadataClient) Fetch(instanceID string) (AgentSettings, error) {
+	c.logger.Debug(metadataClientLogTag, "Fetching agent settings from user metadata of instance '%s'", instanceID)
+
+	userData, err := c.userData(instanceID)
+	if err != nil {
+		return AgentSettings{}, err
+	}
+
+	if userData.Settings == nil {
+		return AgentSettings{}, bosherr.Errorf("User metadata of instance '%s' does not contain agent settings", instanceID)
+	}
+
+	return *userData.Settings, nil
+}
+
+// IsExist indicates whether the agent settings for a given instance ID exists.
+func (c MetadataClient) IsExist(instanceID string) (bool, error) {
+	userData, err := c.userData(instanceID)
+	if err != nil {
+		return false, err
+	}
+
+	return userData.Settings != nil, nil
+}
+
+// Update updates the agent settings for a given instance ID, keeping the rest of the user metadata.
+func (c MetadataClient) Update(instanceID string, agentSettings AgentSettings) error {
+	c.logger.Debug(metadataClientLogTag, "Updating agent settings in user metadata of instance '%s'", instanceID)
+
+	id, err := strconv.Atoi(instanceID)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Parsing instance id '%s'", instanceID)
+	}
+
+	userData, err := c.userData(instanceID)
+	if err != nil {
+		return err
+	}
+
+	userData.Settings = &agentSettings
+	if err = c.store.SetUserDataWithID(id, userData); err != nil {
+		return bosherr.WrapErrorf(err, "Updating agent settings in user metadata of instance '%s'", instanceID)
+	}
+
+	c.logger.Debug(metadataClientLogTag, "Updated agent settings in user metadata of instance '%s'", instanceID)
+	return nil
+}
+
+func (c MetadataClient) userData(instanceID string) (*SoftlayerUserData, error) {
+	id, err := strconv.Atoi(instanceID)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Parsing instance id '%s'", instanceID)
+	}
+
+	userData, found, err := c.store.GetUserDataWithID(id)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Fetching user metadata of instance '%s'", instanceID)
+	}
+
+	if !found {
+		return nil, bosherr.Errorf("Instance '%s' not found", instanceID)
+	}
+
+	return userData, nil
+}
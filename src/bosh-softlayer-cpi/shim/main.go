@@ -0,0 +1,26 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"bosh-softlayer-cpi/api/transport"
+)
+
+var (
+	socketPathOpt = flag.String("socket", "/var/vcap/sys/run/softlayer_cpi/cpi.sock", "Unix socket of the CPI server")
+	_             = flag.String("configFile", "", "Ignored, the CPI server holds the configuration")
+)
+
+// The shim stands in for the CPI binary: it forwards the request on stdin to a CPI started with
+// -socket and writes the response to stdout.
+func main() {
+	flag.Parse()
+
+	err := transport.Forward(*socketPathOpt, os.Stdin, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Forwarding request to CPI server: %s\n", err)
+		os.Exit(1)
+	}
+}
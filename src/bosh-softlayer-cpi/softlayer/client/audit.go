@@ -0,0 +1,140 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+
+	"bosh-softlayer-cpi/audit"
+)
+
+const (
+	auditLogTag = "BillingAudit"
+
+	instanceAuditMask = "id, tagReferences[tag[name]], billingItem[id, categoryCode, description, hourlyRecurringFee, recurringFee, " +
+		"orderItem[order[id]], children[categoryCode, description, hourlyRecurringFee, recurringFee]]"
+)
+
+// WithAudit returns a copy of the client manager recording its orders and cancellations in the audit trail
+func (c *ClientManager) WithAudit(trail *audit.Trail) *ClientManager {
+	manager := *c
+	manager.audit = trail
+	return &manager
+}
+
+// recordAudit completes the event with the CPI call being served and appends it to the audit trail.
+// The order or cancellation already went through, so failing to record it is only logged.
+func (c *ClientManager) recordAudit(event audit.Event) {
+	if !c.audit.Enabled() {
+		return
+	}
+
+	event.Method = c.logger.RequestFields().Method
+	event.RequestID = c.logger.GetSerialTagPrefix()
+	if err := c.audit.Record(event); err != nil {
+		c.logger.Error(auditLogTag, "Recording %s of %s '%d' with order '%d': %s", event.Event, event.Resource, event.ResourceID, event.OrderID, err)
+	}
+}
+
+// instanceAudit looks up the billing item and the BOSH metadata of an instance for the audit trail
+func (c *ClientManager) instanceAudit(id int) (*datatypes.Billing_Item, audit.Metadata) {
+	instance, found, err := c.GetInstance(id, instanceAuditMask)
+	if err != nil || !found {
+		c.logger.Warn(auditLogTag, "Looking up billing item of instance '%d': found %t, %v", id, found, err)
+		return nil, audit.Metadata{}
+	}
+
+	metadata := audit.MetadataFromTags(instanceTags(instance))
+	if instance.BillingItem == nil {
+		return nil, metadata
+	}
+
+	return &instance.BillingItem.Billing_Item, metadata
+}
+
+func instanceTags(instance *datatypes.Virtual_Guest) []string {
+	tags := []string{}
+	for _, reference := range instance.TagReferences {
+		if reference.Tag != nil && reference.Tag.Name != nil {
+			tags = append(tags, *reference.Tag.Name)
+		}
+	}
+
+	return tags
+}
+
+// receiptAudit reads the order id and item prices of a placed order
+func receiptAudit(event audit.Event, receipt datatypes.Container_Product_Order_Receipt) audit.Event {
+	if receipt.OrderId != nil {
+		event.OrderID = *receipt.OrderId
+	}
+	if receipt.OrderDetails != nil {
+		event.ItemPrices = orderItemPrices(receipt.OrderDetails.Prices)
+	}
+
+	return event
+}
+
+func orderItemPrices(prices []datatypes.Product_Item_Price) []audit.ItemPrice {
+	itemPrices := []audit.ItemPrice{}
+	for _, price := range prices {
+		itemPrice := audit.ItemPrice{
+			HourlyFee:  float64Value(price.HourlyRecurringFee),
+			MonthlyFee: float64Value(price.RecurringFee),
+		}
+		if price.Id != nil {
+			itemPrice.ID = *price.Id
+		}
+		if len(price.Categories) > 0 && price.Categories[0].CategoryCode != nil {
+			itemPrice.Category = *price.Categories[0].CategoryCode
+		}
+		if price.Item != nil && price.Item.Description != nil {
+			itemPrice.Description = *price.Item.Description
+		}
+		itemPrices = append(itemPrices, itemPrice)
+	}
+
+	return itemPrices
+}
+
+// billingItemAudit reads the billing item id, order id and item prices of an instance
+func billingItemAudit(event audit.Event, billingItem *datatypes.Billing_Item) audit.Event {
+	if billingItem == nil {
+		return event
+	}
+
+	if billingItem.Id != nil {
+		event.BillingItemID = *billingItem.Id
+	}
+	if billingItem.OrderItem != nil && billingItem.OrderItem.Order != nil && billingItem.OrderItem.Order.Id != nil {
+		event.OrderID = *billingItem.OrderItem.Order.Id
+	}
+
+	event.ItemPrices = []audit.ItemPrice{}
+	for _, item := range append([]datatypes.Billing_Item{*billingItem}, billingItem.Children...) {
+		event.ItemPrices = append(event.ItemPrices, audit.ItemPrice{
+			Category:    stringValue(item.CategoryCode),
+			Description: stringValue(item.Description),
+			HourlyFee:   float64Value(item.HourlyRecurringFee),
+			MonthlyFee:  float64Value(item.RecurringFee),
+		})
+	}
+
+	return event
+}
+
+// volumeMetadata reads the BOSH metadata set_disk_metadata wrote to the notes of a volume
+func volumeMetadata(volume datatypes.Network_Storage) audit.Metadata {
+	if volume.Notes == nil {
+		return audit.Metadata{}
+	}
+
+	return audit.MetadataFromTags(strings.Split(*volume.Notes, ","))
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -5,13 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"math"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"code.cloudfoundry.org/clock"
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 	boshretry "github.com/cloudfoundry/bosh-utils/retrystrategy"
 	"github.com/go-openapi/strfmt"
@@ -22,8 +22,11 @@ import (
 	"github.com/softlayer/softlayer-go/session"
 	"github.com/softlayer/softlayer-go/sl"
 
+	"bosh-softlayer-cpi/audit"
 	"bosh-softlayer-cpi/logger"
+	"bosh-softlayer-cpi/metrics"
 	"bosh-softlayer-cpi/registry"
+	boslconfig "bosh-softlayer-cpi/softlayer/config"
 	vpsVm "bosh-softlayer-cpi/softlayer/vps_service/client/vm"
 	"bosh-softlayer-cpi/softlayer/vps_service/models"
 	"github.com/softlayer/softlayer-go/helpers/product"
@@ -48,10 +51,19 @@ const (
 
 	INSTANCE_ID_MASK = "id"
 
-	INSTANCE_NETWORK_COMPONENTS_MASK = "primaryBackendNetworkComponent[primaryIpAddress, networkVlan[id,name,vlanNumber,primaryRouter], subnets[netmask,networkIdentifier]], primaryNetworkComponent[primaryIpAddress, networkVlan[id,name,vlanNumber,primaryRouter], subnets[netmask,networkIdentifier]]"
+	INSTANCE_ORDER_PRESET_MASK = "billingItem[id, orderItem[presetId]]"
+
+	INSTANCE_NETWORK_COMPONENTS_MASK = "operatingSystemReferenceCode, primaryBackendNetworkComponent[primaryIpAddress, networkVlan[id,name,vlanNumber,primaryRouter], subnets[netmask,networkIdentifier]], primaryNetworkComponent[primaryIpAddress, networkVlan[id,name,vlanNumber,primaryRouter], subnets[netmask,networkIdentifier], primaryVersion6IpAddressRecord[ipAddress, subnet[networkIdentifier,cidr,gateway]]]"
 
 	NETWORK_DEFAULT_VLAN_MASK   = "id,primarySubnetId,networkSpace"
 	NETWORK_DEFAULT_SUBNET_MASK = "id,networkVlanId,addressSpace"
+	NETWORK_IPV6_VLAN_MASK      = "id,networkSpace,subnets[id,version,subnetType,networkIdentifier,cidr,gateway]"
+	NETWORK_STATIC_IP_VLAN_MASK = "id,subnets[id,version,networkIdentifier,cidr]"
+	NETWORK_GLOBAL_IP_MASK      = "id,ipAddress[ipAddress],destinationIpAddress[ipAddress]"
+	NETWORK_STATIC_IP_MASK      = "id,subnetType,networkVlanId,networkIdentifier,cidr,ipAddresses[id,ipAddress,note,isReserved,isNetwork,isGateway,isBroadcast]"
+
+	NETWORK_PREFLIGHT_VLAN_MASK   = "id,networkSpace,primaryRouter[datacenter[name]],subnets[id,subnetType,networkIdentifier,cidr,ipAddresses[ipAddress,note,isReserved,isNetwork,isGateway,isBroadcast]]"
+	NETWORK_PREFLIGHT_SUBNET_MASK = "id,addressSpace,subnetType,networkIdentifier,cidr,datacenter[name],ipAddresses[ipAddress,note,isReserved,isNetwork,isGateway,isBroadcast]"
 
 	VOLUME_DEFAULT_MASK = "id,username,lunId,capacityGb,bytesUsed,serviceResource.datacenter.name,serviceResourceBackendIpAddress,activeTransactionCount,billingItem.orderItem.order[id,userRecord.username]"
 
@@ -64,9 +76,13 @@ const (
 
 	IMAGE_DEFAULT_MASK = "id, name, globalIdentifier, imageType, accountId"
 
+	IMAGE_OS_CODE_MASK = "id,blockDevices.diskImage.softwareReferences.softwareDescription[operatingSystem,referenceCode]," +
+		"children.blockDevices.diskImage.softwareReferences.softwareDescription[operatingSystem,referenceCode]"
+
 	IMAGE_DETAIL_MASK = "id,globalIdentifier,name,datacenter.name,status.name,transaction.transactionStatus.name,accountId,publicFlag,imageType,flexImageFlag,note,createDate,blockDevicesDiskSpaceTotal,children[blockDevicesDiskSpaceTotal,datacenter.name]"
 
 	EPHEMERAL_DISK_CATEGORY_CODE = "guest_disk1"
+	PRIMARY_IPV6_CATEGORY_CODE   = "pri_ipv6_addresses"
 
 	UPGRADE_VIRTUAL_SERVER_ORDER_TYPE = "SoftLayer_Container_Product_Order_Virtual_Guest_Upgrade"
 
@@ -108,6 +124,8 @@ func NewSoftLayerClientManager(session *session.Session, vps *vpsVm.Client, swfi
 		services.GetLocationDatacenterService(session),
 		services.GetNetworkVlanService(session),
 		services.GetNetworkSubnetService(session),
+		services.GetNetworkSubnetIpAddressService(session),
+		services.GetNetworkSubnetIpAddressGlobalService(session),
 		services.GetVirtualGuestBlockDeviceTemplateGroupService(session),
 		services.GetSecuritySshKeyService(session),
 		services.GetBillingOrderService(session),
@@ -116,6 +134,11 @@ func NewSoftLayerClientManager(session *session.Session, vps *vpsVm.Client, swfi
 		vps,
 		swfitClient,
 		logger,
+		boslconfig.DefaultTimeouts(),
+		boslconfig.DefaultRetryPolicy(),
+		clock.NewClock(),
+		nil,
+		nil,
 	}
 }
 
@@ -123,6 +146,7 @@ func NewSoftLayerClientManager(session *session.Session, vps *vpsVm.Client, swfi
 type Client interface {
 	CancelInstance(id int) error
 	CreateInstance(template *datatypes.Virtual_Guest, userData *registry.SoftlayerUserData) (*datatypes.Virtual_Guest, error)
+	VerifyInstanceOrder(template *datatypes.Virtual_Guest) (OrderReport, error)
 	EditInstance(id int, template *datatypes.Virtual_Guest) (bool, error)
 	GetInstance(id int, mask string) (*datatypes.Virtual_Guest, bool, error)
 	GetInstanceByPrimaryBackendIpAddress(ip string) (*datatypes.Virtual_Guest, bool, error)
@@ -131,28 +155,47 @@ type Client interface {
 	ReloadInstance(id int, stemcellId int, sshKeyIds []int, hostname string, domain string, userData *registry.SoftlayerUserData) error
 	UpgradeInstanceConfig(id int, cpu int, memory int, network int, privateCPU bool, dedicatedHost bool) error
 	UpgradeInstance(id int, cpu int, memory int, network int, privateCPU bool, dedicatedHost bool, secondDiskSize int) (int, error)
+	GetVirtualServerPackageItems() ([]datatypes.Product_Item, error)
+	GetVirtualServerPackagePresets() ([]datatypes.Product_Package_Preset, error)
 	WaitInstanceUntilReady(id int, until time.Time) error
 	WaitInstanceUntilReadyWithTicket(id int, until time.Time) error
 	WaitInstanceHasActiveTransaction(id int, until time.Time) error
 	WaitInstanceHasNoneActiveTransaction(id int, until time.Time) error
 	WaitVolumeProvisioningWithOrderId(orderId int, until time.Time) (*datatypes.Network_Storage, error)
+	WaitOrderCompleted(id int, until time.Time) error
+	Timeouts() boslconfig.Timeouts
+	Clock() clock.Clock
 	SetTags(id int, tags string) (bool, error)
 	SetInstanceMetadata(id int, encodedUserData *string) (bool, error)
+	SetUserDataWithID(id int, userData *registry.SoftlayerUserData) error
+	GetUserDataWithID(id int) (*registry.SoftlayerUserData, bool, error)
 	AttachSecondDiskToInstance(id int, diskSize int) error
+	AttachPrimaryIpv6Address(id int) error
+	FindVirtualServerItemPrice(categoryCode string) (*datatypes.Product_Item_Price, bool, error)
 	GetInstanceAllowedHost(id int) (*datatypes.Network_Storage_Allowed_Host, bool, error)
 	AuthorizeHostToVolume(instance *datatypes.Virtual_Guest, volumeId int, until time.Time) (bool, error)
 	DeauthorizeHostToVolume(instance *datatypes.Virtual_Guest, volumeId int, until time.Time) (bool, error)
 	CreateVolume(location string, size int, iops int, snapshotSpace int) (*datatypes.Network_Storage, error)
+	VerifyVolumeOrder(location string, size int, iops int, snapshotSpace int) (OrderReport, error)
 	OrderBlockVolume(storageType string, location string, size int, iops int) (*datatypes.Container_Product_Order_Receipt, error)
 	OrderBlockVolume2(storageType string, location string, size int, iops int, snapshotSpace int) (*datatypes.Container_Product_Order_Receipt, error)
+	UpgradeBlockVolume(volumeId int, size int, iops int) (int, error)
 	CancelBlockVolume(volumeId int, reason string, immediate bool) (bool, error)
 	GetBlockVolumeDetails(volumeId int, mask string) (*datatypes.Network_Storage, bool, error)
 	GetBlockVolumeDetailsBySoftLayerAccount(volumeId int, mask string) (datatypes.Network_Storage, error)
 	GetNetworkStorageTarget(volumeId int, mask string) (string, bool, error)
 	SetNotes(id int, notes string) (bool, error)
 	GetImage(imageId int, mask string) (*datatypes.Virtual_Guest_Block_Device_Template_Group, bool, error)
+	DeleteImage(imageId int) error
+	GetInstancesByImage(globalIdentifier string) ([]datatypes.Virtual_Guest, error)
 	GetVlan(id int, mask string) (*datatypes.Network_Vlan, bool, error)
 	GetSubnet(id int, mask string) (*datatypes.Network_Subnet, bool, error)
+	SetIpAddressNote(id int, note string) error
+	GetIpAddressNote(id int) (string, error)
+	GetIpAddressesByNote(note string) ([]datatypes.Network_Subnet_IpAddress, error)
+	GetGlobalIpRecords(ip string, destinationIp string) ([]datatypes.Network_Subnet_IpAddress_Global, error)
+	RouteGlobalIp(id int, destinationIp string) error
+	UnrouteGlobalIp(id int) error
 	GetAllowedHostCredential(id int) (*datatypes.Network_Storage_Allowed_Host, bool, error)
 	GetAllowedNetworkStorage(id int) ([]string, bool, error)
 	CreateSshKey(label *string, key *string, fingerPrint *string) (*datatypes.Security_Ssh_Key, error)
@@ -184,6 +227,8 @@ type ClientManager struct {
 	LocationService       services.Location_Datacenter
 	NetworkVlanService    services.Network_Vlan
 	NetworkSubnetService  services.Network_Subnet
+	NetworkIpService      services.Network_Subnet_IpAddress
+	GlobalIpService       services.Network_Subnet_IpAddress_Global
 	ImageService          services.Virtual_Guest_Block_Device_Template_Group
 	SecuritySshKeyService services.Security_Ssh_Key
 	BillingOrderService   services.Billing_Order
@@ -192,6 +237,56 @@ type ClientManager struct {
 	vpsService            *vpsVm.Client
 	swfitClient           *swift.Connection
 	logger                logger.Logger
+	timeouts              boslconfig.Timeouts
+	retryPolicy           boslconfig.RetryPolicy
+	clock                 clock.Clock
+	metrics               *metrics.Recorder
+	audit                 *audit.Trail
+}
+
+// WithTimeouts returns a copy of the client manager waiting with the given timeouts, unset ones keeping their defaults
+func (c *ClientManager) WithTimeouts(timeouts boslconfig.Timeouts) *ClientManager {
+	manager := *c
+	manager.timeouts = timeouts.WithDefaults()
+	return &manager
+}
+
+// WithRetryPolicy returns a copy of the client manager backing off with the given policy while SoftLayer refuses a call for now
+func (c *ClientManager) WithRetryPolicy(policy boslconfig.RetryPolicy) *ClientManager {
+	manager := *c
+	manager.retryPolicy = policy.WithDefaults()
+	return &manager
+}
+
+// WithClock returns a copy of the client manager reading the time and sleeping in its wait loops with the given clock
+func (c *ClientManager) WithClock(clock clock.Clock) *ClientManager {
+	manager := *c
+	manager.clock = clock
+	return &manager
+}
+
+// sleepBefore waits for the poll interval, or only until the deadline when that comes first
+func (c *ClientManager) sleepBefore(until time.Time, interval time.Duration) {
+	if remaining := until.Sub(c.clock.Now()); remaining < interval {
+		interval = remaining
+	}
+	c.clock.Sleep(interval)
+}
+
+// WithMetrics returns a copy of the client manager recording how long its wait loops take
+func (c *ClientManager) WithMetrics(recorder *metrics.Recorder) *ClientManager {
+	manager := *c
+	manager.metrics = recorder
+	return &manager
+}
+
+func (c *ClientManager) Timeouts() boslconfig.Timeouts {
+	return c.timeouts
+}
+
+// Clock is the clock the wait loops of the client sleep with, for the deadlines given to them
+func (c *ClientManager) Clock() clock.Clock {
+	return c.clock
 }
 
 func (c *ClientManager) GetInstance(id int, mask string) (*datatypes.Virtual_Guest, bool, error) {
@@ -245,6 +340,81 @@ func (c *ClientManager) GetSubnet(id int, mask string) (*datatypes.Network_Subne
 	return &vlan, true, err
 }
 
+// SetIpAddressNote replaces the note of a subnet IP address record, an empty note clears it
+func (c *ClientManager) SetIpAddressNote(id int, note string) error {
+	_, err := c.NetworkIpService.Id(id).EditObject(&datatypes.Network_Subnet_IpAddress{
+		Note: sl.String(note),
+	})
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Editing note of ip address '%d'", id)
+	}
+
+	return nil
+}
+
+// GetIpAddressNote returns the note of a subnet IP address record, empty when it has none
+func (c *ClientManager) GetIpAddressNote(id int) (string, error) {
+	ipAddress, err := c.NetworkIpService.Id(id).Mask("id,note").GetObject()
+	if err != nil {
+		return "", bosherr.WrapErrorf(err, "Getting note of ip address '%d'", id)
+	}
+
+	if ipAddress.Note == nil {
+		return "", nil
+	}
+
+	return *ipAddress.Note, nil
+}
+
+func (c *ClientManager) GetIpAddressesByNote(note string) ([]datatypes.Network_Subnet_IpAddress, error) {
+	filters := filter.New()
+	filters = append(filters, filter.Path("ipAddresses.note").Eq(note))
+	ipAddresses, err := c.AccountService.Mask("id,ipAddress,note").Filter(filters.Build()).GetIpAddresses()
+	if err != nil {
+		return []datatypes.Network_Subnet_IpAddress{}, bosherr.WrapErrorf(err, "Getting ip addresses with note '%s'", note)
+	}
+
+	return ipAddresses, nil
+}
+
+// GetGlobalIpRecords lists the global IPs of the account, filtered on their address and on the address
+// they are routed to when given
+func (c *ClientManager) GetGlobalIpRecords(ip string, destinationIp string) ([]datatypes.Network_Subnet_IpAddress_Global, error) {
+	filters := filter.New()
+	if ip != "" {
+		filters = append(filters, filter.Path("globalIpRecords.ipAddress.ipAddress").Eq(ip))
+	}
+	if destinationIp != "" {
+		filters = append(filters, filter.Path("globalIpRecords.destinationIpAddress.ipAddress").Eq(destinationIp))
+	}
+
+	records, err := c.AccountService.Mask(NETWORK_GLOBAL_IP_MASK).Filter(filters.Build()).GetGlobalIpRecords()
+	if err != nil {
+		return []datatypes.Network_Subnet_IpAddress_Global{}, bosherr.WrapError(err, "Getting global ip records")
+	}
+
+	return records, nil
+}
+
+// RouteGlobalIp routes a global IP to the given address. SoftLayer updates the route within a couple of minutes.
+func (c *ClientManager) RouteGlobalIp(id int, destinationIp string) error {
+	_, err := c.GlobalIpService.Id(id).Route(sl.String(destinationIp))
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Routing global ip '%d' to '%s'", id, destinationIp)
+	}
+
+	return nil
+}
+
+func (c *ClientManager) UnrouteGlobalIp(id int) error {
+	_, err := c.GlobalIpService.Id(id).Unroute()
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Unrouting global ip '%d'", id)
+	}
+
+	return nil
+}
+
 func (c *ClientManager) GetInstanceByPrimaryBackendIpAddress(ip string) (*datatypes.Virtual_Guest, bool, error) {
 	filters := filter.New()
 	filters = append(filters, filter.Path("virtualGuests.primaryBackendIpAddress").Eq(ip))
@@ -327,8 +497,30 @@ func (c *ClientManager) GetImage(imageId int, mask string) (*datatypes.Virtual_G
 	return &image, true, err
 }
 
+func (c *ClientManager) DeleteImage(imageId int) error {
+	_, err := c.ImageService.Id(imageId).DeleteObject()
+	if err != nil {
+		if apiErr, ok := err.(sl.Error); ok {
+			if apiErr.Exception == SOFTLAYER_OBJECTNOTFOUND_EXCEPTION {
+				return nil
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (c *ClientManager) GetInstancesByImage(globalIdentifier string) ([]datatypes.Virtual_Guest, error) {
+	filters := filter.New()
+	filters = append(filters, filter.Path("virtualGuests.blockDeviceTemplateGroup.globalIdentifier").Eq(globalIdentifier))
+	return c.AccountService.Mask("id, hostname").Filter(filters.Build()).GetVirtualGuests()
+}
+
 // Check the virtual server instance is ready for use
-func (c *ClientManager) WaitInstanceUntilReady(id int, until time.Time) error {
+func (c *ClientManager) WaitInstanceUntilReady(id int, until time.Time) (err error) {
+	defer c.observeWait("WaitInstanceUntilReady", c.clock.Now(), &err)
+
 	for {
 		virtualGuest, found, err := c.GetInstance(id, "id, lastOperatingSystemReload[id,modifyDate], activeTransaction[id,transactionStatus.name], provisionDate, powerState.keyName")
 		if err != nil {
@@ -360,35 +552,34 @@ func (c *ClientManager) WaitInstanceUntilReady(id int, until time.Time) error {
 			}
 		}
 
-		now := time.Now()
-		if now.After(until) {
+		now := c.clock.Now()
+		if !now.Before(until) {
 			return bosherr.Errorf("Power on virtual guest with id %d Time Out!", *virtualGuest.Id)
 		}
 
-		min := math.Min(float64(10.0), float64(until.Sub(now)))
-		time.Sleep(time.Duration(min) * time.Second)
+		c.sleepBefore(until, c.timeouts.InstancePollInterval.Duration)
 	}
 }
 
 // @TODO: The method is experimental and needed to verify.
 func (c *ClientManager) WaitInstanceUntilReadyWithTicket(id int, until time.Time) error {
-	now := time.Now()
+	now := c.clock.Now()
 	halfDuration := until.Sub(now) / 2
 	ticketTime := now.Add(halfDuration)
 
 	// Wait half duration until ready, otherwise create a ticket
-	c.logger.Debug(softlayerClientLogTag, fmt.Sprintf("Waiting for intance '%d' ready unless it is over %.2f minutes to create ticket.", id,
-		halfDuration.Minutes()))
+	c.logger.Debug(softlayerClientLogTag, "Waiting for intance '%d' ready unless it is over %.2f minutes to create ticket.", id,
+		halfDuration.Minutes())
 	err := c.WaitInstanceUntilReady(id, ticketTime)
 	if err != nil {
 		if strings.Contains(err.Error(), fmt.Sprintf("Power on virtual guest with id %d Time Out!", id)) {
 			contents := fmt.Sprintf("The power state of virtual guest '%d' is not 'RUNNING' after OS reload. The ticket generated by Bosh Softlayer CPI.", id)
 
-			c.logger.Debug(softlayerClientLogTag, fmt.Sprintf("Creating ticket for intance '%d' timeout.", id))
+			c.logger.Debug(softlayerClientLogTag, "Creating ticket for intance '%d' timeout.", id)
 			err = c.CreateTicket(sl.String("OS Reload Question"), sl.String("OS reload hung."),
 				sl.String(contents), sl.Int(id), sl.String("VIRTUAL_GUEST"))
 			if err != nil {
-				c.logger.Error(softlayerClientLogTag, fmt.Sprintf("Creating ticket for intance '%d' with err : %v.", id, err))
+				c.logger.Error(softlayerClientLogTag, "Creating ticket for intance '%d' with err : %v.", id, err)
 				// @TODO: To comment by test
 				// return bosherr.WrapError(err, "Creating ticket.")
 			}
@@ -398,8 +589,8 @@ func (c *ClientManager) WaitInstanceUntilReadyWithTicket(id int, until time.Time
 	}
 
 	// Wait remaining  half duration until ready, otherwise throw timeout error.
-	c.logger.Debug(softlayerClientLogTag, fmt.Sprintf("Waiting for intance '%d' ready unless it is over %.2f minutes to return the timeout error.", id,
-		halfDuration.Minutes()))
+	c.logger.Debug(softlayerClientLogTag, "Waiting for intance '%d' ready unless it is over %.2f minutes to return the timeout error.", id,
+		halfDuration.Minutes())
 
 	err = c.WaitInstanceUntilReady(id, until)
 
@@ -410,7 +601,9 @@ func (c *ClientManager) WaitInstanceUntilReadyWithTicket(id int, until time.Time
 	return nil
 }
 
-func (c *ClientManager) WaitInstanceHasActiveTransaction(id int, until time.Time) error {
+func (c *ClientManager) WaitInstanceHasActiveTransaction(id int, until time.Time) (err error) {
+	defer c.observeWait("WaitInstanceHasActiveTransaction", c.clock.Now(), &err)
+
 	for {
 		virtualGuest, found, err := c.GetInstance(id, "id, activeTransaction[id,transactionStatus.name]")
 		if err != nil {
@@ -428,17 +621,18 @@ func (c *ClientManager) WaitInstanceHasActiveTransaction(id int, until time.Time
 			return nil
 		}
 
-		now := time.Now()
-		if now.After(until) {
+		now := c.clock.Now()
+		if !now.Before(until) {
 			return bosherr.Errorf("Waiting instance with id of '%d' has active transaction time out", id)
 		}
 
-		min := math.Min(float64(5.0), float64(until.Sub(now)))
-		time.Sleep(time.Duration(min) * time.Second)
+		c.sleepBefore(until, c.timeouts.TransactionPollInterval.Duration)
 	}
 }
 
-func (c *ClientManager) WaitOrderCompleted(id int, until time.Time) error {
+func (c *ClientManager) WaitOrderCompleted(id int, until time.Time) (err error) {
+	defer c.observeWait("WaitOrderCompleted", c.clock.Now(), &err)
+
 	if id == 0 {
 		return nil
 	}
@@ -452,17 +646,18 @@ func (c *ClientManager) WaitOrderCompleted(id int, until time.Time) error {
 			return nil
 		}
 
-		now := time.Now()
-		if now.After(until) {
+		now := c.clock.Now()
+		if !now.Before(until) {
 			return bosherr.Errorf("Waiting order with id of '%d' has been completed", id)
 		}
 
-		min := math.Min(float64(5.0), float64(until.Sub(now)))
-		time.Sleep(time.Duration(min) * time.Second)
+		c.sleepBefore(until, c.timeouts.OrderPollInterval.Duration)
 	}
 }
 
-func (c *ClientManager) WaitInstanceHasNoneActiveTransaction(id int, until time.Time) error {
+func (c *ClientManager) WaitInstanceHasNoneActiveTransaction(id int, until time.Time) (err error) {
+	defer c.observeWait("WaitInstanceHasNoneActiveTransaction", c.clock.Now(), &err)
+
 	for {
 		virtualGuest, found, err := c.GetInstance(id, "id, activeTransaction[id,transactionStatus.name]")
 		if err != nil {
@@ -480,13 +675,12 @@ func (c *ClientManager) WaitInstanceHasNoneActiveTransaction(id int, until time.
 			return bosherr.Errorf("Instance with id of '%d' has 'RECLAIM_WAIT' transaction", id)
 		}
 
-		now := time.Now()
-		if now.After(until) {
+		now := c.clock.Now()
+		if !now.Before(until) {
 			return bosherr.Errorf("Waiting instance with id of '%d' has none active transaction time out", id)
 		}
 
-		min := math.Min(float64(5.0), float64(until.Sub(now)))
-		time.Sleep(time.Duration(min) * time.Second)
+		c.sleepBefore(until, c.timeouts.TransactionPollInterval.Duration)
 	}
 }
 
@@ -495,15 +689,19 @@ func (c *ClientManager) CreateInstance(template *datatypes.Virtual_Guest, userDa
 	if err != nil {
 		return &datatypes.Virtual_Guest{}, bosherr.WrapError(err, "Creating instance")
 	}
+	// The instance is billed from now on, even if it never gets ready
+	defer c.auditInstanceOrder(*virtualguest.Id)
 
-	// Wait for instance ready
-	time.Sleep(90 * time.Second)
+	// Wait for instance ready, unless the delay is turned off
+	if delay := c.timeouts.CreateInstanceDelay.Duration; delay > 0 {
+		c.clock.Sleep(delay)
+	}
 	err = c.SetUserDataWithID(*virtualguest.Id, userData)
 	if err != nil {
 		return &datatypes.Virtual_Guest{}, bosherr.WrapErrorf(err, "Updating user data contents with instance '%d'", *virtualguest.Id)
 	}
 
-	until := time.Now().Add(time.Duration(4) * time.Hour)
+	until := c.clock.Now().Add(c.timeouts.CreateInstance.Duration)
 	if err := c.WaitInstanceUntilReady(*virtualguest.Id, until); err != nil {
 		return &datatypes.Virtual_Guest{}, bosherr.WrapError(err, "Waiting until instance is ready")
 	}
@@ -514,6 +712,32 @@ func (c *ClientManager) CreateInstance(template *datatypes.Virtual_Guest, userDa
 	return &virtualguest, nil
 }
 
+// VerifyInstanceOrder checks that the template can be ordered, without creating the instance
+func (c *ClientManager) VerifyInstanceOrder(template *datatypes.Virtual_Guest) (OrderReport, error) {
+	orderTemplate, err := c.VirtualGuestService.GenerateOrderTemplate(template)
+	if err != nil {
+		if isOrderRejection(err) {
+			return NewRejectedOrderReport(err), nil
+		}
+		return OrderReport{}, bosherr.WrapError(err, "Generating order template")
+	}
+
+	order := datatypes.Container_Product_Order_Virtual_Guest{
+		Container_Product_Order_Hardware_Server: datatypes.Container_Product_Order_Hardware_Server{
+			Container_Product_Order: orderTemplate,
+		},
+	}
+	verifiedOrder, err := c.OrderService.VerifyOrder(&order)
+	if err != nil {
+		if isOrderRejection(err) {
+			return NewRejectedOrderReport(err), nil
+		}
+		return OrderReport{}, bosherr.WrapError(err, "Verifying order")
+	}
+
+	return newOrderReport(verifiedOrder), nil
+}
+
 func (c *ClientManager) CreateInstanceFromVPS(template *datatypes.Virtual_Guest,
 	stemcellID int, sshKeys []int, userData *registry.SoftlayerUserData) (*datatypes.Virtual_Guest, error) {
 	reqFilter := &models.VMFilter{
@@ -601,7 +825,7 @@ func (c *ClientManager) EditInstance(id int, template *datatypes.Virtual_Guest)
 		}
 	}
 
-	until := time.Now().Add(time.Duration(30) * time.Minute)
+	until := c.clock.Now().Add(c.timeouts.EditInstance.Duration)
 	if err := c.WaitInstanceUntilReady(id, until); err != nil {
 		return false, bosherr.WrapError(err, "Waiting until instance is ready")
 	}
@@ -625,8 +849,8 @@ func (c *ClientManager) RebootInstance(id int, soft bool, hard bool) error {
 
 func (c *ClientManager) ReloadInstance(id int, stemcellId int, sshKeyIds []int, hostname string, domain string, userData *registry.SoftlayerUserData) error {
 	var err error
-	reloadUntil := time.Now().Add(time.Duration(10) * time.Second)
-	transactionUntil := time.Now().Add(time.Duration(1) * time.Hour)
+	reloadUntil := c.clock.Now().Add(c.timeouts.ReloadRequest.Duration)
+	transactionUntil := c.clock.Now().Add(c.timeouts.ReloadTransaction.Duration)
 
 	err = c.SetUserDataWithID(id, userData)
 	if err != nil {
@@ -651,12 +875,12 @@ func (c *ClientManager) ReloadInstance(id int, stemcellId int, sshKeyIds []int,
 		return bosherr.WrapErrorf(err, "Reloading operating system of virtual guest '%d'", id)
 	}
 
-	transactionUntil = time.Now().Add(time.Duration(1) * time.Hour)
+	transactionUntil = c.clock.Now().Add(c.timeouts.ReloadTransaction.Duration)
 	if err = c.WaitInstanceHasActiveTransaction(*sl.Int(id), transactionUntil); err != nil {
 		return bosherr.WrapError(err, "Waiting until instance has active transaction after launching os_reload")
 	}
 
-	transactionUntil = time.Now().Add(time.Duration(4) * time.Hour)
+	transactionUntil = c.clock.Now().Add(c.timeouts.ReloadInstance.Duration)
 	if err = c.WaitInstanceUntilReadyWithTicket(*sl.Int(id), transactionUntil); err != nil {
 		return bosherr.WrapError(err, "Waiting until instance is ready after os_reload")
 	}
@@ -679,23 +903,29 @@ func (c *ClientManager) ReloadInstance(id int, stemcellId int, sshKeyIds []int,
 
 func (c *ClientManager) CancelInstance(id int) error {
 	var err error
-	until := time.Now().Add(time.Duration(10) * time.Minute)
+	until := c.clock.Now().Add(c.timeouts.CancelInstance.Duration)
 	if err = c.WaitInstanceHasNoneActiveTransaction(*sl.Int(id), until); err != nil {
 		if boshErr, ok := err.(bosherr.ComplexError); ok {
 			if strings.Contains(boshErr.Cause.Error(), SOFTLAYER_OBJECTNOTFOUND_EXCEPTION) {
-				c.logger.Warn(softlayerClientLogTag, fmt.Sprintf("Instance '%d' does not exist, CPI skips to cancel it", id))
+				c.logger.Warn(softlayerClientLogTag, "Instance '%d' does not exist, CPI skips to cancel it", id)
 				return nil
 			}
 		}
 
 		if strings.Contains(err.Error(), "has 'RECLAIM_WAIT' transaction") {
-			c.logger.Warn(softlayerClientLogTag, fmt.Sprintf("Instance '%d' stays 'RECLAIM_WAIT' transaction, CPI skips to cancel it", id))
+			c.logger.Warn(softlayerClientLogTag, "Instance '%d' stays 'RECLAIM_WAIT' transaction, CPI skips to cancel it", id)
 			return nil
 		}
 
 		return bosherr.WrapError(err, "Waiting until instance has none active transaction before canceling")
 	}
 
+	var billingItem *datatypes.Billing_Item
+	var metadata audit.Metadata
+	if c.audit.Enabled() {
+		billingItem, metadata = c.instanceAudit(id)
+	}
+
 	resp, err := c.VirtualGuestService.Id(id).DeleteObject()
 	if err != nil {
 		return bosherr.WrapErrorf(err, "Deleting instance with id '%d'", id)
@@ -704,9 +934,31 @@ func (c *ClientManager) CancelInstance(id int) error {
 		return bosherr.WrapErrorf(err, "Deleting instance with id '%d' failed", id)
 	}
 
+	c.recordAudit(billingItemAudit(audit.Event{
+		Event:      audit.EventCancel,
+		Resource:   audit.ResourceVirtualGuest,
+		ResourceID: id,
+		Metadata:   metadata,
+	}, billingItem))
+
 	return nil
 }
 
+// auditInstanceOrder records the order of a new instance with its billing item
+func (c *ClientManager) auditInstanceOrder(id int) {
+	if !c.audit.Enabled() {
+		return
+	}
+
+	billingItem, metadata := c.instanceAudit(id)
+	c.recordAudit(billingItemAudit(audit.Event{
+		Event:      audit.EventOrder,
+		Resource:   audit.ResourceVirtualGuest,
+		ResourceID: id,
+		Metadata:   metadata,
+	}, billingItem))
+}
+
 func (c *ClientManager) DeleteInstanceFromVPS(id int) error {
 	_, err := c.vpsService.GetVMByCid(vpsVm.NewGetVMByCidParams().WithCid(int32(id)))
 	if err != nil {
@@ -755,30 +1007,22 @@ func (c *ClientManager) UpgradeInstance(id int, cpu int, memory int, network int
 
 	if cpu != 0 {
 		upgradeOptions["guest_core"] = float64(cpu)
-		c.logger.Debug(softlayerClientLogTag, fmt.Sprintf("Upgrade item price for 'guest_core/%d'", cpu))
+		c.logger.Debug(softlayerClientLogTag, "Upgrade item price for 'guest_core/%d'", cpu)
 	}
 	if memory != 0 {
 		upgradeOptions["ram"] = float64(memory / 1024)
-		c.logger.Debug(softlayerClientLogTag, fmt.Sprintf("Upgrade item price for 'ram/%d'", memory/1024))
+		c.logger.Debug(softlayerClientLogTag, "Upgrade item price for 'ram/%d'", memory/1024)
 	}
 
 	if network != 0 {
 		upgradeOptions["port_speed"] = float64(network)
-		c.logger.Debug(softlayerClientLogTag, fmt.Sprintf("Upgrade item price for 'port_speed/%d'", network))
+		c.logger.Debug(softlayerClientLogTag, "Upgrade item price for 'port_speed/%d'", network)
 	}
 
-	packageType := "VIRTUAL_SERVER_INSTANCE"
-	productPackages, err := c.PackageService.
-		Mask("id,name,description,isActive,type.keyName").
-		Filter(filter.New(filter.Path("type.keyName").Eq(packageType)).Build()).
-		GetAllObjects()
+	packageID, err := c.getVirtualServerPackageID()
 	if err != nil {
 		return 0, err
 	}
-	if len(productPackages) == 0 {
-		return 0, bosherr.Errorf("No package found for type: %s", packageType)
-	}
-	packageID := *productPackages[0].Id
 	packageItems, err := c.PackageService.
 		Id(packageID).
 		Mask("keyName, description, capacity, prices[id, locationGroupId, categories[categoryCode]]").
@@ -791,7 +1035,7 @@ func (c *ClientManager) UpgradeInstance(id int, cpu int, memory int, network int
 	prices = product.SelectProductPricesByCategory(packageItems, upgradeOptions, forPublicNetwork, !privateCPU, dedicatedHost)
 
 	if secondDiskSize != 0 {
-		c.logger.Debug(softlayerClientLogTag, fmt.Sprintf("Find upgrade item price for second disk: %dGB", secondDiskSize))
+		c.logger.Debug(softlayerClientLogTag, "Find upgrade item price for second disk: %dGB", secondDiskSize)
 		var diskItemPrice *datatypes.Product_Item_Price
 		diskItemPrice, presetId, err = c.getUpgradeItemPriceForSecondDisk(id, secondDiskSize)
 		if err != nil {
@@ -803,6 +1047,12 @@ func (c *ClientManager) UpgradeInstance(id int, cpu int, memory int, network int
 	if len(prices) == 0 {
 		return 0, bosherr.Errorf("Unable to find prices for upgrade: %v", upgradeOptions)
 	}
+
+	return c.placeUpgradeOrder(id, packageID, prices, presetId, "Upgrade instance configuration.")
+}
+
+// placeUpgradeOrder orders the given item prices for the virtual guest, returning 0 when it already has them
+func (c *ClientManager) placeUpgradeOrder(id int, packageID int, prices []datatypes.Product_Item_Price, presetId int, note string) (int, error) {
 	order := datatypes.Container_Product_Order{
 		ComplexType: sl.String(UPGRADE_VIRTUAL_SERVER_ORDER_TYPE),
 		Prices:      prices,
@@ -813,7 +1063,7 @@ func (c *ClientManager) UpgradeInstance(id int, cpu int, memory int, network int
 			},
 			{
 				Name:  sl.String("NOTE_GENERAL"),
-				Value: sl.String("Upgrade instance configuration."),
+				Value: sl.String(note),
 			},
 		},
 		VirtualGuests: []datatypes.Virtual_Guest{
@@ -834,12 +1084,14 @@ func (c *ClientManager) UpgradeInstance(id int, cpu int, memory int, network int
 		},
 	}
 
-	c.logger.Debug(softlayerClientLogTag, fmt.Sprintf("Place order for vm '%d'", id))
+	c.logger.Debug(softlayerClientLogTag, "Place order for vm '%d'", id)
 
 	orderId := 0
+	var orderReceipt datatypes.Container_Product_Order_Receipt
+	var err error
 	execPlaceOrderRetryable := boshretry.NewRetryable(
 		func() (bool, error) {
-			orderReceipt, err := c.OrderService.PlaceOrder(&upgradeOrder, sl.Bool(false))
+			orderReceipt, err = c.OrderService.PlaceOrder(&upgradeOrder, sl.Bool(false))
 			if err != nil {
 				if apiErr, ok := err.(sl.Error); ok {
 					if strings.Contains(apiErr.Message, "A current price was provided for the upgrade order") {
@@ -862,17 +1114,73 @@ func (c *ClientManager) UpgradeInstance(id int, cpu int, memory int, network int
 			}
 		})
 
-	attemptRetryStrategy := boshretry.NewAttemptRetryStrategy(3, 5*time.Second, execPlaceOrderRetryable, c.logger.GetBoshLogger())
-	c.logger.ChangeRetryStrategyLogTag(&attemptRetryStrategy)
+	// Attempt up to 3 times, sleeping with the clock of the client in between
+	for attempt := 1; ; attempt++ {
+		isRetryable, attemptErr := execPlaceOrderRetryable.Attempt()
+		if attemptErr == nil {
+			break
+		}
+		if !isRetryable || attempt == 3 {
+			return orderId, attemptErr
+		}
+
+		c.logger.Debug(softlayerClientLogTag, "Retrying to place order for vm '%d': %s", id, attemptErr)
+		c.clock.Sleep(5 * time.Second)
+	}
 
-	err = attemptRetryStrategy.Try()
-	if err != nil {
-		return orderId, err
+	if orderId != 0 && c.audit.Enabled() {
+		_, metadata := c.instanceAudit(id)
+		c.recordAudit(receiptAudit(audit.Event{
+			Event:      audit.EventOrder,
+			Resource:   audit.ResourceVirtualGuest,
+			ResourceID: id,
+			Metadata:   metadata,
+		}, orderReceipt))
 	}
 
 	return orderId, nil
 }
 
+func (c *ClientManager) GetVirtualServerPackageItems() ([]datatypes.Product_Item, error) {
+	packageID, err := c.getVirtualServerPackageID()
+	if err != nil {
+		return []datatypes.Product_Item{}, err
+	}
+
+	return c.PackageService.
+		Id(packageID).
+		Mask("id, keyName, description, capacity, prices[id, locationGroupId, categories[categoryCode]]").
+		GetItems()
+}
+
+func (c *ClientManager) GetVirtualServerPackagePresets() ([]datatypes.Product_Package_Preset, error) {
+	packageID, err := c.getVirtualServerPackageID()
+	if err != nil {
+		return []datatypes.Product_Package_Preset{}, err
+	}
+
+	return c.PackageService.
+		Id(packageID).
+		Mask("id, keyName, isActive, prices[id, item[keyName, capacity], categories[categoryCode]]").
+		GetActivePresets()
+}
+
+func (c *ClientManager) getVirtualServerPackageID() (int, error) {
+	packageType := "VIRTUAL_SERVER_INSTANCE"
+	productPackages, err := c.PackageService.
+		Mask("id,name,description,isActive,type.keyName").
+		Filter(filter.New(filter.Path("type.keyName").Eq(packageType)).Build()).
+		GetAllObjects()
+	if err != nil {
+		return 0, err
+	}
+	if len(productPackages) == 0 {
+		return 0, bosherr.Errorf("No package found for type: %s", packageType)
+	}
+
+	return *productPackages[0].Id, nil
+}
+
 func (c *ClientManager) SetTags(id int, tags string) (bool, error) {
 	_, err := c.VirtualGuestService.Id(id).SetTags(&tags)
 	if err != nil {
@@ -884,6 +1192,16 @@ func (c *ClientManager) SetTags(id int, tags string) (bool, error) {
 		}
 	}
 
+	if err == nil {
+		// Orders of new instances are recorded before set_vm_metadata tags them, this attributes them
+		c.recordAudit(audit.Event{
+			Event:      audit.EventMetadata,
+			Resource:   audit.ResourceVirtualGuest,
+			ResourceID: id,
+			Metadata:   audit.MetadataFromTags(strings.Split(tags, ",")),
+		})
+	}
+
 	return true, err
 }
 
@@ -911,7 +1229,7 @@ func (c *ClientManager) getUpgradeItemPriceForSecondDisk(id int, diskSize int) (
 		return &datatypes.Product_Item_Price{}, 0, err
 	}
 
-	mask = "localDiskFlag, billingItem[id, orderItem[presetId]]"
+	mask = "localDiskFlag, " + INSTANCE_ORDER_PRESET_MASK
 	virtualGuest, err := c.VirtualGuestService.Id(id).Mask(mask).GetObject()
 	if err != nil {
 		if apiErr, ok := err.(sl.Error); ok {
@@ -955,10 +1273,7 @@ func (c *ClientManager) getUpgradeItemPriceForSecondDisk(id int, diskSize int) (
 		return &datatypes.Product_Item_Price{}, 0, bosherr.Errorf("No proper %s disk for size %d", diskType, diskSize)
 	}
 
-	if virtualGuest.BillingItem.OrderItem.PresetId == nil {
-		return &currentItemPrice, 0, nil
-	}
-	return &currentItemPrice, *virtualGuest.BillingItem.OrderItem.PresetId, nil
+	return &currentItemPrice, orderPresetId(virtualGuest), nil
 }
 
 func (c *ClientManager) GetBlockVolumeDetails(volumeId int, mask string) (*datatypes.Network_Storage, bool, error) {
@@ -1033,9 +1348,23 @@ func (c *ClientManager) GetNetworkStorageTarget(volumeId int, mask string) (stri
 }
 
 func (c *ClientManager) OrderBlockVolume(storageType string, location string, size int, iops int) (*datatypes.Container_Product_Order_Receipt, error) {
+	order, err := c.blockVolumeOrder(storageType, location, size, iops)
+	if err != nil {
+		return &datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	orderReceipt, err := c.OrderService.PlaceOrder(order, sl.Bool(false))
+	if err != nil {
+		return &datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	return &orderReceipt, nil
+}
+
+func (c *ClientManager) blockVolumeOrder(storageType string, location string, size int, iops int) (*datatypes.Container_Product_Order_Network_PerformanceStorage_Iscsi, error) {
 	locationId, err := c.GetLocationId(location)
 	if err != nil {
-		return &datatypes.Container_Product_Order_Receipt{}, bosherr.Error("Invalid datacenter name specified. Please provide the lower case short name (e.g.: dal09)")
+		return nil, invalidLocationError(err)
 	}
 	baseTypeName := "SoftLayer_Container_Product_Order_Network_"
 	var prices = make([]datatypes.Product_Item_Price, 0)
@@ -1043,17 +1372,17 @@ func (c *ClientManager) OrderBlockVolume(storageType string, location string, si
 	if storageType == "performance_storage_iscsi" {
 		productPacakge, err := c.GetPerformanceIscsiPackage()
 		if err != nil {
-			return &datatypes.Container_Product_Order_Receipt{}, err
+			return nil, err
 		}
 		complexType := baseTypeName + "PerformanceStorage_Iscsi"
 		storagePrice, err := FindPerformancePrice(productPacakge, "performance_storage_iscsi")
 		if err != nil {
-			return &datatypes.Container_Product_Order_Receipt{}, err
+			return nil, err
 		}
 		prices = append(prices, storagePrice)
 		spacePrice, err := FindPerformanceSpacePrice(productPacakge, size)
 		if err != nil {
-			return &datatypes.Container_Product_Order_Receipt{}, err
+			return nil, err
 		}
 		prices = append(prices, spacePrice)
 
@@ -1068,12 +1397,12 @@ func (c *ClientManager) OrderBlockVolume(storageType string, location string, si
 				iopsPrice, err = c.selectMaximunIopsItemPriceIdOnSize(size)
 			}
 			if err != nil {
-				return &datatypes.Container_Product_Order_Receipt{}, err
+				return nil, err
 			}
 		} else {
 			iopsPrice, err = FindPerformanceIOPSPrice(productPacakge, size, iops)
 			if err != nil {
-				return &datatypes.Container_Product_Order_Receipt{}, err
+				return nil, err
 			}
 		}
 		prices = append(prices, iopsPrice)
@@ -1093,44 +1422,54 @@ func (c *ClientManager) OrderBlockVolume(storageType string, location string, si
 				},
 			},
 		}
-		orderReceipt, err := c.OrderService.PlaceOrder(&order, sl.Bool(false))
-		if err != nil {
-			return &datatypes.Container_Product_Order_Receipt{}, err
-		}
 
-		return &orderReceipt, nil
+		return &order, nil
 	} else {
-		return &datatypes.Container_Product_Order_Receipt{}, bosherr.Error("Block volume storage_type must be either Performance or Endurance")
+		return nil, bosherr.Error("Block volume storage_type must be either Performance or Endurance")
 	}
 }
 
 func (c *ClientManager) OrderBlockVolume2(storageType string, location string, size int, iops int, snapshotSpace int) (*datatypes.Container_Product_Order_Receipt, error) {
+	order, err := c.blockVolumeOrder2(location, size, iops, snapshotSpace)
+	if err != nil {
+		return &datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	orderReceipt, err := c.OrderService.PlaceOrder(order, sl.Bool(false))
+	if err != nil {
+		return &datatypes.Container_Product_Order_Receipt{}, err
+	}
+
+	return &orderReceipt, nil
+}
+
+func (c *ClientManager) blockVolumeOrder2(location string, size int, iops int, snapshotSpace int) (*datatypes.Container_Product_Order_Network_Storage_AsAService, error) {
 	locationId, err := c.GetLocationId(location)
 	if err != nil {
-		return &datatypes.Container_Product_Order_Receipt{}, bosherr.Error("Invalid datacenter name specified. Please provide the lower case short name (e.g.: dal09)")
+		return nil, invalidLocationError(err)
 	}
 	var prices = make([]datatypes.Product_Item_Price, 0)
 
 	productPacakge, err := c.GetStorageAsServicePackage()
 	if err != nil {
-		return &datatypes.Container_Product_Order_Receipt{}, err
+		return nil, err
 	}
 
 	storagePrice, err := FindSaaSPriceByCategory(productPacakge, "storage_as_a_service")
 	if err != nil {
-		return &datatypes.Container_Product_Order_Receipt{}, err
+		return nil, err
 	}
 	prices = append(prices, storagePrice)
 
 	blockPrice, err := FindSaaSPriceByCategory(productPacakge, "storage_block")
 	if err != nil {
-		return &datatypes.Container_Product_Order_Receipt{}, err
+		return nil, err
 	}
 	prices = append(prices, blockPrice)
 
 	spacePrice, err := FindSaaSPerformSpacePrice(productPacakge, size)
 	if err != nil {
-		return &datatypes.Container_Product_Order_Receipt{}, err
+		return nil, err
 	}
 	prices = append(prices, spacePrice)
 
@@ -1145,12 +1484,12 @@ func (c *ClientManager) OrderBlockVolume2(storageType string, location string, s
 			iopsPrice, err = c.selectMaximunIopsItemPriceIdOnSize(size)
 		}
 		if err != nil {
-			return &datatypes.Container_Product_Order_Receipt{}, err
+			return nil, err
 		}
 	} else {
 		iopsPrice, err = FindSaaSPerformIopsPrice(productPacakge, size, iops)
 		if err != nil {
-			return &datatypes.Container_Product_Order_Receipt{}, err
+			return nil, err
 		}
 	}
 	prices = append(prices, iopsPrice)
@@ -1158,7 +1497,7 @@ func (c *ClientManager) OrderBlockVolume2(storageType string, location string, s
 	if snapshotSpace > 0 {
 		snapshotSpacePrice, err := FindSaaSSnapshotSpacePrice(productPacakge, snapshotSpace, iops)
 		if err != nil {
-			return &datatypes.Container_Product_Order_Receipt{}, err
+			return nil, err
 		}
 		prices = append(prices, snapshotSpacePrice)
 	}
@@ -1180,12 +1519,8 @@ func (c *ClientManager) OrderBlockVolume2(storageType string, location string, s
 		Iops:       sl.Int(iops),
 		VolumeSize: sl.Int(size),
 	}
-	orderReceipt, err := c.OrderService.PlaceOrder(&order, sl.Bool(false))
-	if err != nil {
-		return &datatypes.Container_Product_Order_Receipt{}, err
-	}
 
-	return &orderReceipt, nil
+	return &order, nil
 }
 
 func (c *ClientManager) CreateVolume(location string, size int, iops int, snapshotSpace int) (*datatypes.Network_Storage, error) {
@@ -1202,12 +1537,113 @@ func (c *ClientManager) CreateVolume(location string, size int, iops int, snapsh
 		return &datatypes.Network_Storage{}, err
 	}
 
+	// The volume is billed once ordered, so the order is recorded even if provisioning times out
+	event := receiptAudit(audit.Event{Event: audit.EventOrder, Resource: audit.ResourceBlockVolume}, *receipt)
 	if receipt.OrderId == nil {
+		c.recordAudit(event)
 		return &datatypes.Network_Storage{}, bosherr.Errorf("No order id returned after placing order with size of '%d', iops of '%d', location of `%s`", size, iops, location)
 	}
 
-	until := time.Now().Add(time.Duration(1) * time.Hour)
-	return c.WaitVolumeProvisioningWithOrderId(*receipt.OrderId, until)
+	until := c.clock.Now().Add(c.timeouts.CreateVolume.Duration)
+	volume, err := c.WaitVolumeProvisioningWithOrderId(*receipt.OrderId, until)
+	if err == nil && volume.Id != nil {
+		event.ResourceID = *volume.Id
+	}
+	c.recordAudit(event)
+
+	return volume, err
+}
+
+// Container_Product_Order_Network_Storage_AsAService_Upgrade is missing from the vendored datatypes.
+// The name matters: PlaceOrder derives the order's complexType from it.
+type Container_Product_Order_Network_Storage_AsAService_Upgrade struct {
+	datatypes.Container_Product_Order_Network_Storage_AsAService
+
+	// The volume being upgraded.
+	Volume *datatypes.Network_Storage `json:"volume,omitempty" xmlrpc:"volume,omitempty"`
+}
+
+func (c *ClientManager) UpgradeBlockVolume(volumeId int, size int, iops int) (int, error) {
+	var prices = make([]datatypes.Product_Item_Price, 0)
+
+	productPacakge, err := c.GetStorageAsServicePackage()
+	if err != nil {
+		return 0, err
+	}
+
+	storagePrice, err := FindSaaSPriceByCategory(productPacakge, "storage_as_a_service")
+	if err != nil {
+		return 0, err
+	}
+	prices = append(prices, storagePrice)
+
+	spacePrice, err := FindSaaSPerformSpacePrice(productPacakge, size)
+	if err != nil {
+		return 0, err
+	}
+	prices = append(prices, spacePrice)
+
+	iopsPrice, err := FindSaaSPerformIopsPrice(productPacakge, size, iops)
+	if err != nil {
+		return 0, err
+	}
+	prices = append(prices, iopsPrice)
+
+	order := Container_Product_Order_Network_Storage_AsAService_Upgrade{
+		Container_Product_Order_Network_Storage_AsAService: datatypes.Container_Product_Order_Network_Storage_AsAService{
+			Container_Product_Order: datatypes.Container_Product_Order{
+				PackageId: productPacakge.Id,
+				Prices:    prices,
+				Quantity:  sl.Int(1),
+			},
+			Iops:       sl.Int(iops),
+			VolumeSize: sl.Int(size),
+		},
+		Volume: &datatypes.Network_Storage{
+			Id: sl.Int(volumeId),
+		},
+	}
+
+	c.logger.Debug(softlayerClientLogTag, "Place upgrade order for volume '%d' with size of '%d' and iops of '%d'", volumeId, size, iops)
+	orderReceipt, err := c.OrderService.PlaceOrder(&order, sl.Bool(false))
+	if err != nil {
+		return 0, bosherr.WrapErrorf(err, "Placing upgrade order for volume '%d'", volumeId)
+	}
+
+	if orderReceipt.OrderId == nil {
+		return 0, bosherr.Errorf("No order id returned after placing upgrade order for volume '%d'", volumeId)
+	}
+
+	return *orderReceipt.OrderId, nil
+}
+
+// VerifyVolumeOrder checks the order CreateVolume would place, without placing it
+func (c *ClientManager) VerifyVolumeOrder(location string, size int, iops int, snapshotSpace int) (OrderReport, error) {
+	var order interface{}
+	var err error
+
+	if snapshotSpace == 0 {
+		order, err = c.blockVolumeOrder("performance_storage_iscsi", location, size, iops)
+	} else {
+		order, err = c.blockVolumeOrder2(location, size, iops, snapshotSpace)
+	}
+	if err != nil {
+		// An unknown datacenter or missing prices mean that the volume can not be ordered
+		if isApiFailure(err) {
+			return OrderReport{}, bosherr.WrapError(err, "Building volume order")
+		}
+		return NewRejectedOrderReport(err), nil
+	}
+
+	verifiedOrder, err := c.OrderService.VerifyOrder(order)
+	if err != nil {
+		if isOrderRejection(err) {
+			return NewRejectedOrderReport(err), nil
+		}
+		return OrderReport{}, bosherr.WrapError(err, "Verifying order")
+	}
+
+	return newOrderReport(verifiedOrder), nil
 }
 
 // Creates a snapshot on the given block volume.
@@ -1265,7 +1701,9 @@ func (c *ClientManager) RestoreFromSnapshot(volumeId int, snapshotId int) error
 	return err
 }
 
-func (c *ClientManager) WaitVolumeProvisioningWithOrderId(orderId int, until time.Time) (*datatypes.Network_Storage, error) {
+func (c *ClientManager) WaitVolumeProvisioningWithOrderId(orderId int, until time.Time) (_ *datatypes.Network_Storage, err error) {
+	defer c.observeWait("WaitVolumeProvisioningWithOrderId", c.clock.Now(), &err)
+
 	for {
 		volumes, err := c.getIscsiNetworkStorageWithOrderId(orderId)
 		if err != nil {
@@ -1280,13 +1718,12 @@ func (c *ClientManager) WaitVolumeProvisioningWithOrderId(orderId int, until tim
 			return &volume, nil
 		}
 
-		now := time.Now()
-		if now.After(until) {
+		now := c.clock.Now()
+		if !now.Before(until) {
 			return &datatypes.Network_Storage{}, bosherr.Errorf("Waiting volume provisioning with order id of '%d' has time out", orderId)
 		}
 
-		min := math.Min(float64(5.0), float64(until.Sub(now)))
-		time.Sleep(time.Duration(min) * time.Second)
+		c.sleepBefore(until, c.timeouts.VolumePollInterval.Duration)
 	}
 }
 
@@ -1320,6 +1757,15 @@ func (c *ClientManager) GetStorageAsServicePackage() (datatypes.Product_Package,
 	return c.PackageService.Id(NETWORK_STORAGE_AS_SERVICE_PACKAGE_ID).Mask("id,name,items[prices[categories],attributes]").GetObject()
 }
 
+// invalidLocationError keeps the SoftLayer API error behind a failed datacenter lookup as its cause
+func invalidLocationError(err error) error {
+	if _, ok := err.(sl.Error); ok {
+		return bosherr.WrapError(err, "Invalid datacenter name specified. Please provide the lower case short name (e.g.: dal09)")
+	}
+
+	return bosherr.Error("Invalid datacenter name specified. Please provide the lower case short name (e.g.: dal09)")
+}
+
 func (c *ClientManager) GetLocationId(location string) (int, error) {
 	reqFilter := filter.New(filter.Path("name").Eq(location))
 	datacenters, err := c.LocationService.Mask("longName,id,name").Filter(reqFilter.Build()).GetDatacenters()
@@ -1531,7 +1977,7 @@ func FindPerformanceIOPSPrice(productPackage datatypes.Product_Package, size int
 }
 
 func (c *ClientManager) CancelBlockVolume(volumeId int, reason string, immediate bool) (bool, error) {
-	blockVolume, err := c.GetBlockVolumeDetailsBySoftLayerAccount(volumeId, "id,billingItem.id")
+	blockVolume, err := c.GetBlockVolumeDetailsBySoftLayerAccount(volumeId, "id,notes,billingItem.id")
 	if err != nil {
 		return false, err
 	}
@@ -1540,22 +1986,36 @@ func (c *ClientManager) CancelBlockVolume(volumeId int, reason string, immediate
 		return false, bosherr.Error("No billing item is found to cancel")
 	}
 
-	return c.BillingService.Id(*blockVolume.BillingItem.Id).CancelItem(sl.Bool(immediate), sl.Bool(true), sl.String(reason), sl.String(""))
+	canceled, err := c.BillingService.Id(*blockVolume.BillingItem.Id).CancelItem(sl.Bool(immediate), sl.Bool(true), sl.String(reason), sl.String(""))
+	if err == nil && canceled {
+		c.recordAudit(audit.Event{
+			Event:         audit.EventCancel,
+			Resource:      audit.ResourceBlockVolume,
+			ResourceID:    volumeId,
+			BillingItemID: *blockVolume.BillingItem.Id,
+			Metadata:      volumeMetadata(blockVolume),
+		})
+	}
+
+	return canceled, err
 }
 
 func (c *ClientManager) AuthorizeHostToVolume(instance *datatypes.Virtual_Guest, volumeId int, until time.Time) (bool, error) {
-	for {
+	for retry := 0; ; {
 		allowable, err := c.StorageService.Id(volumeId).AllowAccessFromVirtualGuest(instance)
 		if err != nil {
 			apiErr := err.(sl.Error)
 			if apiErr.Exception == SOFTLAYER_OBJECTNOTFOUND_EXCEPTION {
 				return false, bosherr.WrapErrorf(err, "Unable to find object with id of '%d'", volumeId)
 			}
-			if apiErr.Exception == SOFTLAYER_BLOCKINGOPERATIONINPROGRESS_EXCEPTION {
-				continue
-			}
-			if apiErr.Exception == SOFTLAYER_GROUP_ACCESSCONTROLERROR_EXCEPTION &&
-				strings.Contains(apiErr.Message, "not yet ready for mount") {
+			if apiErr.Exception == SOFTLAYER_BLOCKINGOPERATIONINPROGRESS_EXCEPTION ||
+				(apiErr.Exception == SOFTLAYER_GROUP_ACCESSCONTROLERROR_EXCEPTION && strings.Contains(apiErr.Message, "not yet ready for mount")) {
+				if !c.clock.Now().Before(until) {
+					return false, bosherr.WrapErrorf(err, "Authorizing instance with id '%d' to volume with id '%d' time out after %v", *instance.Id, volumeId, until.String())
+				}
+
+				c.backOff(retry, fmt.Sprintf("authorizing instance with id '%d' to volume with id '%d'", *instance.Id, volumeId), err)
+				retry++
 				continue
 			}
 
@@ -1566,13 +2026,12 @@ func (c *ClientManager) AuthorizeHostToVolume(instance *datatypes.Virtual_Guest,
 			return allowable, nil
 		}
 
-		now := time.Now()
-		if now.After(until) {
+		now := c.clock.Now()
+		if !now.Before(until) {
 			return false, bosherr.Errorf("Authorizing instance with id '%d' to volume with id '%d' time out after %v", *instance.Id, volumeId, until.String())
 		}
 
-		min := math.Min(float64(5.0), float64(until.Sub(now)))
-		time.Sleep(time.Duration(min) * time.Second)
+		c.sleepBefore(until, c.timeouts.VolumePollInterval.Duration)
 	}
 }
 
@@ -1590,7 +2049,7 @@ func (c *ClientManager) DeauthorizeHostToVolume(instance *datatypes.Virtual_Gues
 		return true, nil
 	}
 
-	for {
+	for retry := 0; ; {
 		disAllowed, err := c.StorageService.Id(volumeId).RemoveAccessFromVirtualGuest(instance)
 		if err != nil {
 			apiErr := err.(sl.Error)
@@ -1598,6 +2057,12 @@ func (c *ClientManager) DeauthorizeHostToVolume(instance *datatypes.Virtual_Gues
 				return false, bosherr.Errorf("Unable to find object with id of '%d'", volumeId)
 			}
 			if apiErr.Exception == SOFTLAYER_BLOCKINGOPERATIONINPROGRESS_EXCEPTION {
+				if !c.clock.Now().Before(until) {
+					return false, bosherr.WrapErrorf(err, "De-Authorizing instance with id '%d' to volume with id '%d' time out after %v", *instance.Id, volumeId, until.String())
+				}
+
+				c.backOff(retry, fmt.Sprintf("de-authorizing instance with id '%d' to volume with id '%d'", *instance.Id, volumeId), err)
+				retry++
 				continue
 			}
 			return false, err
@@ -1607,19 +2072,126 @@ func (c *ClientManager) DeauthorizeHostToVolume(instance *datatypes.Virtual_Gues
 			return disAllowed, nil
 		}
 
-		now := time.Now()
-		if now.After(until) {
+		now := c.clock.Now()
+		if !now.Before(until) {
 			return false, bosherr.Errorf("De-Authorizing instance with id '%d' to volume with id '%d' time out after %v", *instance.Id, volumeId, until.String())
 		}
 
-		min := math.Min(float64(5.0), float64(until.Sub(now)))
-		time.Sleep(time.Duration(min) * time.Second)
+		c.sleepBefore(until, c.timeouts.VolumePollInterval.Duration)
 	}
 }
 
+// AttachPrimaryIpv6Address orders a primary IPv6 address for the public network component of the virtual guest
+func (c *ClientManager) AttachPrimaryIpv6Address(id int) error {
+	var err error
+	until := c.clock.Now().Add(c.timeouts.UpgradeInstance.Duration)
+	if err = c.WaitInstanceHasNoneActiveTransaction(id, until); err != nil {
+		return bosherr.WrapError(err, "Waiting until instance has none active transaction before ordering IPv6 address")
+	}
+
+	itemPrice, presetId, err := c.getUpgradeItemPriceByCategory(id, PRIMARY_IPV6_CATEGORY_CODE)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Finding IPv6 address price for virtual guest of id '%d'", id)
+	}
+
+	packageID, err := c.getVirtualServerPackageID()
+	if err != nil {
+		return err
+	}
+
+	orderId, err := c.placeUpgradeOrder(id, packageID, []datatypes.Product_Item_Price{*itemPrice}, presetId, "Add primary IPv6 address.")
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Adding primary IPv6 address to virtual guest of id '%d'", id)
+	}
+
+	// The virtual guest already has the address, e.g. when it is reused by an os_reload
+	if orderId == 0 {
+		return nil
+	}
+
+	until = c.clock.Now().Add(c.timeouts.UpgradeInstance.Duration)
+	if err = c.WaitOrderCompleted(orderId, until); err != nil {
+		return bosherr.WrapError(err, "Waiting until order placed has been completed after ordering IPv6 address")
+	}
+
+	until = c.clock.Now().Add(c.timeouts.UpgradeInstance.Duration)
+	if err = c.WaitInstanceUntilReady(id, until); err != nil {
+		return bosherr.WrapError(err, "Waiting until instance is ready after ordering IPv6 address")
+	}
+
+	return nil
+}
+
+// getUpgradeItemPriceByCategory returns the first upgrade price of the category for the virtual guest,
+// along with the preset id it was ordered with
+func (c *ClientManager) getUpgradeItemPriceByCategory(id int, categoryCode string) (*datatypes.Product_Item_Price, int, error) {
+	mask := "id, categories[id, categoryCode], item[description, capacity]"
+	itemPrices, err := c.VirtualGuestService.Id(id).Mask(mask).GetUpgradeItemPrices(sl.Bool(true))
+	if err != nil {
+		return &datatypes.Product_Item_Price{}, 0, err
+	}
+
+	itemPrice, found := itemPriceInCategory(itemPrices, categoryCode)
+	if !found {
+		return &datatypes.Product_Item_Price{}, 0, bosherr.Errorf("No upgrade price found in category '%s'", categoryCode)
+	}
+
+	virtualGuest, err := c.VirtualGuestService.Id(id).Mask(INSTANCE_ORDER_PRESET_MASK).GetObject()
+	if err != nil {
+		return &datatypes.Product_Item_Price{}, 0, err
+	}
+
+	return itemPrice, orderPresetId(virtualGuest), nil
+}
+
+// FindVirtualServerItemPrice returns the standard price of the category in the virtual server package, so that
+// an upgrade can be checked for before the virtual guest it would be ordered for exists
+func (c *ClientManager) FindVirtualServerItemPrice(categoryCode string) (*datatypes.Product_Item_Price, bool, error) {
+	packageID, err := c.getVirtualServerPackageID()
+	if err != nil {
+		return &datatypes.Product_Item_Price{}, false, err
+	}
+
+	filters := filter.New()
+	filters = append(filters, filter.Path("itemPrices.categories.categoryCode").Eq(categoryCode))
+	itemPrices, err := c.PackageService.Id(packageID).Mask("id, locationGroupId, categories[categoryCode]").Filter(filters.Build()).GetItemPrices()
+	if err != nil {
+		return &datatypes.Product_Item_Price{}, false, err
+	}
+
+	standardPrices := itemsFilter(itemPrices, func(itemPrice datatypes.Product_Item_Price) bool {
+		return itemPrice.LocationGroupId == nil
+	})
+	itemPrice, found := itemPriceInCategory(standardPrices, categoryCode)
+	return itemPrice, found, nil
+}
+
+// itemPriceInCategory returns the first of the item prices in the category
+func itemPriceInCategory(itemPrices []datatypes.Product_Item_Price, categoryCode string) (*datatypes.Product_Item_Price, bool) {
+	for i := range itemPrices {
+		for _, category := range itemPrices[i].Categories {
+			if category.CategoryCode != nil && *category.CategoryCode == categoryCode {
+				return &itemPrices[i], true
+			}
+		}
+	}
+
+	return &datatypes.Product_Item_Price{}, false
+}
+
+// orderPresetId returns the preset id the virtual guest was ordered with, which its upgrade orders have to be
+// placed with too, or 0 for virtual guests ordered without a preset
+func orderPresetId(virtualGuest datatypes.Virtual_Guest) int {
+	if virtualGuest.BillingItem == nil || virtualGuest.BillingItem.OrderItem == nil || virtualGuest.BillingItem.OrderItem.PresetId == nil {
+		return 0
+	}
+
+	return *virtualGuest.BillingItem.OrderItem.PresetId
+}
+
 func (c *ClientManager) AttachSecondDiskToInstance(id int, diskSize int) error {
 	var err error
-	until := time.Now().Add(time.Duration(1) * time.Hour)
+	until := c.clock.Now().Add(c.timeouts.UpgradeInstance.Duration)
 	if err = c.WaitInstanceHasNoneActiveTransaction(*sl.Int(id), until); err != nil {
 		return bosherr.WrapError(err, "Waiting until instance has none active transaction before os_reload")
 	}
@@ -1629,12 +2201,12 @@ func (c *ClientManager) AttachSecondDiskToInstance(id int, diskSize int) error {
 		return bosherr.WrapErrorf(err, "Adding second disk with size '%d' to virutal guest of id '%d'", diskSize, id)
 	}
 
-	until = time.Now().Add(time.Duration(1) * time.Hour)
+	until = c.clock.Now().Add(c.timeouts.UpgradeInstance.Duration)
 	if err = c.WaitOrderCompleted(orderId, until); err != nil {
 		return bosherr.WrapError(err, "Waiting until order placed has been completed after upgrading instance")
 	}
 
-	until = time.Now().Add(time.Duration(1) * time.Hour)
+	until = c.clock.Now().Add(c.timeouts.UpgradeInstance.Duration)
 	if err = c.WaitInstanceUntilReady(*sl.Int(id), until); err != nil {
 		return bosherr.WrapError(err, "Waiting until instance is ready after os_reload")
 	}
@@ -1644,7 +2216,7 @@ func (c *ClientManager) AttachSecondDiskToInstance(id int, diskSize int) error {
 
 func (c *ClientManager) UpgradeInstanceConfig(id int, cpu int, memory int, network int, privateCPU bool, dedicatedHost bool) error {
 	var err error
-	until := time.Now().Add(time.Duration(1) * time.Hour)
+	until := c.clock.Now().Add(c.timeouts.UpgradeInstance.Duration)
 	if err = c.WaitInstanceHasNoneActiveTransaction(*sl.Int(id), until); err != nil {
 		return bosherr.WrapError(err, "Waiting until instance has none active transaction before os_reload")
 	}
@@ -1654,12 +2226,12 @@ func (c *ClientManager) UpgradeInstanceConfig(id int, cpu int, memory int, netwo
 		return bosherr.WrapErrorf(err, "Upgrading configuration to virutal guest of id '%d'", id)
 	}
 
-	until = time.Now().Add(time.Duration(1) * time.Hour)
+	until = c.clock.Now().Add(c.timeouts.UpgradeInstance.Duration)
 	if err = c.WaitOrderCompleted(orderId, until); err != nil {
 		return bosherr.WrapError(err, "Waiting until order placed has been completed after upgrading instance")
 	}
 
-	until = time.Now().Add(time.Duration(1) * time.Hour)
+	until = c.clock.Now().Add(c.timeouts.UpgradeInstance.Duration)
 	if err = c.WaitInstanceUntilReady(*sl.Int(id), until); err != nil {
 		return bosherr.WrapError(err, "Waiting until instance is ready after os_reload")
 	}
@@ -1741,8 +2313,8 @@ func (c *ClientManager) CreateTicket(ticketSubject *string, ticketTitle *string,
 }
 
 func (c *ClientManager) SetUserDataWithID(id int, userData *registry.SoftlayerUserData) error {
-	until := time.Now().Add(300 * time.Second)
-	interval := 30 * time.Second
+	until := c.clock.Now().Add(c.timeouts.SetUserData.Duration)
+	interval := c.timeouts.UserDataPollInterval.Duration
 
 	userData.Server = registry.SoftlayerUserDataServerName{
 		Name: strconv.Itoa(id),
@@ -1765,12 +2337,12 @@ func (c *ClientManager) SetUserDataWithID(id int, userData *registry.SoftlayerUs
 			return nil
 		}
 
-		now := time.Now()
-		if now.After(until) {
+		now := c.clock.Now()
+		if !now.Before(until) {
 			return bosherr.Errorf("setUserMetadata on virtual guest with id %d Time Out!", id)
 		}
 
-		time.Sleep(interval)
+		c.sleepBefore(until, interval)
 	}
 }
 
@@ -1790,6 +2362,32 @@ func (c *ClientManager) SetInstanceMetadata(id int, encodedUserData *string) (bo
 	return true, nil
 }
 
+func (c *ClientManager) GetUserDataWithID(id int) (*registry.SoftlayerUserData, bool, error) {
+	attributes, err := c.VirtualGuestService.Id(id).GetUserData()
+	if err != nil {
+		if apiErr, ok := err.(sl.Error); ok && apiErr.Exception == SOFTLAYER_OBJECTNOTFOUND_EXCEPTION {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	userData := &registry.SoftlayerUserData{}
+	if len(attributes) == 0 || attributes[0].Value == nil {
+		return userData, true, nil
+	}
+
+	contentsBytes, err := base64.RawURLEncoding.DecodeString(*attributes[0].Value)
+	if err != nil {
+		return nil, true, bosherr.WrapErrorf(err, "Decoding user data of virtual guest with id %d", id)
+	}
+
+	if err = json.Unmarshal(contentsBytes, userData); err != nil {
+		return nil, true, bosherr.WrapErrorf(err, "Unmarshalling user data of virtual guest with id %d", id)
+	}
+
+	return userData, true, nil
+}
+
 func (c *ClientManager) CreateSwiftContainer(containerName string) error {
 	c.logger.Debug(softlayerClientLogTag, "Create a Swift container name '%s'", containerName)
 
@@ -1892,7 +2490,7 @@ func (c *ClientManager) CreateImageFromExternalSource(imageName string, note str
 	}
 
 	// Set image boot mode
-	until := time.Now().Add(time.Duration(20) * time.Second)
+	until := c.clock.Now().Add(c.timeouts.ImageBootMode.Duration)
 	err = c.setImageBootModeAsHVM(*vgbdtgObject.Id, until)
 	if err != nil {
 		return 0, bosherr.WrapErrorf(err, "Set boot mode of image template")
@@ -1917,13 +2515,12 @@ func (c *ClientManager) setImageBootModeAsHVM(id int, until time.Time) error {
 			return nil
 		}
 
-		now := time.Now()
-		if now.After(until) {
+		now := c.clock.Now()
+		if !now.Before(until) {
 			return bosherr.Errorf("Set boot mode on image with id %d Time Out!", id)
 		}
 
-		min := math.Min(float64(10.0), float64(until.Sub(now)))
-		time.Sleep(time.Duration(min) * time.Second)
+		c.sleepBefore(until, c.timeouts.ImagePollInterval.Duration)
 	}
 }
 
@@ -1971,13 +2568,12 @@ func (c *ClientManager) reloadOperatingSystemWithConfig(id int, config *datatype
 			return bosherr.WrapErrorf(err, "Reloading operating system of virtual guest '%d'", id)
 		}
 
-		now := time.Now()
-		if now.After(until) {
+		now := c.clock.Now()
+		if !now.Before(until) {
 			return bosherr.WrapErrorf(slError, "Reloading instance '%d' time out", id)
 		}
 
-		min := math.Min(float64(2.0), float64(until.Sub(now)))
-		time.Sleep(time.Duration(min) * time.Second)
+		c.sleepBefore(until, c.timeouts.ReloadPollInterval.Duration)
 	}
 
 	return nil
@@ -5,6 +5,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -18,8 +19,10 @@ import (
 	"github.com/softlayer/softlayer-go/sl"
 
 	"bosh-softlayer-cpi/api"
+	"bosh-softlayer-cpi/audit"
 	cpiLog "bosh-softlayer-cpi/logger"
 	slClient "bosh-softlayer-cpi/softlayer/client"
+	boslconfig "bosh-softlayer-cpi/softlayer/config"
 	vpsVm "bosh-softlayer-cpi/softlayer/vps_service/client/vm"
 	"bosh-softlayer-cpi/test_helpers"
 )
@@ -62,7 +65,7 @@ var _ = Describe("DiskHandler", func() {
 		logger = cpiLog.NewLogger(boshlogger.LevelDebug, strconv.Itoa(nanos))
 		multiLogger = api.MultiLogger{Logger: logger, LogBuff: &errOutLog}
 		sess = test_helpers.NewFakeSoftlayerSession(transportHandler)
-		cli = slClient.NewSoftLayerClientManager(sess, vps, swiftClient, logger)
+		cli = slClient.NewSoftLayerClientManager(sess, vps, swiftClient, logger).WithClock(test_helpers.NewFakeClock(time.Now()))
 
 		diskID = 17336531
 		networkConnInfoID = 123456789
@@ -447,6 +450,32 @@ var _ = Describe("DiskHandler", func() {
 				_, err := cli.CancelBlockVolume(diskID, "Unit test do cancel volume action", false)
 				Expect(err).NotTo(HaveOccurred())
 			})
+
+			It("records the cancellation in the audit trail", func() {
+				respParas = []map[string]interface{}{
+					{
+						"filename":   "SoftLayer_Account_getIscsiNetworkStorage.json",
+						"statusCode": http.StatusOK,
+					},
+					{
+						"filename":   "SoftLayer_Billing_Item_cancelItem.json",
+						"statusCode": http.StatusOK,
+					},
+				}
+				err = test_helpers.SpecifyServerResps(respParas, server)
+				Expect(err).NotTo(HaveOccurred())
+
+				var trailOut bytes.Buffer
+				_, err := cli.WithAudit(audit.NewTrail(&trailOut)).CancelBlockVolume(diskID, "Unit test do cancel volume action", false)
+				Expect(err).NotTo(HaveOccurred())
+
+				var event audit.Event
+				Expect(json.Unmarshal(trailOut.Bytes(), &event)).To(Succeed())
+				Expect(event.Event).To(Equal(audit.EventCancel))
+				Expect(event.Resource).To(Equal(audit.ResourceBlockVolume))
+				Expect(event.ResourceID).To(Equal(diskID))
+				Expect(event.BillingItemID).To(Equal(140952229))
+			})
 		})
 
 		Context("when BillingService cancelItem call return an error", func() {
@@ -497,6 +526,41 @@ var _ = Describe("DiskHandler", func() {
 				_, err := cli.AuthorizeHostToVolume(vg, diskID, time.Now().Add(1*time.Hour))
 				Expect(err).NotTo(HaveOccurred())
 			})
+
+			It("backs off while a blocking operation is in progress", func() {
+				respParas = []map[string]interface{}{
+					{
+						"filename":   "SoftLayer_Network_Storage_allowAccessFromVirtualGuest_Blocking.json",
+						"statusCode": http.StatusInternalServerError,
+					},
+					{
+						"filename":   "SoftLayer_Network_Storage_allowAccessFromVirtualGuest_GroupAccessControlError.json",
+						"statusCode": http.StatusInternalServerError,
+					},
+					{
+						"filename":   "SoftLayer_Network_Storage_allowAccessFromVirtualGuest.json",
+						"statusCode": http.StatusOK,
+					},
+				}
+				err = test_helpers.SpecifyServerResps(respParas, server)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeClock := test_helpers.NewFakeClock(time.Now())
+				jitter := 0.1
+				cli = cli.WithClock(fakeClock).WithRetryPolicy(boslconfig.RetryPolicy{
+					InitialDelay: boslconfig.NewDuration(10 * time.Second),
+					Jitter:       &jitter,
+				})
+
+				allowed, err := cli.AuthorizeHostToVolume(vg, diskID, fakeClock.Now().Add(1*time.Hour))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(allowed).To(BeTrue())
+
+				sleeps := fakeClock.SleptTimes()
+				Expect(sleeps).To(HaveLen(2))
+				Expect(sleeps[0]).To(BeNumerically("~", 10*time.Second, time.Second))
+				Expect(sleeps[1]).To(BeNumerically("~", 20*time.Second, 2*time.Second))
+			})
 		})
 
 		Context("when StorageService allowAccessFromVirtualGuest call return an error", func() {
@@ -582,6 +646,36 @@ var _ = Describe("DiskHandler", func() {
 				_, err := cli.DeauthorizeHostToVolume(vg, diskID, time.Now().Add(1*time.Hour))
 				Expect(err).NotTo(HaveOccurred())
 			})
+
+			It("stops backing off when the timeout passes", func() {
+				respParas = []map[string]interface{}{
+					{
+						"filename":   "SoftLayer_Network_Storage_getAllowedVirtualGuests.json",
+						"statusCode": http.StatusOK,
+					},
+					{
+						"filename":   "SoftLayer_Network_Storage_removeAccessFromVirtualGuest_Blocking.json",
+						"statusCode": http.StatusInternalServerError,
+					},
+					{
+						"filename":   "SoftLayer_Network_Storage_removeAccessFromVirtualGuest_Blocking.json",
+						"statusCode": http.StatusInternalServerError,
+					},
+				}
+				err = test_helpers.SpecifyServerResps(respParas, server)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeClock := test_helpers.NewFakeClock(time.Now())
+				cli = cli.WithClock(fakeClock).WithRetryPolicy(boslconfig.RetryPolicy{
+					InitialDelay: boslconfig.NewDuration(time.Minute),
+				})
+
+				_, err := cli.DeauthorizeHostToVolume(vg, diskID, fakeClock.Now().Add(30*time.Second))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("De-Authorizing instance with id '%d' to volume with id '%d' time out", *vg.Id, diskID)))
+				Expect(err.Error()).To(ContainSubstring("Blocking operation in progress"))
+				Expect(fakeClock.SleptTimes()).To(HaveLen(1))
+			})
 		})
 
 		Context("when StorageService removeAccessFromVirtualGuest call return an error", func() {
@@ -1402,6 +1496,87 @@ var _ = Describe("DiskHandler", func() {
 		})
 	})
 
+	Describe("UpgradeBlockVolume", func() {
+		It("Upgrade successfully", func() {
+			respParas = []map[string]interface{}{
+				// GetStorageAsServicePackage
+				{
+					"filename":   "SoftLayer_Product_Package_getObject_StorageAsService.json",
+					"statusCode": http.StatusOK,
+				},
+				// PlaceOrder
+				{
+					"filename":   "SoftLayer_Product_Order_placeOrder.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			orderId, err := cli.UpgradeBlockVolume(12345678, 250, 1500)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orderId).NotTo(Equal(0))
+		})
+
+		It("Return error when unable to find price for storage_as_a_service storage", func() {
+			respParas = []map[string]interface{}{
+				// GetStorageAsServicePackage
+				{
+					"filename":   "SoftLayer_Product_Package_getObject_Performance.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err := cli.UpgradeBlockVolume(12345678, 250, 1500)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Unable to find price storage category"))
+		})
+
+		It("Return error when call placeOrder return an error", func() {
+			respParas = []map[string]interface{}{
+				// GetStorageAsServicePackage
+				{
+					"filename":   "SoftLayer_Product_Package_getObject_StorageAsService.json",
+					"statusCode": http.StatusOK,
+				},
+				// PlaceOrder
+				{
+					"filename":   "SoftLayer_Product_Order_placeOrder_InternalError.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err := cli.UpgradeBlockVolume(12345678, 250, 1500)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Placing upgrade order for volume '12345678'"))
+		})
+
+		It("Return error when call placeOrder return receipt without order id", func() {
+			respParas = []map[string]interface{}{
+				// GetStorageAsServicePackage
+				{
+					"filename":   "SoftLayer_Product_Package_getObject_StorageAsService.json",
+					"statusCode": http.StatusOK,
+				},
+				// PlaceOrder
+				{
+					"filename":   "SoftLayer_Product_Order_placeOrder_Without_Orderid.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err := cli.UpgradeBlockVolume(12345678, 250, 1500)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("No order id returned after placing upgrade order"))
+		})
+	})
+
 	Describe("CreateVolume", func() {
 		It("Create successfully", func() {
 			respParas = []map[string]interface{}{
@@ -1521,6 +1696,130 @@ var _ = Describe("DiskHandler", func() {
 		})
 	})
 
+	Describe("VerifyVolumeOrder", func() {
+		It("verifies the order successfully", func() {
+			respParas = []map[string]interface{}{
+				// GetLocationId
+				{
+					"filename":   "SoftLayer_Location_Datacenter_getDatacenters.json",
+					"statusCode": http.StatusOK,
+				},
+				// GetPerformanceIscsiPackage
+				{
+					"filename":   "SoftLayer_Product_Package_getObject_Performance.json",
+					"statusCode": http.StatusOK,
+				},
+				// selectMaximunIopsItemPriceIdOnSize
+				{
+					"filename":   "SoftLayer_Product_Package_getItemPrices.json",
+					"statusCode": http.StatusOK,
+				},
+				// VerifyOrder
+				{
+					"filename":   "SoftLayer_Product_Order_verifyOrder.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			report, err := cli.VerifyVolumeOrder("dal02", 250, 0, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Verified).To(BeTrue())
+			Expect(report.MonthlyRecurringFee).To(Equal(float64(8)))
+			Expect(report.Prices).NotTo(BeEmpty())
+			Expect(report.Prices[0].Category).To(Equal("sov_sec_ip_addresses_pub"))
+			Expect(report.Prices[0].ItemKeyName).To(Equal("4_PORTABLE_PUBLIC_IP_ADDRESSES"))
+		})
+
+		It("reports the order as rejected when the datacenter is invalid", func() {
+			respParas = []map[string]interface{}{
+				// GetLocationId
+				{
+					"filename":   "SoftLayer_Location_Datacenter_getDatacenters.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			report, err := cli.VerifyVolumeOrder("xyz99", 250, 0, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Verified).To(BeFalse())
+			Expect(report.Errors).To(ConsistOf(ContainSubstring("Invalid datacenter name specified")))
+		})
+
+		It("returns an error when looking the datacenter up fails", func() {
+			respParas = []map[string]interface{}{
+				// GetLocationId
+				{
+					"filename":   "SoftLayer_Location_Datacenter_getDatacenters_InternalError.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err := cli.VerifyVolumeOrder("dal02", 250, 0, 0)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Building volume order"))
+			Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+		})
+
+		It("returns an error when getting the storage package fails", func() {
+			respParas = []map[string]interface{}{
+				// GetLocationId
+				{
+					"filename":   "SoftLayer_Location_Datacenter_getDatacenters.json",
+					"statusCode": http.StatusOK,
+				},
+				// GetPerformanceIscsiPackage
+				{
+					"filename":   "SoftLayer_Product_Package_getObject_InternalError.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err := cli.VerifyVolumeOrder("dal02", 250, 0, 0)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Building volume order"))
+		})
+
+		It("reports the order as rejected when SoftLayer refuses it", func() {
+			respParas = []map[string]interface{}{
+				// GetLocationId
+				{
+					"filename":   "SoftLayer_Location_Datacenter_getDatacenters.json",
+					"statusCode": http.StatusOK,
+				},
+				// GetStorageAsServicePackage
+				{
+					"filename":   "SoftLayer_Product_Package_getObject_StorageAsService.json",
+					"statusCode": http.StatusOK,
+				},
+				// selectMaximunIopsItemPriceIdOnSize
+				{
+					"filename":   "SoftLayer_Product_Package_getItemPrices.json",
+					"statusCode": http.StatusOK,
+				},
+				// VerifyOrder
+				{
+					"filename":   "SoftLayer_Product_Order_verifyOrder_InvalidLocation.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			report, err := cli.VerifyVolumeOrder("dal02", 250, 0, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Verified).To(BeFalse())
+			Expect(report.Errors).To(ConsistOf(ContainSubstring("The location provided for this order is invalid")))
+		})
+	})
+
 	Describe("SetNotes", func() {
 		Context("when StorageService editObject call successfully", func() {
 			It("set tags successfully", func() {
@@ -4,9 +4,11 @@ package fakes
 import (
 	"bosh-softlayer-cpi/registry"
 	"bosh-softlayer-cpi/softlayer/client"
+	boslconfig "bosh-softlayer-cpi/softlayer/config"
 	"sync"
 	"time"
 
+	"code.cloudfoundry.org/clock"
 	"github.com/softlayer/softlayer-go/datatypes"
 )
 
@@ -667,6 +669,255 @@ type FakeClient struct {
 		result1 int
 		result2 error
 	}
+	SetUserDataWithIDStub        func(id int, userData *registry.SoftlayerUserData) error
+	setUserDataWithIDMutex       sync.RWMutex
+	setUserDataWithIDArgsForCall []struct {
+		id       int
+		userData *registry.SoftlayerUserData
+	}
+	setUserDataWithIDReturns struct {
+		result1 error
+	}
+	setUserDataWithIDReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetUserDataWithIDStub        func(id int) (*registry.SoftlayerUserData, bool, error)
+	getUserDataWithIDMutex       sync.RWMutex
+	getUserDataWithIDArgsForCall []struct {
+		id int
+	}
+	getUserDataWithIDReturns struct {
+		result1 *registry.SoftlayerUserData
+		result2 bool
+		result3 error
+	}
+	getUserDataWithIDReturnsOnCall map[int]struct {
+		result1 *registry.SoftlayerUserData
+		result2 bool
+		result3 error
+	}
+	GetVirtualServerPackageItemsStub        func() ([]datatypes.Product_Item, error)
+	getVirtualServerPackageItemsMutex       sync.RWMutex
+	getVirtualServerPackageItemsArgsForCall []struct{}
+	getVirtualServerPackageItemsReturns     struct {
+		result1 []datatypes.Product_Item
+		result2 error
+	}
+	getVirtualServerPackageItemsReturnsOnCall map[int]struct {
+		result1 []datatypes.Product_Item
+		result2 error
+	}
+	GetVirtualServerPackagePresetsStub        func() ([]datatypes.Product_Package_Preset, error)
+	getVirtualServerPackagePresetsMutex       sync.RWMutex
+	getVirtualServerPackagePresetsArgsForCall []struct{}
+	getVirtualServerPackagePresetsReturns     struct {
+		result1 []datatypes.Product_Package_Preset
+		result2 error
+	}
+	getVirtualServerPackagePresetsReturnsOnCall map[int]struct {
+		result1 []datatypes.Product_Package_Preset
+		result2 error
+	}
+	WaitOrderCompletedStub        func(id int, until time.Time) error
+	waitOrderCompletedMutex       sync.RWMutex
+	waitOrderCompletedArgsForCall []struct {
+		id    int
+		until time.Time
+	}
+	waitOrderCompletedReturns struct {
+		result1 error
+	}
+	waitOrderCompletedReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpgradeBlockVolumeStub        func(volumeId int, size int, iops int) (int, error)
+	upgradeBlockVolumeMutex       sync.RWMutex
+	upgradeBlockVolumeArgsForCall []struct {
+		volumeId int
+		size     int
+		iops     int
+	}
+	upgradeBlockVolumeReturns struct {
+		result1 int
+		result2 error
+	}
+	upgradeBlockVolumeReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	DeleteImageStub        func(imageId int) error
+	deleteImageMutex       sync.RWMutex
+	deleteImageArgsForCall []struct {
+		imageId int
+	}
+	deleteImageReturns struct {
+		result1 error
+	}
+	deleteImageReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetInstancesByImageStub        func(globalIdentifier string) ([]datatypes.Virtual_Guest, error)
+	getInstancesByImageMutex       sync.RWMutex
+	getInstancesByImageArgsForCall []struct {
+		globalIdentifier string
+	}
+	getInstancesByImageReturns struct {
+		result1 []datatypes.Virtual_Guest
+		result2 error
+	}
+	getInstancesByImageReturnsOnCall map[int]struct {
+		result1 []datatypes.Virtual_Guest
+		result2 error
+	}
+	VerifyInstanceOrderStub        func(template *datatypes.Virtual_Guest) (client.OrderReport, error)
+	verifyInstanceOrderMutex       sync.RWMutex
+	verifyInstanceOrderArgsForCall []struct {
+		template *datatypes.Virtual_Guest
+	}
+	verifyInstanceOrderReturns struct {
+		result1 client.OrderReport
+		result2 error
+	}
+	verifyInstanceOrderReturnsOnCall map[int]struct {
+		result1 client.OrderReport
+		result2 error
+	}
+	VerifyVolumeOrderStub        func(location string, size int, iops int, snapshotSpace int) (client.OrderReport, error)
+	verifyVolumeOrderMutex       sync.RWMutex
+	verifyVolumeOrderArgsForCall []struct {
+		location      string
+		size          int
+		iops          int
+		snapshotSpace int
+	}
+	verifyVolumeOrderReturns struct {
+		result1 client.OrderReport
+		result2 error
+	}
+	verifyVolumeOrderReturnsOnCall map[int]struct {
+		result1 client.OrderReport
+		result2 error
+	}
+	TimeoutsStub        func() boslconfig.Timeouts
+	timeoutsMutex       sync.RWMutex
+	timeoutsArgsForCall []struct{}
+	timeoutsReturns     struct {
+		result1 boslconfig.Timeouts
+	}
+	timeoutsReturnsOnCall map[int]struct {
+		result1 boslconfig.Timeouts
+	}
+	AttachPrimaryIpv6AddressStub        func(id int) error
+	attachPrimaryIpv6AddressMutex       sync.RWMutex
+	attachPrimaryIpv6AddressArgsForCall []struct {
+		id int
+	}
+	attachPrimaryIpv6AddressReturns struct {
+		result1 error
+	}
+	attachPrimaryIpv6AddressReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetIpAddressNoteStub        func(id int, note string) error
+	setIpAddressNoteMutex       sync.RWMutex
+	setIpAddressNoteArgsForCall []struct {
+		id   int
+		note string
+	}
+	setIpAddressNoteReturns struct {
+		result1 error
+	}
+	setIpAddressNoteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetIpAddressesByNoteStub        func(note string) ([]datatypes.Network_Subnet_IpAddress, error)
+	getIpAddressesByNoteMutex       sync.RWMutex
+	getIpAddressesByNoteArgsForCall []struct {
+		note string
+	}
+	getIpAddressesByNoteReturns struct {
+		result1 []datatypes.Network_Subnet_IpAddress
+		result2 error
+	}
+	getIpAddressesByNoteReturnsOnCall map[int]struct {
+		result1 []datatypes.Network_Subnet_IpAddress
+		result2 error
+	}
+	GetGlobalIpRecordsStub        func(ip string, destinationIp string) ([]datatypes.Network_Subnet_IpAddress_Global, error)
+	getGlobalIpRecordsMutex       sync.RWMutex
+	getGlobalIpRecordsArgsForCall []struct {
+		ip            string
+		destinationIp string
+	}
+	getGlobalIpRecordsReturns struct {
+		result1 []datatypes.Network_Subnet_IpAddress_Global
+		result2 error
+	}
+	getGlobalIpRecordsReturnsOnCall map[int]struct {
+		result1 []datatypes.Network_Subnet_IpAddress_Global
+		result2 error
+	}
+	RouteGlobalIpStub        func(id int, destinationIp string) error
+	routeGlobalIpMutex       sync.RWMutex
+	routeGlobalIpArgsForCall []struct {
+		id            int
+		destinationIp string
+	}
+	routeGlobalIpReturns struct {
+		result1 error
+	}
+	routeGlobalIpReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UnrouteGlobalIpStub        func(id int) error
+	unrouteGlobalIpMutex       sync.RWMutex
+	unrouteGlobalIpArgsForCall []struct {
+		id int
+	}
+	unrouteGlobalIpReturns struct {
+		result1 error
+	}
+	unrouteGlobalIpReturnsOnCall map[int]struct {
+		result1 error
+	}
+	FindVirtualServerItemPriceStub        func(categoryCode string) (*datatypes.Product_Item_Price, bool, error)
+	findVirtualServerItemPriceMutex       sync.RWMutex
+	findVirtualServerItemPriceArgsForCall []struct {
+		categoryCode string
+	}
+	findVirtualServerItemPriceReturns struct {
+		result1 *datatypes.Product_Item_Price
+		result2 bool
+		result3 error
+	}
+	findVirtualServerItemPriceReturnsOnCall map[int]struct {
+		result1 *datatypes.Product_Item_Price
+		result2 bool
+		result3 error
+	}
+	ClockStub        func() clock.Clock
+	clockMutex       sync.RWMutex
+	clockArgsForCall []struct {
+	}
+	clockReturns struct {
+		result1 clock.Clock
+	}
+	clockReturnsOnCall map[int]struct {
+		result1 clock.Clock
+	}
+	GetIpAddressNoteStub        func(id int) (string, error)
+	getIpAddressNoteMutex       sync.RWMutex
+	getIpAddressNoteArgsForCall []struct {
+		id int
+	}
+	getIpAddressNoteReturns struct {
+		result1 string
+		result2 error
+	}
+	getIpAddressNoteReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -3080,6 +3331,984 @@ func (fake *FakeClient) CreateImageFromExternalSourceReturnsOnCall(i int, result
 	}{result1, result2}
 }
 
+func (fake *FakeClient) SetUserDataWithID(id int, userData *registry.SoftlayerUserData) error {
+	fake.setUserDataWithIDMutex.Lock()
+	ret, specificReturn := fake.setUserDataWithIDReturnsOnCall[len(fake.setUserDataWithIDArgsForCall)]
+	fake.setUserDataWithIDArgsForCall = append(fake.setUserDataWithIDArgsForCall, struct {
+		id       int
+		userData *registry.SoftlayerUserData
+	}{id, userData})
+	fake.recordInvocation("SetUserDataWithID", []interface{}{id, userData})
+	fake.setUserDataWithIDMutex.Unlock()
+	if fake.SetUserDataWithIDStub != nil {
+		return fake.SetUserDataWithIDStub(id, userData)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.setUserDataWithIDReturns.result1
+}
+
+func (fake *FakeClient) SetUserDataWithIDCallCount() int {
+	fake.setUserDataWithIDMutex.RLock()
+	defer fake.setUserDataWithIDMutex.RUnlock()
+	return len(fake.setUserDataWithIDArgsForCall)
+}
+
+func (fake *FakeClient) SetUserDataWithIDArgsForCall(i int) (int, *registry.SoftlayerUserData) {
+	fake.setUserDataWithIDMutex.RLock()
+	defer fake.setUserDataWithIDMutex.RUnlock()
+	return fake.setUserDataWithIDArgsForCall[i].id, fake.setUserDataWithIDArgsForCall[i].userData
+}
+
+func (fake *FakeClient) SetUserDataWithIDReturns(result1 error) {
+	fake.SetUserDataWithIDStub = nil
+	fake.setUserDataWithIDReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) SetUserDataWithIDReturnsOnCall(i int, result1 error) {
+	fake.SetUserDataWithIDStub = nil
+	if fake.setUserDataWithIDReturnsOnCall == nil {
+		fake.setUserDataWithIDReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setUserDataWithIDReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) GetUserDataWithID(id int) (*registry.SoftlayerUserData, bool, error) {
+	fake.getUserDataWithIDMutex.Lock()
+	ret, specificReturn := fake.getUserDataWithIDReturnsOnCall[len(fake.getUserDataWithIDArgsForCall)]
+	fake.getUserDataWithIDArgsForCall = append(fake.getUserDataWithIDArgsForCall, struct {
+		id int
+	}{id})
+	fake.recordInvocation("GetUserDataWithID", []interface{}{id})
+	fake.getUserDataWithIDMutex.Unlock()
+	if fake.GetUserDataWithIDStub != nil {
+		return fake.GetUserDataWithIDStub(id)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.getUserDataWithIDReturns.result1, fake.getUserDataWithIDReturns.result2, fake.getUserDataWithIDReturns.result3
+}
+
+func (fake *FakeClient) GetUserDataWithIDCallCount() int {
+	fake.getUserDataWithIDMutex.RLock()
+	defer fake.getUserDataWithIDMutex.RUnlock()
+	return len(fake.getUserDataWithIDArgsForCall)
+}
+
+func (fake *FakeClient) GetUserDataWithIDArgsForCall(i int) int {
+	fake.getUserDataWithIDMutex.RLock()
+	defer fake.getUserDataWithIDMutex.RUnlock()
+	return fake.getUserDataWithIDArgsForCall[i].id
+}
+
+func (fake *FakeClient) GetUserDataWithIDReturns(result1 *registry.SoftlayerUserData, result2 bool, result3 error) {
+	fake.GetUserDataWithIDStub = nil
+	fake.getUserDataWithIDReturns = struct {
+		result1 *registry.SoftlayerUserData
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeClient) GetUserDataWithIDReturnsOnCall(i int, result1 *registry.SoftlayerUserData, result2 bool, result3 error) {
+	fake.GetUserDataWithIDStub = nil
+	if fake.getUserDataWithIDReturnsOnCall == nil {
+		fake.getUserDataWithIDReturnsOnCall = make(map[int]struct {
+			result1 *registry.SoftlayerUserData
+			result2 bool
+			result3 error
+		})
+	}
+	fake.getUserDataWithIDReturnsOnCall[i] = struct {
+		result1 *registry.SoftlayerUserData
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeClient) GetVirtualServerPackageItems() ([]datatypes.Product_Item, error) {
+	fake.getVirtualServerPackageItemsMutex.Lock()
+	ret, specificReturn := fake.getVirtualServerPackageItemsReturnsOnCall[len(fake.getVirtualServerPackageItemsArgsForCall)]
+	fake.getVirtualServerPackageItemsArgsForCall = append(fake.getVirtualServerPackageItemsArgsForCall, struct{}{})
+	fake.recordInvocation("GetVirtualServerPackageItems", []interface{}{})
+	fake.getVirtualServerPackageItemsMutex.Unlock()
+	if fake.GetVirtualServerPackageItemsStub != nil {
+		return fake.GetVirtualServerPackageItemsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getVirtualServerPackageItemsReturns.result1, fake.getVirtualServerPackageItemsReturns.result2
+}
+
+func (fake *FakeClient) GetVirtualServerPackageItemsCallCount() int {
+	fake.getVirtualServerPackageItemsMutex.RLock()
+	defer fake.getVirtualServerPackageItemsMutex.RUnlock()
+	return len(fake.getVirtualServerPackageItemsArgsForCall)
+}
+
+func (fake *FakeClient) GetVirtualServerPackageItemsReturns(result1 []datatypes.Product_Item, result2 error) {
+	fake.GetVirtualServerPackageItemsStub = nil
+	fake.getVirtualServerPackageItemsReturns = struct {
+		result1 []datatypes.Product_Item
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetVirtualServerPackageItemsReturnsOnCall(i int, result1 []datatypes.Product_Item, result2 error) {
+	fake.GetVirtualServerPackageItemsStub = nil
+	if fake.getVirtualServerPackageItemsReturnsOnCall == nil {
+		fake.getVirtualServerPackageItemsReturnsOnCall = make(map[int]struct {
+			result1 []datatypes.Product_Item
+			result2 error
+		})
+	}
+	fake.getVirtualServerPackageItemsReturnsOnCall[i] = struct {
+		result1 []datatypes.Product_Item
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetVirtualServerPackagePresets() ([]datatypes.Product_Package_Preset, error) {
+	fake.getVirtualServerPackagePresetsMutex.Lock()
+	ret, specificReturn := fake.getVirtualServerPackagePresetsReturnsOnCall[len(fake.getVirtualServerPackagePresetsArgsForCall)]
+	fake.getVirtualServerPackagePresetsArgsForCall = append(fake.getVirtualServerPackagePresetsArgsForCall, struct{}{})
+	fake.recordInvocation("GetVirtualServerPackagePresets", []interface{}{})
+	fake.getVirtualServerPackagePresetsMutex.Unlock()
+	if fake.GetVirtualServerPackagePresetsStub != nil {
+		return fake.GetVirtualServerPackagePresetsStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getVirtualServerPackagePresetsReturns.result1, fake.getVirtualServerPackagePresetsReturns.result2
+}
+
+func (fake *FakeClient) GetVirtualServerPackagePresetsCallCount() int {
+	fake.getVirtualServerPackagePresetsMutex.RLock()
+	defer fake.getVirtualServerPackagePresetsMutex.RUnlock()
+	return len(fake.getVirtualServerPackagePresetsArgsForCall)
+}
+
+func (fake *FakeClient) GetVirtualServerPackagePresetsReturns(result1 []datatypes.Product_Package_Preset, result2 error) {
+	fake.GetVirtualServerPackagePresetsStub = nil
+	fake.getVirtualServerPackagePresetsReturns = struct {
+		result1 []datatypes.Product_Package_Preset
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetVirtualServerPackagePresetsReturnsOnCall(i int, result1 []datatypes.Product_Package_Preset, result2 error) {
+	fake.GetVirtualServerPackagePresetsStub = nil
+	if fake.getVirtualServerPackagePresetsReturnsOnCall == nil {
+		fake.getVirtualServerPackagePresetsReturnsOnCall = make(map[int]struct {
+			result1 []datatypes.Product_Package_Preset
+			result2 error
+		})
+	}
+	fake.getVirtualServerPackagePresetsReturnsOnCall[i] = struct {
+		result1 []datatypes.Product_Package_Preset
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) WaitOrderCompleted(id int, until time.Time) error {
+	fake.waitOrderCompletedMutex.Lock()
+	ret, specificReturn := fake.waitOrderCompletedReturnsOnCall[len(fake.waitOrderCompletedArgsForCall)]
+	fake.waitOrderCompletedArgsForCall = append(fake.waitOrderCompletedArgsForCall, struct {
+		id    int
+		until time.Time
+	}{id, until})
+	fake.recordInvocation("WaitOrderCompleted", []interface{}{id, until})
+	fake.waitOrderCompletedMutex.Unlock()
+	if fake.WaitOrderCompletedStub != nil {
+		return fake.WaitOrderCompletedStub(id, until)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.waitOrderCompletedReturns.result1
+}
+
+func (fake *FakeClient) WaitOrderCompletedCallCount() int {
+	fake.waitOrderCompletedMutex.RLock()
+	defer fake.waitOrderCompletedMutex.RUnlock()
+	return len(fake.waitOrderCompletedArgsForCall)
+}
+
+func (fake *FakeClient) WaitOrderCompletedArgsForCall(i int) (int, time.Time) {
+	fake.waitOrderCompletedMutex.RLock()
+	defer fake.waitOrderCompletedMutex.RUnlock()
+	return fake.waitOrderCompletedArgsForCall[i].id, fake.waitOrderCompletedArgsForCall[i].until
+}
+
+func (fake *FakeClient) WaitOrderCompletedReturns(result1 error) {
+	fake.WaitOrderCompletedStub = nil
+	fake.waitOrderCompletedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) WaitOrderCompletedReturnsOnCall(i int, result1 error) {
+	fake.WaitOrderCompletedStub = nil
+	if fake.waitOrderCompletedReturnsOnCall == nil {
+		fake.waitOrderCompletedReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.waitOrderCompletedReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) UpgradeBlockVolume(volumeId int, size int, iops int) (int, error) {
+	fake.upgradeBlockVolumeMutex.Lock()
+	ret, specificReturn := fake.upgradeBlockVolumeReturnsOnCall[len(fake.upgradeBlockVolumeArgsForCall)]
+	fake.upgradeBlockVolumeArgsForCall = append(fake.upgradeBlockVolumeArgsForCall, struct {
+		volumeId int
+		size     int
+		iops     int
+	}{volumeId, size, iops})
+	fake.recordInvocation("UpgradeBlockVolume", []interface{}{volumeId, size, iops})
+	fake.upgradeBlockVolumeMutex.Unlock()
+	if fake.UpgradeBlockVolumeStub != nil {
+		return fake.UpgradeBlockVolumeStub(volumeId, size, iops)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.upgradeBlockVolumeReturns.result1, fake.upgradeBlockVolumeReturns.result2
+}
+
+func (fake *FakeClient) UpgradeBlockVolumeCallCount() int {
+	fake.upgradeBlockVolumeMutex.RLock()
+	defer fake.upgradeBlockVolumeMutex.RUnlock()
+	return len(fake.upgradeBlockVolumeArgsForCall)
+}
+
+func (fake *FakeClient) UpgradeBlockVolumeArgsForCall(i int) (int, int, int) {
+	fake.upgradeBlockVolumeMutex.RLock()
+	defer fake.upgradeBlockVolumeMutex.RUnlock()
+	return fake.upgradeBlockVolumeArgsForCall[i].volumeId, fake.upgradeBlockVolumeArgsForCall[i].size, fake.upgradeBlockVolumeArgsForCall[i].iops
+}
+
+func (fake *FakeClient) UpgradeBlockVolumeReturns(result1 int, result2 error) {
+	fake.UpgradeBlockVolumeStub = nil
+	fake.upgradeBlockVolumeReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) UpgradeBlockVolumeReturnsOnCall(i int, result1 int, result2 error) {
+	fake.UpgradeBlockVolumeStub = nil
+	if fake.upgradeBlockVolumeReturnsOnCall == nil {
+		fake.upgradeBlockVolumeReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.upgradeBlockVolumeReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteImage(imageId int) error {
+	fake.deleteImageMutex.Lock()
+	ret, specificReturn := fake.deleteImageReturnsOnCall[len(fake.deleteImageArgsForCall)]
+	fake.deleteImageArgsForCall = append(fake.deleteImageArgsForCall, struct {
+		imageId int
+	}{imageId})
+	fake.recordInvocation("DeleteImage", []interface{}{imageId})
+	fake.deleteImageMutex.Unlock()
+	if fake.DeleteImageStub != nil {
+		return fake.DeleteImageStub(imageId)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.deleteImageReturns.result1
+}
+
+func (fake *FakeClient) DeleteImageCallCount() int {
+	fake.deleteImageMutex.RLock()
+	defer fake.deleteImageMutex.RUnlock()
+	return len(fake.deleteImageArgsForCall)
+}
+
+func (fake *FakeClient) DeleteImageArgsForCall(i int) int {
+	fake.deleteImageMutex.RLock()
+	defer fake.deleteImageMutex.RUnlock()
+	return fake.deleteImageArgsForCall[i].imageId
+}
+
+func (fake *FakeClient) DeleteImageReturns(result1 error) {
+	fake.DeleteImageStub = nil
+	fake.deleteImageReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteImageReturnsOnCall(i int, result1 error) {
+	fake.DeleteImageStub = nil
+	if fake.deleteImageReturnsOnCall == nil {
+		fake.deleteImageReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteImageReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) GetInstancesByImage(globalIdentifier string) ([]datatypes.Virtual_Guest, error) {
+	fake.getInstancesByImageMutex.Lock()
+	ret, specificReturn := fake.getInstancesByImageReturnsOnCall[len(fake.getInstancesByImageArgsForCall)]
+	fake.getInstancesByImageArgsForCall = append(fake.getInstancesByImageArgsForCall, struct {
+		globalIdentifier string
+	}{globalIdentifier})
+	fake.recordInvocation("GetInstancesByImage", []interface{}{globalIdentifier})
+	fake.getInstancesByImageMutex.Unlock()
+	if fake.GetInstancesByImageStub != nil {
+		return fake.GetInstancesByImageStub(globalIdentifier)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getInstancesByImageReturns.result1, fake.getInstancesByImageReturns.result2
+}
+
+func (fake *FakeClient) GetInstancesByImageCallCount() int {
+	fake.getInstancesByImageMutex.RLock()
+	defer fake.getInstancesByImageMutex.RUnlock()
+	return len(fake.getInstancesByImageArgsForCall)
+}
+
+func (fake *FakeClient) GetInstancesByImageArgsForCall(i int) string {
+	fake.getInstancesByImageMutex.RLock()
+	defer fake.getInstancesByImageMutex.RUnlock()
+	return fake.getInstancesByImageArgsForCall[i].globalIdentifier
+}
+
+func (fake *FakeClient) GetInstancesByImageReturns(result1 []datatypes.Virtual_Guest, result2 error) {
+	fake.GetInstancesByImageStub = nil
+	fake.getInstancesByImageReturns = struct {
+		result1 []datatypes.Virtual_Guest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetInstancesByImageReturnsOnCall(i int, result1 []datatypes.Virtual_Guest, result2 error) {
+	fake.GetInstancesByImageStub = nil
+	if fake.getInstancesByImageReturnsOnCall == nil {
+		fake.getInstancesByImageReturnsOnCall = make(map[int]struct {
+			result1 []datatypes.Virtual_Guest
+			result2 error
+		})
+	}
+	fake.getInstancesByImageReturnsOnCall[i] = struct {
+		result1 []datatypes.Virtual_Guest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) VerifyInstanceOrder(template *datatypes.Virtual_Guest) (client.OrderReport, error) {
+	fake.verifyInstanceOrderMutex.Lock()
+	ret, specificReturn := fake.verifyInstanceOrderReturnsOnCall[len(fake.verifyInstanceOrderArgsForCall)]
+	fake.verifyInstanceOrderArgsForCall = append(fake.verifyInstanceOrderArgsForCall, struct {
+		template *datatypes.Virtual_Guest
+	}{template})
+	fake.recordInvocation("VerifyInstanceOrder", []interface{}{template})
+	fake.verifyInstanceOrderMutex.Unlock()
+	if fake.VerifyInstanceOrderStub != nil {
+		return fake.VerifyInstanceOrderStub(template)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.verifyInstanceOrderReturns.result1, fake.verifyInstanceOrderReturns.result2
+}
+
+func (fake *FakeClient) VerifyInstanceOrderCallCount() int {
+	fake.verifyInstanceOrderMutex.RLock()
+	defer fake.verifyInstanceOrderMutex.RUnlock()
+	return len(fake.verifyInstanceOrderArgsForCall)
+}
+
+func (fake *FakeClient) VerifyInstanceOrderArgsForCall(i int) *datatypes.Virtual_Guest {
+	fake.verifyInstanceOrderMutex.RLock()
+	defer fake.verifyInstanceOrderMutex.RUnlock()
+	return fake.verifyInstanceOrderArgsForCall[i].template
+}
+
+func (fake *FakeClient) VerifyInstanceOrderReturns(result1 client.OrderReport, result2 error) {
+	fake.VerifyInstanceOrderStub = nil
+	fake.verifyInstanceOrderReturns = struct {
+		result1 client.OrderReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) VerifyInstanceOrderReturnsOnCall(i int, result1 client.OrderReport, result2 error) {
+	fake.VerifyInstanceOrderStub = nil
+	if fake.verifyInstanceOrderReturnsOnCall == nil {
+		fake.verifyInstanceOrderReturnsOnCall = make(map[int]struct {
+			result1 client.OrderReport
+			result2 error
+		})
+	}
+	fake.verifyInstanceOrderReturnsOnCall[i] = struct {
+		result1 client.OrderReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) VerifyVolumeOrder(location string, size int, iops int, snapshotSpace int) (client.OrderReport, error) {
+	fake.verifyVolumeOrderMutex.Lock()
+	ret, specificReturn := fake.verifyVolumeOrderReturnsOnCall[len(fake.verifyVolumeOrderArgsForCall)]
+	fake.verifyVolumeOrderArgsForCall = append(fake.verifyVolumeOrderArgsForCall, struct {
+		location      string
+		size          int
+		iops          int
+		snapshotSpace int
+	}{location, size, iops, snapshotSpace})
+	fake.recordInvocation("VerifyVolumeOrder", []interface{}{location, size, iops, snapshotSpace})
+	fake.verifyVolumeOrderMutex.Unlock()
+	if fake.VerifyVolumeOrderStub != nil {
+		return fake.VerifyVolumeOrderStub(location, size, iops, snapshotSpace)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.verifyVolumeOrderReturns.result1, fake.verifyVolumeOrderReturns.result2
+}
+
+func (fake *FakeClient) VerifyVolumeOrderCallCount() int {
+	fake.verifyVolumeOrderMutex.RLock()
+	defer fake.verifyVolumeOrderMutex.RUnlock()
+	return len(fake.verifyVolumeOrderArgsForCall)
+}
+
+func (fake *FakeClient) VerifyVolumeOrderArgsForCall(i int) (string, int, int, int) {
+	fake.verifyVolumeOrderMutex.RLock()
+	defer fake.verifyVolumeOrderMutex.RUnlock()
+	return fake.verifyVolumeOrderArgsForCall[i].location, fake.verifyVolumeOrderArgsForCall[i].size, fake.verifyVolumeOrderArgsForCall[i].iops, fake.verifyVolumeOrderArgsForCall[i].snapshotSpace
+}
+
+func (fake *FakeClient) VerifyVolumeOrderReturns(result1 client.OrderReport, result2 error) {
+	fake.VerifyVolumeOrderStub = nil
+	fake.verifyVolumeOrderReturns = struct {
+		result1 client.OrderReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) VerifyVolumeOrderReturnsOnCall(i int, result1 client.OrderReport, result2 error) {
+	fake.VerifyVolumeOrderStub = nil
+	if fake.verifyVolumeOrderReturnsOnCall == nil {
+		fake.verifyVolumeOrderReturnsOnCall = make(map[int]struct {
+			result1 client.OrderReport
+			result2 error
+		})
+	}
+	fake.verifyVolumeOrderReturnsOnCall[i] = struct {
+		result1 client.OrderReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) Timeouts() boslconfig.Timeouts {
+	fake.timeoutsMutex.Lock()
+	ret, specificReturn := fake.timeoutsReturnsOnCall[len(fake.timeoutsArgsForCall)]
+	fake.timeoutsArgsForCall = append(fake.timeoutsArgsForCall, struct{}{})
+	fake.recordInvocation("Timeouts", []interface{}{})
+	fake.timeoutsMutex.Unlock()
+	if fake.TimeoutsStub != nil {
+		return fake.TimeoutsStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.timeoutsReturns.result1
+}
+
+func (fake *FakeClient) TimeoutsCallCount() int {
+	fake.timeoutsMutex.RLock()
+	defer fake.timeoutsMutex.RUnlock()
+	return len(fake.timeoutsArgsForCall)
+}
+
+func (fake *FakeClient) TimeoutsReturns(result1 boslconfig.Timeouts) {
+	fake.TimeoutsStub = nil
+	fake.timeoutsReturns = struct {
+		result1 boslconfig.Timeouts
+	}{result1}
+}
+
+func (fake *FakeClient) TimeoutsReturnsOnCall(i int, result1 boslconfig.Timeouts) {
+	fake.TimeoutsStub = nil
+	if fake.timeoutsReturnsOnCall == nil {
+		fake.timeoutsReturnsOnCall = make(map[int]struct {
+			result1 boslconfig.Timeouts
+		})
+	}
+	fake.timeoutsReturnsOnCall[i] = struct {
+		result1 boslconfig.Timeouts
+	}{result1}
+}
+
+func (fake *FakeClient) AttachPrimaryIpv6Address(id int) error {
+	fake.attachPrimaryIpv6AddressMutex.Lock()
+	ret, specificReturn := fake.attachPrimaryIpv6AddressReturnsOnCall[len(fake.attachPrimaryIpv6AddressArgsForCall)]
+	fake.attachPrimaryIpv6AddressArgsForCall = append(fake.attachPrimaryIpv6AddressArgsForCall, struct {
+		id int
+	}{id})
+	fake.recordInvocation("AttachPrimaryIpv6Address", []interface{}{id})
+	fake.attachPrimaryIpv6AddressMutex.Unlock()
+	if fake.AttachPrimaryIpv6AddressStub != nil {
+		return fake.AttachPrimaryIpv6AddressStub(id)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.attachPrimaryIpv6AddressReturns.result1
+}
+
+func (fake *FakeClient) AttachPrimaryIpv6AddressCallCount() int {
+	fake.attachPrimaryIpv6AddressMutex.RLock()
+	defer fake.attachPrimaryIpv6AddressMutex.RUnlock()
+	return len(fake.attachPrimaryIpv6AddressArgsForCall)
+}
+
+func (fake *FakeClient) AttachPrimaryIpv6AddressArgsForCall(i int) int {
+	fake.attachPrimaryIpv6AddressMutex.RLock()
+	defer fake.attachPrimaryIpv6AddressMutex.RUnlock()
+	return fake.attachPrimaryIpv6AddressArgsForCall[i].id
+}
+
+func (fake *FakeClient) AttachPrimaryIpv6AddressReturns(result1 error) {
+	fake.AttachPrimaryIpv6AddressStub = nil
+	fake.attachPrimaryIpv6AddressReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) AttachPrimaryIpv6AddressReturnsOnCall(i int, result1 error) {
+	fake.AttachPrimaryIpv6AddressStub = nil
+	if fake.attachPrimaryIpv6AddressReturnsOnCall == nil {
+		fake.attachPrimaryIpv6AddressReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.attachPrimaryIpv6AddressReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) SetIpAddressNote(id int, note string) error {
+	fake.setIpAddressNoteMutex.Lock()
+	ret, specificReturn := fake.setIpAddressNoteReturnsOnCall[len(fake.setIpAddressNoteArgsForCall)]
+	fake.setIpAddressNoteArgsForCall = append(fake.setIpAddressNoteArgsForCall, struct {
+		id   int
+		note string
+	}{id, note})
+	fake.recordInvocation("SetIpAddressNote", []interface{}{id, note})
+	fake.setIpAddressNoteMutex.Unlock()
+	if fake.SetIpAddressNoteStub != nil {
+		return fake.SetIpAddressNoteStub(id, note)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.setIpAddressNoteReturns.result1
+}
+
+func (fake *FakeClient) SetIpAddressNoteCallCount() int {
+	fake.setIpAddressNoteMutex.RLock()
+	defer fake.setIpAddressNoteMutex.RUnlock()
+	return len(fake.setIpAddressNoteArgsForCall)
+}
+
+func (fake *FakeClient) SetIpAddressNoteArgsForCall(i int) (int, string) {
+	fake.setIpAddressNoteMutex.RLock()
+	defer fake.setIpAddressNoteMutex.RUnlock()
+	return fake.setIpAddressNoteArgsForCall[i].id, fake.setIpAddressNoteArgsForCall[i].note
+}
+
+func (fake *FakeClient) SetIpAddressNoteReturns(result1 error) {
+	fake.SetIpAddressNoteStub = nil
+	fake.setIpAddressNoteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) SetIpAddressNoteReturnsOnCall(i int, result1 error) {
+	fake.SetIpAddressNoteStub = nil
+	if fake.setIpAddressNoteReturnsOnCall == nil {
+		fake.setIpAddressNoteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setIpAddressNoteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) GetIpAddressesByNote(note string) ([]datatypes.Network_Subnet_IpAddress, error) {
+	fake.getIpAddressesByNoteMutex.Lock()
+	ret, specificReturn := fake.getIpAddressesByNoteReturnsOnCall[len(fake.getIpAddressesByNoteArgsForCall)]
+	fake.getIpAddressesByNoteArgsForCall = append(fake.getIpAddressesByNoteArgsForCall, struct {
+		note string
+	}{note})
+	fake.recordInvocation("GetIpAddressesByNote", []interface{}{note})
+	fake.getIpAddressesByNoteMutex.Unlock()
+	if fake.GetIpAddressesByNoteStub != nil {
+		return fake.GetIpAddressesByNoteStub(note)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getIpAddressesByNoteReturns.result1, fake.getIpAddressesByNoteReturns.result2
+}
+
+func (fake *FakeClient) GetIpAddressesByNoteCallCount() int {
+	fake.getIpAddressesByNoteMutex.RLock()
+	defer fake.getIpAddressesByNoteMutex.RUnlock()
+	return len(fake.getIpAddressesByNoteArgsForCall)
+}
+
+func (fake *FakeClient) GetIpAddressesByNoteArgsForCall(i int) string {
+	fake.getIpAddressesByNoteMutex.RLock()
+	defer fake.getIpAddressesByNoteMutex.RUnlock()
+	return fake.getIpAddressesByNoteArgsForCall[i].note
+}
+
+func (fake *FakeClient) GetIpAddressesByNoteReturns(result1 []datatypes.Network_Subnet_IpAddress, result2 error) {
+	fake.GetIpAddressesByNoteStub = nil
+	fake.getIpAddressesByNoteReturns = struct {
+		result1 []datatypes.Network_Subnet_IpAddress
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetIpAddressesByNoteReturnsOnCall(i int, result1 []datatypes.Network_Subnet_IpAddress, result2 error) {
+	fake.GetIpAddressesByNoteStub = nil
+	if fake.getIpAddressesByNoteReturnsOnCall == nil {
+		fake.getIpAddressesByNoteReturnsOnCall = make(map[int]struct {
+			result1 []datatypes.Network_Subnet_IpAddress
+			result2 error
+		})
+	}
+	fake.getIpAddressesByNoteReturnsOnCall[i] = struct {
+		result1 []datatypes.Network_Subnet_IpAddress
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetGlobalIpRecords(ip string, destinationIp string) ([]datatypes.Network_Subnet_IpAddress_Global, error) {
+	fake.getGlobalIpRecordsMutex.Lock()
+	ret, specificReturn := fake.getGlobalIpRecordsReturnsOnCall[len(fake.getGlobalIpRecordsArgsForCall)]
+	fake.getGlobalIpRecordsArgsForCall = append(fake.getGlobalIpRecordsArgsForCall, struct {
+		ip            string
+		destinationIp string
+	}{ip, destinationIp})
+	fake.recordInvocation("GetGlobalIpRecords", []interface{}{ip, destinationIp})
+	fake.getGlobalIpRecordsMutex.Unlock()
+	if fake.GetGlobalIpRecordsStub != nil {
+		return fake.GetGlobalIpRecordsStub(ip, destinationIp)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getGlobalIpRecordsReturns.result1, fake.getGlobalIpRecordsReturns.result2
+}
+
+func (fake *FakeClient) GetGlobalIpRecordsCallCount() int {
+	fake.getGlobalIpRecordsMutex.RLock()
+	defer fake.getGlobalIpRecordsMutex.RUnlock()
+	return len(fake.getGlobalIpRecordsArgsForCall)
+}
+
+func (fake *FakeClient) GetGlobalIpRecordsArgsForCall(i int) (string, string) {
+	fake.getGlobalIpRecordsMutex.RLock()
+	defer fake.getGlobalIpRecordsMutex.RUnlock()
+	return fake.getGlobalIpRecordsArgsForCall[i].ip, fake.getGlobalIpRecordsArgsForCall[i].destinationIp
+}
+
+func (fake *FakeClient) GetGlobalIpRecordsReturns(result1 []datatypes.Network_Subnet_IpAddress_Global, result2 error) {
+	fake.GetGlobalIpRecordsStub = nil
+	fake.getGlobalIpRecordsReturns = struct {
+		result1 []datatypes.Network_Subnet_IpAddress_Global
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetGlobalIpRecordsReturnsOnCall(i int, result1 []datatypes.Network_Subnet_IpAddress_Global, result2 error) {
+	fake.GetGlobalIpRecordsStub = nil
+	if fake.getGlobalIpRecordsReturnsOnCall == nil {
+		fake.getGlobalIpRecordsReturnsOnCall = make(map[int]struct {
+			result1 []datatypes.Network_Subnet_IpAddress_Global
+			result2 error
+		})
+	}
+	fake.getGlobalIpRecordsReturnsOnCall[i] = struct {
+		result1 []datatypes.Network_Subnet_IpAddress_Global
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) RouteGlobalIp(id int, destinationIp string) error {
+	fake.routeGlobalIpMutex.Lock()
+	ret, specificReturn := fake.routeGlobalIpReturnsOnCall[len(fake.routeGlobalIpArgsForCall)]
+	fake.routeGlobalIpArgsForCall = append(fake.routeGlobalIpArgsForCall, struct {
+		id            int
+		destinationIp string
+	}{id, destinationIp})
+	fake.recordInvocation("RouteGlobalIp", []interface{}{id, destinationIp})
+	fake.routeGlobalIpMutex.Unlock()
+	if fake.RouteGlobalIpStub != nil {
+		return fake.RouteGlobalIpStub(id, destinationIp)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.routeGlobalIpReturns.result1
+}
+
+func (fake *FakeClient) RouteGlobalIpCallCount() int {
+	fake.routeGlobalIpMutex.RLock()
+	defer fake.routeGlobalIpMutex.RUnlock()
+	return len(fake.routeGlobalIpArgsForCall)
+}
+
+func (fake *FakeClient) RouteGlobalIpArgsForCall(i int) (int, string) {
+	fake.routeGlobalIpMutex.RLock()
+	defer fake.routeGlobalIpMutex.RUnlock()
+	return fake.routeGlobalIpArgsForCall[i].id, fake.routeGlobalIpArgsForCall[i].destinationIp
+}
+
+func (fake *FakeClient) RouteGlobalIpReturns(result1 error) {
+	fake.RouteGlobalIpStub = nil
+	fake.routeGlobalIpReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) RouteGlobalIpReturnsOnCall(i int, result1 error) {
+	fake.RouteGlobalIpStub = nil
+	if fake.routeGlobalIpReturnsOnCall == nil {
+		fake.routeGlobalIpReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.routeGlobalIpReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) UnrouteGlobalIp(id int) error {
+	fake.unrouteGlobalIpMutex.Lock()
+	ret, specificReturn := fake.unrouteGlobalIpReturnsOnCall[len(fake.unrouteGlobalIpArgsForCall)]
+	fake.unrouteGlobalIpArgsForCall = append(fake.unrouteGlobalIpArgsForCall, struct {
+		id int
+	}{id})
+	fake.recordInvocation("UnrouteGlobalIp", []interface{}{id})
+	fake.unrouteGlobalIpMutex.Unlock()
+	if fake.UnrouteGlobalIpStub != nil {
+		return fake.UnrouteGlobalIpStub(id)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.unrouteGlobalIpReturns.result1
+}
+
+func (fake *FakeClient) UnrouteGlobalIpCallCount() int {
+	fake.unrouteGlobalIpMutex.RLock()
+	defer fake.unrouteGlobalIpMutex.RUnlock()
+	return len(fake.unrouteGlobalIpArgsForCall)
+}
+
+func (fake *FakeClient) UnrouteGlobalIpArgsForCall(i int) int {
+	fake.unrouteGlobalIpMutex.RLock()
+	defer fake.unrouteGlobalIpMutex.RUnlock()
+	return fake.unrouteGlobalIpArgsForCall[i].id
+}
+
+func (fake *FakeClient) UnrouteGlobalIpReturns(result1 error) {
+	fake.UnrouteGlobalIpStub = nil
+	fake.unrouteGlobalIpReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) UnrouteGlobalIpReturnsOnCall(i int, result1 error) {
+	fake.UnrouteGlobalIpStub = nil
+	if fake.unrouteGlobalIpReturnsOnCall == nil {
+		fake.unrouteGlobalIpReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.unrouteGlobalIpReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) FindVirtualServerItemPrice(categoryCode string) (*datatypes.Product_Item_Price, bool, error) {
+	fake.findVirtualServerItemPriceMutex.Lock()
+	ret, specificReturn := fake.findVirtualServerItemPriceReturnsOnCall[len(fake.findVirtualServerItemPriceArgsForCall)]
+	fake.findVirtualServerItemPriceArgsForCall = append(fake.findVirtualServerItemPriceArgsForCall, struct {
+		categoryCode string
+	}{categoryCode})
+	fake.recordInvocation("FindVirtualServerItemPrice", []interface{}{categoryCode})
+	fake.findVirtualServerItemPriceMutex.Unlock()
+	if fake.FindVirtualServerItemPriceStub != nil {
+		return fake.FindVirtualServerItemPriceStub(categoryCode)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fake.findVirtualServerItemPriceReturns.result1, fake.findVirtualServerItemPriceReturns.result2, fake.findVirtualServerItemPriceReturns.result3
+}
+
+func (fake *FakeClient) FindVirtualServerItemPriceCallCount() int {
+	fake.findVirtualServerItemPriceMutex.RLock()
+	defer fake.findVirtualServerItemPriceMutex.RUnlock()
+	return len(fake.findVirtualServerItemPriceArgsForCall)
+}
+
+func (fake *FakeClient) FindVirtualServerItemPriceArgsForCall(i int) string {
+	fake.findVirtualServerItemPriceMutex.RLock()
+	defer fake.findVirtualServerItemPriceMutex.RUnlock()
+	return fake.findVirtualServerItemPriceArgsForCall[i].categoryCode
+}
+
+func (fake *FakeClient) FindVirtualServerItemPriceReturns(result1 *datatypes.Product_Item_Price, result2 bool, result3 error) {
+	fake.FindVirtualServerItemPriceStub = nil
+	fake.findVirtualServerItemPriceReturns = struct {
+		result1 *datatypes.Product_Item_Price
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeClient) FindVirtualServerItemPriceReturnsOnCall(i int, result1 *datatypes.Product_Item_Price, result2 bool, result3 error) {
+	fake.FindVirtualServerItemPriceStub = nil
+	if fake.findVirtualServerItemPriceReturnsOnCall == nil {
+		fake.findVirtualServerItemPriceReturnsOnCall = make(map[int]struct {
+			result1 *datatypes.Product_Item_Price
+			result2 bool
+			result3 error
+		})
+	}
+	fake.findVirtualServerItemPriceReturnsOnCall[i] = struct {
+		result1 *datatypes.Product_Item_Price
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeClient) Clock() clock.Clock {
+	fake.clockMutex.Lock()
+	ret, specificReturn := fake.clockReturnsOnCall[len(fake.clockArgsForCall)]
+	fake.clockArgsForCall = append(fake.clockArgsForCall, struct {
+	}{})
+	fake.recordInvocation("Clock", []interface{}{})
+	fake.clockMutex.Unlock()
+	if fake.ClockStub != nil {
+		return fake.ClockStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.clockReturns.result1
+}
+
+func (fake *FakeClient) ClockCallCount() int {
+	fake.clockMutex.RLock()
+	defer fake.clockMutex.RUnlock()
+	return len(fake.clockArgsForCall)
+}
+
+func (fake *FakeClient) ClockReturns(result1 clock.Clock) {
+	fake.ClockStub = nil
+	fake.clockReturns = struct {
+		result1 clock.Clock
+	}{result1}
+}
+
+func (fake *FakeClient) ClockReturnsOnCall(i int, result1 clock.Clock) {
+	fake.ClockStub = nil
+	if fake.clockReturnsOnCall == nil {
+		fake.clockReturnsOnCall = make(map[int]struct {
+			result1 clock.Clock
+		})
+	}
+	fake.clockReturnsOnCall[i] = struct {
+		result1 clock.Clock
+	}{result1}
+}
+
+func (fake *FakeClient) GetIpAddressNote(id int) (string, error) {
+	fake.getIpAddressNoteMutex.Lock()
+	ret, specificReturn := fake.getIpAddressNoteReturnsOnCall[len(fake.getIpAddressNoteArgsForCall)]
+	fake.getIpAddressNoteArgsForCall = append(fake.getIpAddressNoteArgsForCall, struct {
+		id int
+	}{id})
+	fake.recordInvocation("GetIpAddressNote", []interface{}{id})
+	fake.getIpAddressNoteMutex.Unlock()
+	if fake.GetIpAddressNoteStub != nil {
+		return fake.GetIpAddressNoteStub(id)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.getIpAddressNoteReturns.result1, fake.getIpAddressNoteReturns.result2
+}
+
+func (fake *FakeClient) GetIpAddressNoteCallCount() int {
+	fake.getIpAddressNoteMutex.RLock()
+	defer fake.getIpAddressNoteMutex.RUnlock()
+	return len(fake.getIpAddressNoteArgsForCall)
+}
+
+func (fake *FakeClient) GetIpAddressNoteArgsForCall(i int) int {
+	fake.getIpAddressNoteMutex.RLock()
+	defer fake.getIpAddressNoteMutex.RUnlock()
+	return fake.getIpAddressNoteArgsForCall[i].id
+}
+
+func (fake *FakeClient) GetIpAddressNoteReturns(result1 string, result2 error) {
+	fake.GetIpAddressNoteStub = nil
+	fake.getIpAddressNoteReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetIpAddressNoteReturnsOnCall(i int, result1 string, result2 error) {
+	fake.GetIpAddressNoteStub = nil
+	if fake.getIpAddressNoteReturnsOnCall == nil {
+		fake.getIpAddressNoteReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getIpAddressNoteReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -3175,6 +4404,46 @@ func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	defer fake.deleteSwiftLargeObjectMutex.RUnlock()
 	fake.createImageFromExternalSourceMutex.RLock()
 	defer fake.createImageFromExternalSourceMutex.RUnlock()
+	fake.setUserDataWithIDMutex.RLock()
+	defer fake.setUserDataWithIDMutex.RUnlock()
+	fake.getUserDataWithIDMutex.RLock()
+	defer fake.getUserDataWithIDMutex.RUnlock()
+	fake.getVirtualServerPackageItemsMutex.RLock()
+	defer fake.getVirtualServerPackageItemsMutex.RUnlock()
+	fake.getVirtualServerPackagePresetsMutex.RLock()
+	defer fake.getVirtualServerPackagePresetsMutex.RUnlock()
+	fake.waitOrderCompletedMutex.RLock()
+	defer fake.waitOrderCompletedMutex.RUnlock()
+	fake.upgradeBlockVolumeMutex.RLock()
+	defer fake.upgradeBlockVolumeMutex.RUnlock()
+	fake.deleteImageMutex.RLock()
+	defer fake.deleteImageMutex.RUnlock()
+	fake.getInstancesByImageMutex.RLock()
+	defer fake.getInstancesByImageMutex.RUnlock()
+	fake.verifyInstanceOrderMutex.RLock()
+	defer fake.verifyInstanceOrderMutex.RUnlock()
+	fake.verifyVolumeOrderMutex.RLock()
+	defer fake.verifyVolumeOrderMutex.RUnlock()
+	fake.timeoutsMutex.RLock()
+	defer fake.timeoutsMutex.RUnlock()
+	fake.attachPrimaryIpv6AddressMutex.RLock()
+	defer fake.attachPrimaryIpv6AddressMutex.RUnlock()
+	fake.setIpAddressNoteMutex.RLock()
+	defer fake.setIpAddressNoteMutex.RUnlock()
+	fake.getIpAddressesByNoteMutex.RLock()
+	defer fake.getIpAddressesByNoteMutex.RUnlock()
+	fake.getGlobalIpRecordsMutex.RLock()
+	defer fake.getGlobalIpRecordsMutex.RUnlock()
+	fake.routeGlobalIpMutex.RLock()
+	defer fake.routeGlobalIpMutex.RUnlock()
+	fake.unrouteGlobalIpMutex.RLock()
+	defer fake.unrouteGlobalIpMutex.RUnlock()
+	fake.findVirtualServerItemPriceMutex.RLock()
+	defer fake.findVirtualServerItemPriceMutex.RUnlock()
+	fake.clockMutex.RLock()
+	defer fake.clockMutex.RUnlock()
+	fake.getIpAddressNoteMutex.RLock()
+	defer fake.getIpAddressNoteMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -55,7 +55,7 @@ var _ = Describe("ImageHandler", func() {
 		logger = cpiLog.NewLogger(boshlogger.LevelDebug, strconv.Itoa(nanos))
 		multiLogger = api.MultiLogger{Logger: logger, LogBuff: &errOutLog}
 		sess = test_helpers.NewFakeSoftlayerSession(transportHandler)
-		cli = slClient.NewSoftLayerClientManager(sess, vps, swiftClient, logger)
+		cli = slClient.NewSoftLayerClientManager(sess, vps, swiftClient, logger).WithClock(test_helpers.NewFakeClock(time.Now()))
 
 		imageID = 1335057
 	})
@@ -133,4 +133,80 @@ var _ = Describe("ImageHandler", func() {
 		})
 	})
 
+	Describe("DeleteImage", func() {
+		It("delete image successfully", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Virtual_Guest_Block_Device_Template_Group_deleteObject.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			err := cli.DeleteImage(imageID)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("return nil when the image does not exist", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Virtual_Guest_Block_Device_Template_Group_getObject_NotFound.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			err := cli.DeleteImage(imageID)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("return an error when ImageService deleteObject call return an error", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Virtual_Guest_Block_Device_Template_Group_getObject_InternalError.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			err := cli.DeleteImage(imageID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+		})
+	})
+
+	Describe("GetInstancesByImage", func() {
+		It("get instances successfully", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Account_getVirtualGuests.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			instances, err := cli.GetInstancesByImage("07beadaa-1e11-476e-a188-3f7795feb9fb")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instances).NotTo(BeEmpty())
+		})
+
+		It("return an error when AccountService getVirtualGuests call return an error", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Account_getVirtualGuests_InternalError.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err := cli.GetInstancesByImage("07beadaa-1e11-476e-a188-3f7795feb9fb")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 })
@@ -5,6 +5,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -18,9 +19,12 @@ import (
 	"github.com/softlayer/softlayer-go/sl"
 
 	"bosh-softlayer-cpi/api"
+	"bosh-softlayer-cpi/audit"
 	cpiLog "bosh-softlayer-cpi/logger"
+	"bosh-softlayer-cpi/metrics"
 	"bosh-softlayer-cpi/registry"
 	slClient "bosh-softlayer-cpi/softlayer/client"
+	boslconfig "bosh-softlayer-cpi/softlayer/config"
 	vpsVm "bosh-softlayer-cpi/softlayer/vps_service/client/vm"
 	"bosh-softlayer-cpi/test_helpers"
 )
@@ -70,7 +74,7 @@ var _ = Describe("InstanceHandler", func() {
 		logger = cpiLog.NewLogger(boshlogger.LevelDebug, strconv.Itoa(nanos))
 		multiLogger = api.MultiLogger{Logger: logger, LogBuff: &errOutLog}
 		sess = test_helpers.NewFakeSoftlayerSession(transportHandler)
-		cli = slClient.NewSoftLayerClientManager(sess, vps, swiftClient, logger)
+		cli = slClient.NewSoftLayerClientManager(sess, vps, swiftClient, logger).WithClock(test_helpers.NewFakeClock(time.Now()))
 
 		vgID = 25804753
 		vlanID = 1262125
@@ -165,6 +169,24 @@ var _ = Describe("InstanceHandler", func() {
 				Expect(success).To(Equal(true))
 				Expect(*vgs.Id).To(Equal(vgID))
 			})
+
+			It("get the operating system reference code of an instance provisioned from an image template", func() {
+				respParas = []map[string]interface{}{
+					{
+						"filename":   "SoftLayer_Virtual_Guest_getObject_ImageTemplate.json",
+						"statusCode": http.StatusOK,
+					},
+				}
+				err = test_helpers.SpecifyServerResps(respParas, server)
+				Expect(err).NotTo(HaveOccurred())
+
+				vgs, success, err := cli.GetInstance(vgID, slClient.INSTANCE_NETWORK_COMPONENTS_MASK)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(success).To(Equal(true))
+				Expect(*vgs.BlockDeviceTemplateGroup.GlobalIdentifier).To(Equal("8071601b-5ee1-483e-a9e8-6e5582dcb9f7"))
+				Expect(*vgs.OperatingSystemReferenceCode).To(Equal("CENTOS_7_64"))
+				Expect(*vgs.PrimaryBackendNetworkComponent.PrimaryIpAddress).To(Equal("10.127.94.175"))
+			})
 		})
 
 		Context("when VirtualGuestService getObject call return an error", func() {
@@ -342,6 +364,197 @@ var _ = Describe("InstanceHandler", func() {
 		})
 	})
 
+	Describe("SetIpAddressNote", func() {
+		It("edits the note of the ip address successfully", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Network_Subnet_IpAddress_editObject.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			err := cli.SetIpAddressNote(10776597, "fake-note")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("return an error when NetworkIpService editObject call return an error", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Network_Subnet_IpAddress_editObject_InternalError.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			err := cli.SetIpAddressNote(10776597, "fake-note")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Editing note of ip address '10776597'"))
+		})
+	})
+
+	Describe("GetIpAddressNote", func() {
+		It("gets the note of the ip address successfully", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Network_Subnet_IpAddress_getObject.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			note, err := cli.GetIpAddressNote(10776597)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(note).To(Equal("fake-note"))
+		})
+
+		It("return an error when NetworkIpService getObject call return an error", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Network_Subnet_IpAddress_getObject_InternalError.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err := cli.GetIpAddressNote(10776597)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Getting note of ip address '10776597'"))
+		})
+	})
+
+	Describe("GetIpAddressesByNote", func() {
+		It("gets the ip addresses with the note successfully", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Account_getIpAddresses.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			ipAddresses, err := cli.GetIpAddressesByNote("bosh-softlayer-cpi: assigned to virtual guest 12345678")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ipAddresses).To(HaveLen(1))
+			Expect(*ipAddresses[0].IpAddress).To(Equal("10.40.207.173"))
+		})
+
+		It("return an error when AccountService getIpAddresses call return an error", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Account_getIpAddresses_InternalError.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err := cli.GetIpAddressesByNote("fake-note")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Getting ip addresses with note"))
+		})
+	})
+
+	Describe("GetGlobalIpRecords", func() {
+		It("gets the global ip records successfully", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Account_getGlobalIpRecords.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			records, err := cli.GetGlobalIpRecords("169.55.61.215", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(2))
+			Expect(*records[1].Id).To(Equal(52703))
+		})
+
+		It("return an error when AccountService getGlobalIpRecords call return an error", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Account_getGlobalIpRecords_InternalError.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err := cli.GetGlobalIpRecords("169.55.61.215", "")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Getting global ip records"))
+		})
+	})
+
+	Describe("RouteGlobalIp", func() {
+		It("routes the global ip successfully", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Network_Subnet_IpAddress_Global_route.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			err := cli.RouteGlobalIp(52703, "169.50.1.10")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("return an error when GlobalIpService route call return an error", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Network_Subnet_IpAddress_Global_route_InternalError.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			err := cli.RouteGlobalIp(52703, "169.50.1.10")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Routing global ip '52703' to '169.50.1.10'"))
+		})
+	})
+
+	Describe("UnrouteGlobalIp", func() {
+		It("unroutes the global ip successfully", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Network_Subnet_IpAddress_Global_unroute.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			err := cli.UnrouteGlobalIp(52703)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("return an error when GlobalIpService unroute call return an error", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Network_Subnet_IpAddress_Global_unroute_InternalError.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			err := cli.UnrouteGlobalIp(52703)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Unrouting global ip '52703'"))
+		})
+	})
+
 	Describe("GetInstanceByPrimaryBackendIpAddress", func() {
 		Context("when AccountService getVirtualGuests call successfully", func() {
 			It("get instance by primary backend ip successfully", func() {
@@ -591,6 +804,27 @@ var _ = Describe("InstanceHandler", func() {
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("Power on virtual guest with id %d Time Out!", vgID)))
 			})
+
+			It("polls every instance_poll_interval until it times out, sleeping no later than the deadline", func() {
+				respParas = []map[string]interface{}{}
+				for i := 0; i < 4; i++ {
+					respParas = append(respParas, map[string]interface{}{
+						"filename":   "SoftLayer_Virtual_Guest_getObject_HasActiveTxn.json",
+						"statusCode": http.StatusOK,
+					})
+				}
+				err = test_helpers.SpecifyServerResps(respParas, server)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeClock := test_helpers.NewFakeClock(time.Now())
+				cli = cli.WithClock(fakeClock)
+
+				err := cli.WaitInstanceUntilReady(vgID, fakeClock.Now().Add(25*time.Second))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("Power on virtual guest with id %d Time Out!", vgID)))
+				Expect(fakeClock.SleptTimes()).To(Equal([]time.Duration{10 * time.Second, 10 * time.Second, 5 * time.Second}))
+				Expect(server.ReceivedRequests()).To(HaveLen(4))
+			})
 		})
 
 		Context("when VirtualGuestService getObject call return error", func() {
@@ -866,6 +1100,186 @@ var _ = Describe("InstanceHandler", func() {
 		})
 	})
 
+	Describe("WaitOrderCompleted", func() {
+		var fakeClock *test_helpers.FakeClock
+
+		BeforeEach(func() {
+			fakeClock = test_helpers.NewFakeClock(time.Now())
+			cli = cli.WithClock(fakeClock).WithTimeouts(boslconfig.Timeouts{
+				OrderPollInterval: boslconfig.NewDuration(2 * time.Second),
+			})
+		})
+
+		It("returns once the order is completed", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Billing_Order_getObject_Pending.json",
+					"statusCode": http.StatusOK,
+				},
+				{
+					"filename":   "SoftLayer_Billing_Order_getObject.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			err := cli.WaitOrderCompleted(16885, fakeClock.Now().Add(time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeClock.SleptTimes()).To(Equal([]time.Duration{2 * time.Second}))
+		})
+
+		It("polls every order_poll_interval until it times out, sleeping no later than the deadline", func() {
+			respParas = []map[string]interface{}{}
+			for i := 0; i < 3; i++ {
+				respParas = append(respParas, map[string]interface{}{
+					"filename":   "SoftLayer_Billing_Order_getObject_Pending.json",
+					"statusCode": http.StatusOK,
+				})
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			err := cli.WaitOrderCompleted(16885, fakeClock.Now().Add(3*time.Second))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Waiting order with id of '16885' has been completed"))
+			Expect(fakeClock.SleptTimes()).To(Equal([]time.Duration{2 * time.Second, 1 * time.Second}))
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+		})
+
+		It("records how long it waited and that it timed out", func() {
+			respParas = []map[string]interface{}{}
+			for i := 0; i < 3; i++ {
+				respParas = append(respParas, map[string]interface{}{
+					"filename":   "SoftLayer_Billing_Order_getObject_Pending.json",
+					"statusCode": http.StatusOK,
+				})
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := metrics.NewRecorder()
+			cli = cli.WithMetrics(recorder)
+
+			err := cli.WaitOrderCompleted(16885, fakeClock.Now().Add(3*time.Second))
+			Expect(err).To(HaveOccurred())
+
+			waits := recorder.Snapshot().Waits
+			Expect(waits).To(HaveLen(1))
+			Expect(waits[0].Name).To(Equal("WaitOrderCompleted"))
+			Expect(waits[0].Timings.Total()).To(Equal(3 * time.Second))
+			Expect(waits[0].Failures).To(Equal(1))
+		})
+
+		It("does not wait when there is no order", func() {
+			err := cli.WaitOrderCompleted(0, fakeClock.Now())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+		})
+	})
+
+	Describe("VerifyInstanceOrder", func() {
+		It("verifies the order generated from the template", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Virtual_Guest_generateOrderTemplate.json",
+					"statusCode": http.StatusOK,
+				},
+				{
+					"filename":   "SoftLayer_Product_Order_verifyOrder.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			report, err := cli.VerifyInstanceOrder(vgTemplate)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Verified).To(BeTrue())
+			Expect(report.Errors).To(BeEmpty())
+			Expect(report.MonthlyRecurringFee).To(Equal(float64(8)))
+			Expect(report.Prices[0].Id).To(Equal(29227))
+			Expect(report.Prices[0].Description).To(Equal("4 Portable Public IP Addresses"))
+		})
+
+		It("reports the order as rejected when SoftLayer refuses it", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Virtual_Guest_generateOrderTemplate.json",
+					"statusCode": http.StatusOK,
+				},
+				{
+					"filename":   "SoftLayer_Product_Order_verifyOrder_InvalidLocation.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			report, err := cli.VerifyInstanceOrder(vgTemplate)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Verified).To(BeFalse())
+			Expect(report.Errors).To(ConsistOf(ContainSubstring("The location provided for this order is invalid")))
+		})
+
+		It("returns an error when verifyOrder call fails", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Virtual_Guest_generateOrderTemplate.json",
+					"statusCode": http.StatusOK,
+				},
+				{
+					"filename":   "SoftLayer_Product_Order_verifyOrder_InternalError.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err := cli.VerifyInstanceOrder(vgTemplate)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Verifying order"))
+		})
+
+		It("returns an error when the credentials are refused", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Virtual_Guest_generateOrderTemplate.json",
+					"statusCode": http.StatusOK,
+				},
+				{
+					"filename":   "SoftLayer_Product_Order_verifyOrder_InvalidLegacyToken.json",
+					"statusCode": http.StatusUnauthorized,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err := cli.VerifyInstanceOrder(vgTemplate)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid API token"))
+		})
+
+		It("returns an error when the call is rate limited", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Virtual_Guest_generateOrderTemplate.json",
+					"statusCode": http.StatusOK,
+				},
+				{
+					"filename":   "SoftLayer_Product_Order_verifyOrder_RateLimitExceeded.json",
+					"statusCode": http.StatusTooManyRequests,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err := cli.VerifyInstanceOrder(vgTemplate)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Rate limit exceeded"))
+		})
+	})
+
 	Describe("CreateInstance", func() {
 		Context("when VirtualGuestService createObject call successfully", func() {
 			It("create instance successfully", func() {
@@ -890,9 +1304,44 @@ var _ = Describe("InstanceHandler", func() {
 				err = test_helpers.SpecifyServerResps(respParas, server)
 				Expect(err).NotTo(HaveOccurred())
 
+				fakeClock := test_helpers.NewFakeClock(time.Now())
+				cli = cli.WithClock(fakeClock)
+
+				vgs, err := cli.CreateInstance(vgTemplate, userData)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*vgs.FullyQualifiedDomainName).To(Equal(*(*vgTemplate).FullyQualifiedDomainName))
+				Expect(fakeClock.SleptTimes()).To(Equal([]time.Duration{90 * time.Second}))
+			})
+
+			It("creates the instance without delay when the delay is turned off", func() {
+				respParas = []map[string]interface{}{
+					{
+						"filename":   "SoftLayer_Virtual_Guest_createObject.json",
+						"statusCode": http.StatusOK,
+					},
+					{
+						"filename":   "SoftLayer_Virtual_Guest_setUserMetadata.json",
+						"statusCode": http.StatusOK,
+					},
+					{
+						"filename":   "SoftLayer_Virtual_Guest_getObject_HasNoneActiveTxn.json",
+						"statusCode": http.StatusOK,
+					},
+					{
+						"filename":   "SoftLayer_Virtual_Guest_getObject_HasNoneActiveTxn.json",
+						"statusCode": http.StatusOK,
+					},
+				}
+				err = test_helpers.SpecifyServerResps(respParas, server)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeClock := test_helpers.NewFakeClock(time.Now())
+				cli = cli.WithClock(fakeClock).WithTimeouts(boslconfig.Timeouts{CreateInstanceDelay: &boslconfig.Duration{}})
+
 				vgs, err := cli.CreateInstance(vgTemplate, userData)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(*vgs.FullyQualifiedDomainName).To(Equal(*(*vgTemplate).FullyQualifiedDomainName))
+				Expect(fakeClock.SleptTimes()).To(BeEmpty())
 			})
 		})
 
@@ -1488,6 +1937,45 @@ var _ = Describe("InstanceHandler", func() {
 				Expect(err).NotTo(HaveOccurred())
 			})
 
+			It("records the cancellation with its billing item and BOSH metadata in the audit trail", func() {
+				respParas = []map[string]interface{}{
+					{
+						"filename":   "SoftLayer_Virtual_Guest_getObject_HasNoneActiveTxn.json",
+						"statusCode": http.StatusOK,
+					},
+					{
+						"filename":   "SoftLayer_Virtual_Guest_getObject_BillingAudit.json",
+						"statusCode": http.StatusOK,
+					},
+					{
+						"filename":   "SoftLayer_Virtual_Guest_deleteObject.json",
+						"statusCode": http.StatusOK,
+					},
+				}
+				err = test_helpers.SpecifyServerResps(respParas, server)
+				Expect(err).NotTo(HaveOccurred())
+
+				var trailOut bytes.Buffer
+				logger.ChangeRequestFields(cpiLog.RequestFields{Method: "delete_vm"})
+				err := cli.WithAudit(audit.NewTrail(&trailOut)).CancelInstance(vgID)
+				Expect(err).NotTo(HaveOccurred())
+
+				var event audit.Event
+				Expect(json.Unmarshal(trailOut.Bytes(), &event)).To(Succeed())
+				Expect(event.Event).To(Equal(audit.EventCancel))
+				Expect(event.Resource).To(Equal(audit.ResourceVirtualGuest))
+				Expect(event.ResourceID).To(Equal(vgID))
+				Expect(event.OrderID).To(Equal(11764036))
+				Expect(event.BillingItemID).To(Equal(140952230))
+				Expect(event.ItemPrices).To(Equal([]audit.ItemPrice{
+					{Category: "guest_core", Description: "2 x 2.0 GHz Cores", HourlyFee: 0.06, MonthlyFee: 40},
+					{Category: "ram", Description: "4 GB", HourlyFee: 0.05, MonthlyFee: 33},
+				}))
+				Expect(event.Method).To(Equal("delete_vm"))
+				Expect(event.RequestID).To(Equal(logger.GetSerialTagPrefix()))
+				Expect(event.Metadata).To(Equal(audit.Metadata{Deployment: "fake-deployment", Job: "fake-job", Index: "0"}))
+			})
+
 			It("Cancel instance successfully when instance stays 'RECLAIM_WAIT' transaction", func() {
 				respParas = []map[string]interface{}{
 					{
@@ -1521,6 +2009,33 @@ var _ = Describe("InstanceHandler", func() {
 			})
 		})
 
+		Context("when the instance keeps an active transaction", func() {
+			It("Return error once the configured cancel_instance timeout is over", func() {
+				respParas = []map[string]interface{}{
+					{
+						"filename":   "SoftLayer_Virtual_Guest_getObject_HasActiveTxn.json",
+						"statusCode": http.StatusOK,
+					},
+					{
+						"filename":   "SoftLayer_Virtual_Guest_getObject_HasActiveTxn.json",
+						"statusCode": http.StatusOK,
+					},
+				}
+				err = test_helpers.SpecifyServerResps(respParas, server)
+				Expect(err).NotTo(HaveOccurred())
+
+				cli = cli.WithTimeouts(boslconfig.Timeouts{
+					CancelInstance:          boslconfig.NewDuration(10 * time.Millisecond),
+					TransactionPollInterval: boslconfig.NewDuration(50 * time.Millisecond),
+				})
+
+				err := cli.CancelInstance(vgID)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("has none active transaction time out"))
+				Expect(server.ReceivedRequests()).To(HaveLen(2))
+			})
+		})
+
 		Context("when VirtualGuestService getObject call return error", func() {
 			It("Return error", func() {
 				respParas = []map[string]interface{}{
@@ -1692,9 +2207,13 @@ var _ = Describe("InstanceHandler", func() {
 				err = test_helpers.SpecifyServerResps(respParas, server)
 				Expect(err).NotTo(HaveOccurred())
 
+				fakeClock := test_helpers.NewFakeClock(time.Now())
+				cli = cli.WithClock(fakeClock)
+
 				_, err := cli.UpgradeInstance(vgID, 2, 0, 0, true, false, 0)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("Unable to find order id"))
+				Expect(fakeClock.SleptTimes()).To(Equal([]time.Duration{5 * time.Second, 5 * time.Second}))
 			})
 
 			It("return an error if privateCPU and dedicatedHost are both true", func() {
@@ -2215,6 +2734,168 @@ var _ = Describe("InstanceHandler", func() {
 		})
 	})
 
+	Describe("AttachPrimaryIpv6Address", func() {
+		It("Attach successfully", func() {
+			respParas = []map[string]interface{}{
+				// WaitInstanceHasNoneActiveTransaction
+				{
+					"filename":   "SoftLayer_Virtual_Guest_getObject_HasNoneActiveTxn.json",
+					"statusCode": http.StatusOK,
+				},
+				// getUpgradeItemPriceByCategory
+				{
+					"filename":   "SoftLayer_Virtual_Guest_getUpgradeItemPrices.json",
+					"statusCode": http.StatusOK,
+				},
+				{
+					"filename":   "SoftLayer_Virtual_Guest_getObject_localDisk.json",
+					"statusCode": http.StatusOK,
+				},
+				// getVirtualServerPackageID
+				{
+					"filename":   "SoftLayer_Product_Package_getAllObjects.json",
+					"statusCode": http.StatusOK,
+				},
+				{
+					"filename":   "SoftLayer_Product_Order_placeOrder.json",
+					"statusCode": http.StatusOK,
+				},
+				// WaitOrderCompleted
+				{
+					"filename":   "SoftLayer_Billing_Order_getObject.json",
+					"statusCode": http.StatusOK,
+				},
+				// WaitInstanceUntilReady
+				{
+					"filename":   "SoftLayer_Virtual_Guest_getObject_HasNoneActiveTxn.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			err := cli.AttachPrimaryIpv6Address(vgID)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Return error when call WaitInstanceHasNoneActiveTransaction return an error", func() {
+			respParas = []map[string]interface{}{
+				// WaitInstanceHasNoneActiveTransaction
+				{
+					"filename":   "SoftLayer_Virtual_Guest_getObject_InternalError.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			err := cli.AttachPrimaryIpv6Address(vgID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("before ordering IPv6 address"))
+		})
+
+		It("Return error when call GetUpgradeItemPrices return an error", func() {
+			respParas = []map[string]interface{}{
+				// WaitInstanceHasNoneActiveTransaction
+				{
+					"filename":   "SoftLayer_Virtual_Guest_getObject_HasNoneActiveTxn.json",
+					"statusCode": http.StatusOK,
+				},
+				{
+					"filename":   "SoftLayer_Virtual_Guest_getUpgradeItemPrices_InternalError.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			err := cli.AttachPrimaryIpv6Address(vgID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Finding IPv6 address price"))
+		})
+	})
+
+	Describe("FindVirtualServerItemPrice", func() {
+		It("Find the standard price of the category", func() {
+			respParas = []map[string]interface{}{
+				// getVirtualServerPackageID
+				{
+					"filename":   "SoftLayer_Product_Package_getAllObjects.json",
+					"statusCode": http.StatusOK,
+				},
+				{
+					"filename":   "SoftLayer_Product_Package_getItemPrices_Ipv6.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			itemPrice, found, err := cli.FindVirtualServerItemPrice(slClient.PRIMARY_IPV6_CATEGORY_CODE)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(*itemPrice.Id).To(Equal(17129))
+			Expect(itemPrice.LocationGroupId).To(BeNil())
+		})
+
+		It("Return not found when the package has no price in the category", func() {
+			respParas = []map[string]interface{}{
+				// getVirtualServerPackageID
+				{
+					"filename":   "SoftLayer_Product_Package_getAllObjects.json",
+					"statusCode": http.StatusOK,
+				},
+				{
+					"filename":   "SoftLayer_Product_Package_getItemPrices.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, found, err := cli.FindVirtualServerItemPrice(slClient.PRIMARY_IPV6_CATEGORY_CODE)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("Return error when SoftLayerProductPackage call getAllObjects return an error", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Product_Package_getAllObjects_InternalError.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, found, err := cli.FindVirtualServerItemPrice(slClient.PRIMARY_IPV6_CATEGORY_CODE)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+			Expect(found).To(BeFalse())
+		})
+
+		It("Return error when SoftLayerProductPackage call getItemPrices return an error", func() {
+			respParas = []map[string]interface{}{
+				// getVirtualServerPackageID
+				{
+					"filename":   "SoftLayer_Product_Package_getAllObjects.json",
+					"statusCode": http.StatusOK,
+				},
+				{
+					"filename":   "SoftLayer_Product_Package_getItemPrices_InternalError.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, found, err := cli.FindVirtualServerItemPrice(slClient.PRIMARY_IPV6_CATEGORY_CODE)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+			Expect(found).To(BeFalse())
+		})
+	})
+
 	Describe("AttachSecondDiskToInstance", func() {
 		It("Attach successfully", func() {
 			respParas = []map[string]interface{}{
@@ -2672,8 +3353,12 @@ var _ = Describe("InstanceHandler", func() {
 				err = test_helpers.SpecifyServerResps(respParas, server)
 				Expect(err).NotTo(HaveOccurred())
 
+				fakeClock := test_helpers.NewFakeClock(time.Now())
+				cli = cli.WithClock(fakeClock)
+
 				err := cli.SetUserDataWithID(vgID, userData)
 				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeClock.SleptTimes()).To(Equal([]time.Duration{30 * time.Second, 30 * time.Second}))
 			})
 
 			It("failed to set instance's metadata when meet SoftLayer_Exception_NotImplemented exception 11 times", func() {
@@ -2726,9 +3411,13 @@ var _ = Describe("InstanceHandler", func() {
 				err = test_helpers.SpecifyServerResps(respParas, server)
 				Expect(err).NotTo(HaveOccurred())
 
+				fakeClock := test_helpers.NewFakeClock(time.Now())
+				cli = cli.WithClock(fakeClock)
+
 				err := cli.SetUserDataWithID(vgID, userData)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("Time Out!"))
+				Expect(fakeClock.TotalSlept()).To(Equal(5 * time.Minute))
 			})
 
 			It("failed to set instance's metadata", func() {
@@ -2747,4 +3436,58 @@ var _ = Describe("InstanceHandler", func() {
 			})
 		})
 	})
+
+	Describe("GetUserDataWithID", func() {
+		Context("when VirtualGuestService GetUserData call successfully", func() {
+			It("get instance's user data successfully", func() {
+				respParas = []map[string]interface{}{
+					{
+						"filename":   "SoftLayer_Virtual_Guest_getUserData.json",
+						"statusCode": http.StatusOK,
+					},
+				}
+				err = test_helpers.SpecifyServerResps(respParas, server)
+				Expect(err).NotTo(HaveOccurred())
+
+				userData, found, err := cli.GetUserDataWithID(vgID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(userData.Server.Name).To(Equal("25804753"))
+				Expect(userData.Settings).NotTo(BeNil())
+				Expect(userData.Settings.AgentID).To(Equal("fake-agent-id"))
+			})
+		})
+
+		Context("when VirtualGuestService GetUserData call return error", func() {
+			It("return not found when the instance does not exist", func() {
+				respParas = []map[string]interface{}{
+					{
+						"filename":   "SoftLayer_Virtual_Guest_getUserData_NotFound.json",
+						"statusCode": http.StatusInternalServerError,
+					},
+				}
+				err = test_helpers.SpecifyServerResps(respParas, server)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, found, err := cli.GetUserDataWithID(vgID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+
+			It("failed to get instance's user data", func() {
+				respParas = []map[string]interface{}{
+					{
+						"filename":   "SoftLayer_Virtual_Guest_setUserMetadata_InternalError.json",
+						"statusCode": http.StatusInternalServerError,
+					},
+				}
+				err = test_helpers.SpecifyServerResps(respParas, server)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, err := cli.GetUserDataWithID(vgID)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+			})
+		})
+	})
 })
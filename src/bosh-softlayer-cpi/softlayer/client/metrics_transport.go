@@ -0,0 +1,88 @@
+package client
+
+import (
+	"net"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+
+	"bosh-softlayer-cpi/metrics"
+)
+
+// Error classes of the SoftLayer API calls recorded in the metrics
+const (
+	ErrorClassRateLimited = "rate_limited"
+	ErrorClassNotFound    = "not_found"
+	ErrorClassClient      = "client_error"
+	ErrorClassServer      = "server_error"
+	ErrorClassTimeout     = "timeout"
+	ErrorClassTransport   = "transport"
+)
+
+// MetricsTransportHandler wraps a softlayer-go transport, recording the count, latency and error class of each call
+type MetricsTransportHandler struct {
+	handler  session.TransportHandler
+	recorder *metrics.Recorder
+	clock    clock.Clock
+}
+
+func NewMetricsTransportHandler(handler session.TransportHandler, recorder *metrics.Recorder) *MetricsTransportHandler {
+	return &MetricsTransportHandler{
+		handler:  handler,
+		recorder: recorder,
+		clock:    clock.NewClock(),
+	}
+}
+
+// WithClock returns a copy of the handler timing the calls with the given clock
+func (h *MetricsTransportHandler) WithClock(clock clock.Clock) *MetricsTransportHandler {
+	handler := *h
+	handler.clock = clock
+	return &handler
+}
+
+func (h *MetricsTransportHandler) DoRequest(sess *session.Session, service string, method string, args []interface{}, options *sl.Options, pResult interface{}) error {
+	start := h.clock.Now()
+	err := h.handler.DoRequest(sess, service, method, args, options, pResult)
+	h.recorder.ObserveCall(service, method, h.clock.Since(start), ErrorClass(err))
+
+	return err
+}
+
+// ErrorClass sorts the error of a SoftLayer API call into a few classes, or returns "" when there is none
+func ErrorClass(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	if IsRateLimited(err) {
+		return ErrorClassRateLimited
+	}
+
+	apiErr, ok := err.(sl.Error)
+	if !ok {
+		return ErrorClassTransport
+	}
+
+	switch {
+	case apiErr.Exception == SOFTLAYER_OBJECTNOTFOUND_EXCEPTION:
+		return ErrorClassNotFound
+	case apiErr.StatusCode >= 500:
+		return ErrorClassServer
+	case apiErr.StatusCode >= 400:
+		return ErrorClassClient
+	}
+
+	if netErr, ok := apiErr.Wrapped.(net.Error); ok && netErr.Timeout() {
+		return ErrorClassTimeout
+	}
+
+	return ErrorClassTransport
+}
+
+// observeWait records how long the wait loop started at start took, and whether it gave up
+func (c *ClientManager) observeWait(name string, start time.Time, err *error) {
+	c.metrics.ObserveWait(name, c.clock.Since(start), *err != nil)
+}
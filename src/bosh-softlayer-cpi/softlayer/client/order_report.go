@@ -0,0 +1,100 @@
+package client
+
+import (
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// OrderReport describes an order verified by SoftLayer without placing it
+type OrderReport struct {
+	Verified bool               `json:"verified"`
+	Prices   []OrderReportPrice `json:"prices"`
+	Errors   []string           `json:"errors"`
+
+	HourlyRecurringFee  float64 `json:"hourly_recurring_fee"`
+	MonthlyRecurringFee float64 `json:"monthly_recurring_fee"`
+	SetupFee            float64 `json:"setup_fee"`
+}
+
+type OrderReportPrice struct {
+	Id          int    `json:"id"`
+	Category    string `json:"category"`
+	ItemKeyName string `json:"item_key_name"`
+	Description string `json:"description"`
+
+	HourlyRecurringFee float64 `json:"hourly_recurring_fee"`
+	RecurringFee       float64 `json:"recurring_fee"`
+	SetupFee           float64 `json:"setup_fee"`
+}
+
+func newOrderReport(order datatypes.Container_Product_Order) OrderReport {
+	report := OrderReport{
+		Verified:            true,
+		Prices:              []OrderReportPrice{},
+		Errors:              []string{},
+		HourlyRecurringFee:  float64Value(order.PostTaxRecurringHourly),
+		MonthlyRecurringFee: float64Value(order.PostTaxRecurringMonthly),
+		SetupFee:            float64Value(order.PostTaxSetup),
+	}
+
+	for _, price := range order.Prices {
+		reportPrice := OrderReportPrice{
+			Id:                 sl.Get(price.Id, 0).(int),
+			HourlyRecurringFee: float64Value(price.HourlyRecurringFee),
+			RecurringFee:       float64Value(price.RecurringFee),
+			SetupFee:           float64Value(price.SetupFee),
+		}
+		if len(price.Categories) > 0 {
+			reportPrice.Category = sl.Get(price.Categories[0].CategoryCode, "").(string)
+		}
+		if price.Item != nil {
+			reportPrice.ItemKeyName = sl.Get(price.Item.KeyName, "").(string)
+			reportPrice.Description = sl.Get(price.Item.Description, "").(string)
+		}
+		report.Prices = append(report.Prices, reportPrice)
+	}
+
+	return report
+}
+
+// NewRejectedOrderReport reports an order that was refused before or by SoftLayer
+func NewRejectedOrderReport(reason error) OrderReport {
+	return OrderReport{
+		Verified: false,
+		Prices:   []OrderReportPrice{},
+		Errors:   []string{reason.Error()},
+	}
+}
+
+// isOrderRejection tells a SoftLayer exception raised by the order validation apart from
+// transport, authentication, rate limit and server failures
+func isOrderRejection(err error) bool {
+	apiErr, ok := err.(sl.Error)
+	return ok && (strings.HasPrefix(apiErr.Exception, "SoftLayer_Exception_Order_") || apiErr.Exception == "SoftLayer_Exception_Public")
+}
+
+// isApiFailure tells a failed SoftLayer API call, possibly wrapped, apart from an order that cannot be
+// placed, whether SoftLayer refused it or the CPI found no price for it
+func isApiFailure(err error) bool {
+	for {
+		complexErr, ok := err.(bosherr.ComplexError)
+		if !ok {
+			break
+		}
+		err = complexErr.Cause
+	}
+
+	_, ok := err.(sl.Error)
+	return ok && !isOrderRejection(err)
+}
+
+func float64Value(value *datatypes.Float64) float64 {
+	if value == nil {
+		return 0
+	}
+
+	return float64(*value)
+}
@@ -61,7 +61,7 @@ var _ = Describe("packageHandler", func() {
 		logger = cpiLog.NewLogger(boshlogger.LevelDebug, strconv.Itoa(nanos))
 		multiLogger = api.MultiLogger{Logger: logger, LogBuff: &errOutLog}
 		sess = test_helpers.NewFakeSoftlayerSession(transportHandler)
-		cli = slClient.NewSoftLayerClientManager(sess, vps, swiftClient, logger)
+		cli = slClient.NewSoftLayerClientManager(sess, vps, swiftClient, logger).WithClock(test_helpers.NewFakeClock(time.Now()))
 
 		label = "fake-label"
 		key = "fake-key"
@@ -134,6 +134,79 @@ var _ = Describe("packageHandler", func() {
 		})
 	})
 
+	Describe("GetVirtualServerPackageItems", func() {
+		It("Get virtual server package items successfully", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Product_Package_getAllObjects.json",
+					"statusCode": http.StatusOK,
+				},
+				{
+					"filename":   "SoftLayer_Product_Package_getItems.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			items, err := cli.GetVirtualServerPackageItems()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(items).NotTo(BeEmpty())
+		})
+
+		It("Return error when SoftLayer_Product_Package call getAllObjects return an empty object", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Product_Package_getAllObjects_Empty.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = cli.GetVirtualServerPackageItems()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("No package found for type"))
+		})
+	})
+
+	Describe("GetVirtualServerPackagePresets", func() {
+		It("Get virtual server package presets successfully", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Product_Package_getAllObjects.json",
+					"statusCode": http.StatusOK,
+				},
+				{
+					"filename":   "SoftLayer_Product_Package_getActivePresets.json",
+					"statusCode": http.StatusOK,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			presets, err := cli.GetVirtualServerPackagePresets()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(presets).To(HaveLen(1))
+			Expect(*presets[0].KeyName).To(Equal("B1_2X4X25"))
+		})
+
+		It("Return error when SoftLayer_Product_Package call getAllObjects return an error", func() {
+			respParas = []map[string]interface{}{
+				{
+					"filename":   "SoftLayer_Product_Package_getAllObjects_InternalError.json",
+					"statusCode": http.StatusInternalServerError,
+				},
+			}
+			err = test_helpers.SpecifyServerResps(respParas, server)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = cli.GetVirtualServerPackagePresets()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+		})
+	})
+
 	Describe("GetPerformanceIscsiPackage", func() {
 		It("Get Performance Package successfully", func() {
 			respParas = []map[string]interface{}{
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+
+	"code.cloudfoundry.org/clock"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+
+	"bosh-softlayer-cpi/logger"
+	boslconfig "bosh-softlayer-cpi/softlayer/config"
+)
+
+const (
+	SOFTLAYER_RATELIMITEXCEEDED_EXCEPTION = "SoftLayer_Exception_WebService_RateLimitExceeded"
+
+	retryLogTag = "SoftLayerRetry"
+)
+
+// RetryTransportHandler wraps a softlayer-go transport, retrying with backoff the calls SoftLayer refuses as rate limited
+type RetryTransportHandler struct {
+	handler session.TransportHandler
+	policy  boslconfig.RetryPolicy
+	logger  logger.Logger
+	clock   clock.Clock
+}
+
+func NewRetryTransportHandler(handler session.TransportHandler, policy boslconfig.RetryPolicy, logger logger.Logger) *RetryTransportHandler {
+	return &RetryTransportHandler{
+		handler: handler,
+		policy:  policy.WithDefaults(),
+		logger:  logger,
+		clock:   clock.NewClock(),
+	}
+}
+
+// DefaultTransportHandler is the transport softlayer-go picks for the endpoint when the session sets none
+func DefaultTransportHandler(apiEndpoint string) session.TransportHandler {
+	if strings.Contains(apiEndpoint, "/xmlrpc/") {
+		return &session.XmlRpcTransport{}
+	}
+
+	return &session.RestTransport{}
+}
+
+// WithClock returns a copy of the handler sleeping between retries with the given clock
+func (h *RetryTransportHandler) WithClock(clock clock.Clock) *RetryTransportHandler {
+	handler := *h
+	handler.clock = clock
+	return &handler
+}
+
+func (h *RetryTransportHandler) DoRequest(sess *session.Session, service string, method string, args []interface{}, options *sl.Options, pResult interface{}) error {
+	for retry := 0; ; retry++ {
+		err := h.handler.DoRequest(sess, service, method, args, options, pResult)
+		if err == nil || !IsRateLimited(err) || retry >= *h.policy.MaxRetries {
+			return err
+		}
+
+		delay := h.policy.Delay(retry)
+		h.logger.Warn(retryLogTag, "Retrying %s::%s in %s: %s", service, method, delay, err)
+		h.clock.Sleep(delay)
+	}
+}
+
+// IsRateLimited tells whether SoftLayer refused a call because the account sent too many requests
+func IsRateLimited(err error) bool {
+	apiErr, ok := err.(sl.Error)
+	if !ok {
+		return false
+	}
+
+	return apiErr.Exception == SOFTLAYER_RATELIMITEXCEEDED_EXCEPTION || apiErr.StatusCode == http.StatusTooManyRequests
+}
+
+// backOff sleeps before the given retry of a call SoftLayer refused for now, logging the delay
+func (c *ClientManager) backOff(retry int, call string, err error) {
+	delay := c.retryPolicy.Delay(retry)
+	c.logger.Warn(retryLogTag, "Retrying %s in %s: %s", call, delay, err)
+	c.clock.Sleep(delay)
+}
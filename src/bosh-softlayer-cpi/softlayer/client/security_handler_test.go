@@ -59,7 +59,7 @@ var _ = Describe("SecurityHandler", func() {
 		logger = cpiLog.NewLogger(boshlogger.LevelDebug, strconv.Itoa(nanos))
 		multiLogger = api.MultiLogger{Logger: logger, LogBuff: &errOutLog}
 		sess = test_helpers.NewFakeSoftlayerSession(transportHandler)
-		cli = slClient.NewSoftLayerClientManager(sess, vps, swiftClient, logger)
+		cli = slClient.NewSoftLayerClientManager(sess, vps, swiftClient, logger).WithClock(test_helpers.NewFakeClock(time.Now()))
 
 		label = "fake-label"
 		key = "fake-key"
@@ -2,6 +2,7 @@ package client
 
 import (
 	"log"
+	"net/http"
 	"time"
 
 	"fmt"
@@ -14,14 +15,23 @@ const (
 	SoftlayerGoLogTag                  = "softlayerGo"
 )
 
+// NewSoftlayerClientSession returns a session tracing its calls through outLogger when trace is set. The trace is
+// done by the transport of the session, so that the process-wide logger of softlayer-go is left alone.
 func NewSoftlayerClientSession(apiEndpoint string, username string, password string, trace bool, timeoutSec int, retries int, retryWaitSec int, outLogger *log.Logger) *session.Session {
 	// Use native logger's prefix as bosh logger tag
 	outLogger.SetPrefix(fmt.Sprintf("[%s:%s] ", outLogger.Prefix(), SoftlayerGoLogTag))
-	session.Logger = outLogger
 	session := session.New(username, password, apiEndpoint)
-	session.Debug = trace
+	session.Debug = false
 	session.Timeout = time.Duration(timeoutSec) * time.Second
 	session.Retries = retries
 	session.RetryWait = time.Duration(retryWaitSec) * time.Second
+	// softlayer-go sets the timeout of the session on its HTTP client, so sessions served concurrently
+	// each get their own client rather than the shared default one. They still share its transport.
+	session.HTTPClient = &http.Client{}
+
+	session.TransportHandler = DefaultTransportHandler(apiEndpoint)
+	if trace {
+		session.TransportHandler = NewTraceTransportHandler(session.TransportHandler, outLogger)
+	}
 	return session
 }
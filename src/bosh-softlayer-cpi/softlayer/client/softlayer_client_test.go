@@ -7,8 +7,20 @@ import (
 	"bytes"
 	"io"
 	"log"
+	"net/http"
+	"time"
 
+	boshlogger "github.com/cloudfoundry/bosh-utils/logger"
+	"github.com/onsi/gomega/ghttp"
+	"github.com/softlayer/softlayer-go/services"
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+
+	cpiLog "bosh-softlayer-cpi/logger"
+	"bosh-softlayer-cpi/metrics"
 	boslc "bosh-softlayer-cpi/softlayer/client"
+	boslconfig "bosh-softlayer-cpi/softlayer/config"
+	"bosh-softlayer-cpi/test_helpers"
 )
 
 var _ = Describe("SoftlayerClient", func() {
@@ -36,3 +48,251 @@ var _ = Describe("SoftlayerClient", func() {
 		}()
 	})
 })
+
+var _ = Describe("ClientManager", func() {
+	It("waits with the default timeouts", func() {
+		cli := boslc.NewSoftLayerClientManager(nil, nil, nil, nil)
+		Expect(cli.Timeouts()).To(Equal(boslconfig.DefaultTimeouts()))
+	})
+
+	It("keeps the defaults of the timeouts left unset", func() {
+		cli := boslc.NewSoftLayerClientManager(nil, nil, nil, nil).WithTimeouts(boslconfig.Timeouts{
+			CreateInstance:       boslconfig.NewDuration(time.Minute),
+			InstancePollInterval: boslconfig.NewDuration(time.Second),
+		})
+
+		timeouts := cli.Timeouts()
+		Expect(timeouts.CreateInstance.Duration).To(Equal(time.Minute))
+		Expect(timeouts.InstancePollInterval.Duration).To(Equal(time.Second))
+		Expect(timeouts.CreateInstanceDelay).To(Equal(boslconfig.DefaultTimeouts().CreateInstanceDelay))
+		Expect(timeouts.CancelInstance).To(Equal(boslconfig.DefaultTimeouts().CancelInstance))
+	})
+})
+
+var _ = Describe("TraceTransportHandler", func() {
+	var (
+		server    *ghttp.Server
+		logOut    bytes.Buffer
+		sess      *session.Session
+		respParas []map[string]interface{}
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		logOut.Reset()
+
+		transportHandler := &test_helpers.FakeTransportHandler{
+			FakeServer:           server,
+			SoftlayerAPIEndpoint: server.URL(),
+		}
+		sess = &session.Session{
+			TransportHandler: boslc.NewTraceTransportHandler(transportHandler, log.New(&logOut, "", 0)),
+		}
+	})
+
+	AfterEach(func() {
+		test_helpers.DestroyServer(server)
+	})
+
+	It("traces the calls of its session through its own logger", func() {
+		respParas = []map[string]interface{}{
+			{
+				"filename":   "SoftLayer_Account_getCurrentUser.json",
+				"statusCode": http.StatusOK,
+			},
+		}
+		err := test_helpers.SpecifyServerResps(respParas, server)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = services.GetAccountService(sess).Mask("id").GetCurrentUser()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(logOut.String()).To(ContainSubstring("Request: SoftLayer_Account::getCurrentUser mask=id"))
+		Expect(logOut.String()).To(ContainSubstring(`Response: SoftLayer_Account::getCurrentUser: {"id":247176`))
+	})
+
+	It("traces the errors of its session", func() {
+		respParas = []map[string]interface{}{
+			{
+				"filename":   "SoftLayer_Account_getCurrentUser_InternalError.json",
+				"statusCode": http.StatusInternalServerError,
+			},
+		}
+		err := test_helpers.SpecifyServerResps(respParas, server)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = services.GetAccountService(sess).GetCurrentUser()
+		Expect(err).To(HaveOccurred())
+		Expect(logOut.String()).To(ContainSubstring("Error: SoftLayer_Account::getCurrentUser: "))
+	})
+})
+
+var _ = Describe("RetryTransportHandler", func() {
+	var (
+		server    *ghttp.Server
+		fakeClock *test_helpers.FakeClock
+		logOut    bytes.Buffer
+		sess      *session.Session
+		respParas []map[string]interface{}
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		fakeClock = test_helpers.NewFakeClock(time.Now())
+		logOut.Reset()
+		logger := cpiLog.NewWriterLogger(boshlogger.LevelDebug, "fake-prefix", &logOut, &logOut)
+
+		transportHandler := &test_helpers.FakeTransportHandler{
+			FakeServer:           server,
+			SoftlayerAPIEndpoint: server.URL(),
+		}
+		jitter := 0.5
+		policy := boslconfig.RetryPolicy{MaxRetries: sl.Int(2), InitialDelay: boslconfig.NewDuration(10 * time.Second), Jitter: &jitter}
+		sess = &session.Session{
+			TransportHandler: boslc.NewRetryTransportHandler(transportHandler, policy, logger).WithClock(fakeClock),
+		}
+	})
+
+	AfterEach(func() {
+		test_helpers.DestroyServer(server)
+	})
+
+	It("retries rate limited calls with growing, jittered delays", func() {
+		respParas = []map[string]interface{}{
+			{
+				"filename":   "SoftLayer_Account_getCurrentUser_RateLimitExceeded.json",
+				"statusCode": http.StatusTooManyRequests,
+			},
+			{
+				"filename":   "SoftLayer_Account_getCurrentUser_RateLimitExceeded.json",
+				"statusCode": http.StatusTooManyRequests,
+			},
+			{
+				"filename":   "SoftLayer_Account_getCurrentUser.json",
+				"statusCode": http.StatusOK,
+			},
+		}
+		err := test_helpers.SpecifyServerResps(respParas, server)
+		Expect(err).NotTo(HaveOccurred())
+
+		user, err := services.GetAccountService(sess).GetCurrentUser()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*user.Id).To(Equal(247176))
+
+		sleeps := fakeClock.SleptTimes()
+		Expect(sleeps).To(HaveLen(2))
+		Expect(sleeps[0]).To(BeNumerically("~", 10*time.Second, 5*time.Second))
+		Expect(sleeps[1]).To(BeNumerically("~", 20*time.Second, 10*time.Second))
+		Expect(logOut.String()).To(ContainSubstring("Retrying SoftLayer_Account::getCurrentUser in "))
+	})
+
+	It("gives up once the retries are used", func() {
+		respParas = []map[string]interface{}{
+			{
+				"filename":   "SoftLayer_Account_getCurrentUser_RateLimitExceeded.json",
+				"statusCode": http.StatusTooManyRequests,
+			},
+			{
+				"filename":   "SoftLayer_Account_getCurrentUser_RateLimitExceeded.json",
+				"statusCode": http.StatusTooManyRequests,
+			},
+			{
+				"filename":   "SoftLayer_Account_getCurrentUser_RateLimitExceeded.json",
+				"statusCode": http.StatusTooManyRequests,
+			},
+		}
+		err := test_helpers.SpecifyServerResps(respParas, server)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = services.GetAccountService(sess).GetCurrentUser()
+		Expect(err).To(HaveOccurred())
+		Expect(boslc.IsRateLimited(err)).To(BeTrue())
+		Expect(server.ReceivedRequests()).To(HaveLen(3))
+		Expect(fakeClock.SleptTimes()).To(HaveLen(2))
+	})
+
+	It("does not retry other errors", func() {
+		respParas = []map[string]interface{}{
+			{
+				"filename":   "SoftLayer_Account_getCurrentUser_InternalError.json",
+				"statusCode": http.StatusInternalServerError,
+			},
+		}
+		err := test_helpers.SpecifyServerResps(respParas, server)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = services.GetAccountService(sess).GetCurrentUser()
+		Expect(err).To(HaveOccurred())
+		Expect(err.(sl.Error).Exception).To(Equal("UNKNOWN_ERROR"))
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		Expect(fakeClock.SleptTimes()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("MetricsTransportHandler", func() {
+	var (
+		server    *ghttp.Server
+		fakeClock *test_helpers.FakeClock
+		recorder  *metrics.Recorder
+		sess      *session.Session
+		respParas []map[string]interface{}
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		fakeClock = test_helpers.NewFakeClock(time.Now())
+		recorder = metrics.NewRecorder()
+
+		transportHandler := &test_helpers.FakeTransportHandler{
+			FakeServer:           server,
+			SoftlayerAPIEndpoint: server.URL(),
+		}
+		sess = &session.Session{
+			TransportHandler: boslc.NewMetricsTransportHandler(transportHandler, recorder).WithClock(fakeClock),
+		}
+	})
+
+	AfterEach(func() {
+		test_helpers.DestroyServer(server)
+	})
+
+	It("records each call with the class of its error", func() {
+		respParas = []map[string]interface{}{
+			{
+				"filename":   "SoftLayer_Account_getCurrentUser.json",
+				"statusCode": http.StatusOK,
+			},
+			{
+				"filename":   "SoftLayer_Account_getCurrentUser_RateLimitExceeded.json",
+				"statusCode": http.StatusTooManyRequests,
+			},
+			{
+				"filename":   "SoftLayer_Account_getCurrentUser_InternalError.json",
+				"statusCode": http.StatusInternalServerError,
+			},
+		}
+		err := test_helpers.SpecifyServerResps(respParas, server)
+		Expect(err).NotTo(HaveOccurred())
+
+		for i := 0; i < 3; i++ {
+			services.GetAccountService(sess).GetCurrentUser()
+		}
+
+		calls := recorder.Snapshot().Calls
+		Expect(calls).To(HaveLen(1))
+		Expect(calls[0].Service).To(Equal("SoftLayer_Account"))
+		Expect(calls[0].Method).To(Equal("getCurrentUser"))
+		Expect(calls[0].Timings.Count()).To(Equal(3))
+		Expect(calls[0].Errors).To(Equal(map[string]int{
+			boslc.ErrorClassRateLimited: 1,
+			boslc.ErrorClassServer:      1,
+		}))
+	})
+
+	It("classes the errors of SoftLayer API calls", func() {
+		Expect(boslc.ErrorClass(nil)).To(BeEmpty())
+		Expect(boslc.ErrorClass(sl.Error{StatusCode: 404, Exception: boslc.SOFTLAYER_OBJECTNOTFOUND_EXCEPTION})).To(Equal(boslc.ErrorClassNotFound))
+		Expect(boslc.ErrorClass(sl.Error{StatusCode: 400, Exception: "SoftLayer_Exception_Public"})).To(Equal(boslc.ErrorClassClient))
+		Expect(boslc.ErrorClass(sl.Error{StatusCode: 500, Exception: boslc.SOFTLAYER_RATELIMITEXCEEDED_EXCEPTION})).To(Equal(boslc.ErrorClassRateLimited))
+		Expect(boslc.ErrorClass(sl.Error{Message: "connection refused"})).To(Equal(boslc.ErrorClassTransport))
+	})
+})
@@ -242,7 +242,7 @@ func (lo *largeObject) retryPutPart(part *part) {
 			lo.bp.give <- part.b
 			return
 		}
-		lo.logger.Error(swiftLargeObjectLogTag, fmt.Sprintf("Error on attempt %d: Retrying part: %v, Error: %s", i, part, err))
+		lo.logger.Error(swiftLargeObjectLogTag, "Error on attempt %d: Retrying part: %v, Error: %s", i, part, err)
 	}
 	lo.err = err
 }
@@ -252,7 +252,7 @@ func (lo *largeObject) putPart(part *part) error {
 	container := lo.container
 	objectName := lo.objectName + "/" + lo.timestamp + "/" + fmt.Sprintf("%d", part.PartNumber)
 
-	lo.logger.Debug(swiftLargeObjectLogTag, "Upload Part: (", container, objectName, part.len, fmt.Sprintf("%x", part.contentMd5), part.ETag, ")")
+	lo.logger.Debug(swiftLargeObjectLogTag, "Upload Part: (%s %s %d %x %s)", container, objectName, part.len, part.contentMd5, part.ETag)
 
 	if _, err := part.r.Seek(0, 0); err != nil { // move back to beginning, if retrying
 		return err
@@ -295,7 +295,7 @@ func (lo *largeObject) Close() (err error) {
 		return lo.err
 	}
 	// Complete Multipart upload
-	lo.logger.Debug(swiftLargeObjectLogTag, "Complete multipart: (", lo.container, lo.objectName, "X-Object-Manifest: ", lo.container+"/"+lo.objectName+"/"+lo.timestamp, ")")
+	lo.logger.Debug(swiftLargeObjectLogTag, "Complete multipart: (%s %s X-Object-Manifest: %s)", lo.container, lo.objectName, lo.container+"/"+lo.objectName+"/"+lo.timestamp)
 
 	reqHeaders := map[string]string{"X-Object-Manifest": lo.container + "/" + lo.objectName + "/" + lo.timestamp}
 
@@ -314,7 +314,7 @@ func (lo *largeObject) Close() (err error) {
 		lo.abort()
 		return err
 	}
-	lo.logger.Debug(swiftLargeObjectLogTag, fmt.Sprintf("Set multipart header: %#v", headers))
+	lo.logger.Debug(swiftLargeObjectLogTag, "Set multipart header: %#v", headers)
 
 	return
 }
@@ -323,14 +323,14 @@ func (lo *largeObject) Close() (err error) {
 func (lo *largeObject) abort() {
 	objects, err := lo.c.ObjectNamesAll(lo.container, nil)
 	if err != nil {
-		lo.logger.Error(swiftLargeObjectLogTag, fmt.Sprintf("Return all multipart objects: %v\n", err))
+		lo.logger.Error(swiftLargeObjectLogTag, "Return all multipart objects: %v\n", err)
 		return
 	}
 	for _, object := range objects {
 		if strings.HasPrefix(object, lo.objectName+"/"+lo.timestamp+"/") {
 			lo.c.ObjectDelete(lo.container, object)
 			if err != nil {
-				lo.logger.Error(swiftLargeObjectLogTag, fmt.Sprintf("Delete the multipart objects: %v\n", err))
+				lo.logger.Error(swiftLargeObjectLogTag, "Delete the multipart objects: %v\n", err)
 			}
 		}
 	}
@@ -359,7 +359,7 @@ func (lo *largeObject) md5Content(r io.ReadSeeker) (string, string, error) {
 func (lo *largeObject) putMd5() (err error) {
 	calcMd5 := fmt.Sprintf("%x", lo.md5.Sum(nil))
 	md5Reader := strings.NewReader(calcMd5)
-	lo.logger.Debug(swiftLargeObjectLogTag, fmt.Sprintf("Put md5: %s of object: %s", calcMd5, lo.container+"/"+lo.objectName+".md5"))
+	lo.logger.Debug(swiftLargeObjectLogTag, "Put md5: %s of object: %s", calcMd5, lo.container+"/"+lo.objectName+".md5")
 	_, err = lo.c.ObjectPut(lo.container, lo.objectName+".md5", md5Reader, true, "", "", nil)
 	return
 }
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/softlayer/softlayer-go/session"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+// TraceTransportHandler wraps a softlayer-go transport, logging each call and its result through the logger of
+// its own session. softlayer-go traces through the process-wide session.Logger instead, which every session shares.
+type TraceTransportHandler struct {
+	handler session.TransportHandler
+	logger  *log.Logger
+}
+
+func NewTraceTransportHandler(handler session.TransportHandler, logger *log.Logger) *TraceTransportHandler {
+	return &TraceTransportHandler{
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+func (h *TraceTransportHandler) DoRequest(sess *session.Session, service string, method string, args []interface{}, options *sl.Options, pResult interface{}) error {
+	h.logger.Printf("Request: %s::%s%s", service, method, traceOptions(options))
+	if len(args) > 0 {
+		h.logger.Printf("Parameters: %s", traceJSON(args))
+	}
+
+	err := h.handler.DoRequest(sess, service, method, args, options, pResult)
+	if err != nil {
+		h.logger.Printf("Error: %s::%s: %s", service, method, err)
+		return err
+	}
+
+	h.logger.Printf("Response: %s::%s: %s", service, method, traceJSON(pResult))
+	return nil
+}
+
+func traceOptions(options *sl.Options) string {
+	if options == nil {
+		return ""
+	}
+
+	var trace string
+	if options.Id != nil {
+		trace += fmt.Sprintf(" id=%d", *options.Id)
+	}
+	if options.Mask != "" {
+		trace += fmt.Sprintf(" mask=%s", options.Mask)
+	}
+	if options.Filter != "" {
+		trace += fmt.Sprintf(" filter=%s", options.Filter)
+	}
+	if options.Limit != nil {
+		trace += fmt.Sprintf(" limit=%d", *options.Limit)
+	}
+	if options.Offset != nil {
+		trace += fmt.Sprintf(" offset=%d", *options.Offset)
+	}
+
+	return trace
+}
+
+func traceJSON(v interface{}) string {
+	traced, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+
+	return string(traced)
+}
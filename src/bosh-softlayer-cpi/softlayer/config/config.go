@@ -1,9 +1,14 @@
 package config
 
 import (
+	"net"
+
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 )
 
+// DefaultPrivateRoutes are the SoftLayer service networks, reached through the backend gateway of VMs
+var DefaultPrivateRoutes = []string{"10.0.0.0/8", "161.26.0.0/16", "166.8.0.0/14"}
+
 type Config struct {
 	Username             string `json:"username"`
 	ApiKey               string `json:"api_key"`
@@ -12,12 +17,20 @@ type Config struct {
 	PublicKey            string `json:"ssh_public_key"`
 	PublicKeyFingerPrint string `json:"ssh_public_key_fingerprint"`
 	EnableVps            bool   `json:"enable_vps"`
-	Trace                bool   `json:"trace"`
 	VpsHost              string `json:"vps_host"`
 	VpsPort              int    `json:"vps_port"`
 	SwiftUsername        string `json:"swift_username"`
 	SwiftEndpoint        string `json:"swift_endpoint"`
 	// SWIFT password is also SoftLayer API key
+
+	// Deprecated in favour of the softlayer_trace_level of the log options, only read when it is unset
+	Trace bool `json:"trace"`
+
+	Timeouts Timeouts    `json:"timeouts"`
+	Retry    RetryPolicy `json:"retry"`
+
+	// Destinations in CIDR notation routed through the backend gateway of every VM, DefaultPrivateRoutes when unset
+	PrivateRoutes []string `json:"private_routes"`
 }
 
 func (c Config) Validate() error {
@@ -29,5 +42,28 @@ func (c Config) Validate() error {
 		return bosherr.Error("Must provide non-empty ApiKey")
 	}
 
+	if err := c.Timeouts.Validate(); err != nil {
+		return bosherr.WrapError(err, "Validating timeouts")
+	}
+
+	if err := c.Retry.Validate(); err != nil {
+		return bosherr.WrapError(err, "Validating retry policy")
+	}
+
+	for _, destination := range c.PrivateRoutes {
+		if _, _, err := net.ParseCIDR(destination); err != nil {
+			return bosherr.Errorf("Private route destination '%s' is not a CIDR", destination)
+		}
+	}
+
 	return nil
 }
+
+// PrivateRoutesOrDefault returns the configured private routes, or DefaultPrivateRoutes when there are none
+func (c Config) PrivateRoutesOrDefault() []string {
+	if c.PrivateRoutes == nil {
+		return DefaultPrivateRoutes
+	}
+
+	return c.PrivateRoutes
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// RetryPolicy backs off exponentially, with jitter, between retries of the SoftLayer calls
+// refused as rate limited or blocked by another operation. Unset values take the defaults from DefaultRetryPolicy;
+// max_retries and jitter are pointers so that an explicit 0 turns retries or jitter off instead of being unset.
+type RetryPolicy struct {
+	// Retries of a rate limited call before giving up on it
+	MaxRetries *int `json:"max_retries"`
+	// Delay before the first retry
+	InitialDelay Duration `json:"initial_delay"`
+	// Upper bound of the delay, however many retries came before
+	MaxDelay Duration `json:"max_delay"`
+	// Factor the delay grows by after each retry
+	Multiplier float64 `json:"multiplier"`
+	// Fraction of the delay randomly added or removed, so that concurrent CPI calls spread their retries
+	Jitter *float64 `json:"jitter"`
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	maxRetries := 5
+	jitter := 0.2
+
+	return RetryPolicy{
+		MaxRetries:   &maxRetries,
+		InitialDelay: NewDuration(1 * time.Second),
+		MaxDelay:     NewDuration(1 * time.Minute),
+		Multiplier:   2,
+		Jitter:       &jitter,
+	}
+}
+
+// WithDefaults returns a copy of the policy with the unset values taken from DefaultRetryPolicy
+func (p RetryPolicy) WithDefaults() RetryPolicy {
+	defaults := DefaultRetryPolicy()
+	if p.MaxRetries == nil {
+		p.MaxRetries = defaults.MaxRetries
+	}
+	if p.InitialDelay.Duration == 0 {
+		p.InitialDelay = defaults.InitialDelay
+	}
+	if p.MaxDelay.Duration == 0 {
+		p.MaxDelay = defaults.MaxDelay
+	}
+	if p.Multiplier == 0 {
+		p.Multiplier = defaults.Multiplier
+	}
+	if p.Jitter == nil {
+		p.Jitter = defaults.Jitter
+	}
+
+	return p
+}
+
+func (p RetryPolicy) Validate() error {
+	if p.MaxRetries != nil && *p.MaxRetries < 0 {
+		return bosherr.Error("Retry 'max_retries' must not be negative")
+	}
+
+	if p.InitialDelay.Duration < 0 || p.MaxDelay.Duration < 0 {
+		return bosherr.Error("Retry delays must not be negative")
+	}
+
+	if p.Multiplier != 0 && p.Multiplier < 1 {
+		return bosherr.Errorf("Retry 'multiplier' must be at least 1, got %v", p.Multiplier)
+	}
+
+	if p.Jitter != nil && (*p.Jitter < 0 || *p.Jitter > 1) {
+		return bosherr.Errorf("Retry 'jitter' must be between 0 and 1, got %v", *p.Jitter)
+	}
+
+	return nil
+}
+
+// Delay returns how long to wait before the given retry, counted from 0. An unset jitter adds none.
+func (p RetryPolicy) Delay(retry int) time.Duration {
+	delay := float64(p.InitialDelay.Duration) * math.Pow(p.Multiplier, float64(retry))
+	if max := float64(p.MaxDelay.Duration); delay > max {
+		delay = max
+	}
+
+	if p.Jitter != nil {
+		delay += delay * *p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(delay)
+}
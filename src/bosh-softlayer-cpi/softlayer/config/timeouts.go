@@ -0,0 +1,172 @@
+package config
+
+import (
+	"encoding/json"
+	"time"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// Duration is a time.Duration read from a Go duration string such as "90s" or "4h",
+// or from a number of seconds
+type Duration struct {
+	time.Duration
+}
+
+func NewDuration(d time.Duration) Duration {
+	return Duration{d}
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case float64:
+		d.Duration = time.Duration(v * float64(time.Second))
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Parsing duration '%s'", v)
+		}
+		d.Duration = parsed
+	default:
+		return bosherr.Errorf("Invalid duration %s", string(data))
+	}
+
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Timeouts bounds every phase the SoftLayer client waits on, and how often it polls meanwhile.
+// Zero values take the defaults from DefaultTimeouts; create_instance_delay is a pointer so that
+// an explicit 0 turns the delay off instead of being unset.
+type Timeouts struct {
+	// Delay between creating an instance and setting its user data
+	CreateInstanceDelay *Duration `json:"create_instance_delay"`
+	// Waiting for a new instance to be running with no active transaction
+	CreateInstance Duration `json:"create_instance"`
+	// Waiting for an instance to be running after editing it
+	EditInstance Duration `json:"edit_instance"`
+	// Retrying the OS reload request while SoftLayer refuses it
+	ReloadRequest Duration `json:"reload_request"`
+	// Waiting for the transactions before and after an OS reload to finish and start
+	ReloadTransaction Duration `json:"reload_transaction"`
+	// Waiting for an instance to be running after an OS reload
+	ReloadInstance Duration `json:"reload_instance"`
+	// Waiting for active transactions to finish before canceling an instance
+	CancelInstance Duration `json:"cancel_instance"`
+	// Waiting for each step of an instance upgrade order
+	UpgradeInstance Duration `json:"upgrade_instance"`
+	// Retrying to set the user data of an instance
+	SetUserData Duration `json:"set_user_data"`
+	// Waiting for an ordered volume to be provisioned
+	CreateVolume Duration `json:"create_volume"`
+	// Waiting for a volume upgrade order to complete
+	ResizeVolume Duration `json:"resize_volume"`
+	// Retrying to grant or revoke an instance access to a volume
+	AuthorizeVolume Duration `json:"authorize_volume"`
+	// Retrying to set the boot mode of an imported image
+	ImageBootMode Duration `json:"image_boot_mode"`
+
+	InstancePollInterval    Duration `json:"instance_poll_interval"`
+	TransactionPollInterval Duration `json:"transaction_poll_interval"`
+	OrderPollInterval       Duration `json:"order_poll_interval"`
+	ReloadPollInterval      Duration `json:"reload_poll_interval"`
+	UserDataPollInterval    Duration `json:"user_data_poll_interval"`
+	VolumePollInterval      Duration `json:"volume_poll_interval"`
+	ImagePollInterval       Duration `json:"image_poll_interval"`
+}
+
+func DefaultTimeouts() Timeouts {
+	createInstanceDelay := NewDuration(90 * time.Second)
+
+	return Timeouts{
+		CreateInstanceDelay: &createInstanceDelay,
+		CreateInstance:      NewDuration(4 * time.Hour),
+		EditInstance:        NewDuration(30 * time.Minute),
+		ReloadRequest:       NewDuration(10 * time.Second),
+		ReloadTransaction:   NewDuration(1 * time.Hour),
+		ReloadInstance:      NewDuration(4 * time.Hour),
+		CancelInstance:      NewDuration(10 * time.Minute),
+		UpgradeInstance:     NewDuration(1 * time.Hour),
+		SetUserData:         NewDuration(5 * time.Minute),
+		CreateVolume:        NewDuration(1 * time.Hour),
+		ResizeVolume:        NewDuration(1 * time.Hour),
+		AuthorizeVolume:     NewDuration(1 * time.Hour),
+		ImageBootMode:       NewDuration(20 * time.Second),
+
+		InstancePollInterval:    NewDuration(10 * time.Second),
+		TransactionPollInterval: NewDuration(5 * time.Second),
+		OrderPollInterval:       NewDuration(5 * time.Second),
+		ReloadPollInterval:      NewDuration(2 * time.Second),
+		UserDataPollInterval:    NewDuration(30 * time.Second),
+		VolumePollInterval:      NewDuration(5 * time.Second),
+		ImagePollInterval:       NewDuration(10 * time.Second),
+	}
+}
+
+// WithDefaults returns a copy of the timeouts with the unset values taken from DefaultTimeouts
+func (t Timeouts) WithDefaults() Timeouts {
+	defaults := DefaultTimeouts()
+	if t.CreateInstanceDelay == nil {
+		t.CreateInstanceDelay = defaults.CreateInstanceDelay
+	}
+
+	defaultFields := defaults.fields()
+	for i, field := range t.fields() {
+		if field.value.Duration == 0 {
+			*field.value = *defaultFields[i].value
+		}
+	}
+
+	return t
+}
+
+func (t Timeouts) Validate() error {
+	if t.CreateInstanceDelay != nil && t.CreateInstanceDelay.Duration < 0 {
+		return bosherr.Error("Timeout 'create_instance_delay' must not be negative")
+	}
+
+	for _, field := range t.fields() {
+		if field.value.Duration < 0 {
+			return bosherr.Errorf("Timeout '%s' must not be negative", field.name)
+		}
+	}
+
+	return nil
+}
+
+type timeoutField struct {
+	name  string
+	value *Duration
+}
+
+func (t *Timeouts) fields() []timeoutField {
+	return []timeoutField{
+		{"create_instance", &t.CreateInstance},
+		{"edit_instance", &t.EditInstance},
+		{"reload_request", &t.ReloadRequest},
+		{"reload_transaction", &t.ReloadTransaction},
+		{"reload_instance", &t.ReloadInstance},
+		{"cancel_instance", &t.CancelInstance},
+		{"upgrade_instance", &t.UpgradeInstance},
+		{"set_user_data", &t.SetUserData},
+		{"create_volume", &t.CreateVolume},
+		{"resize_volume", &t.ResizeVolume},
+		{"authorize_volume", &t.AuthorizeVolume},
+		{"image_boot_mode", &t.ImageBootMode},
+		{"instance_poll_interval", &t.InstancePollInterval},
+		{"transaction_poll_interval", &t.TransactionPollInterval},
+		{"order_poll_interval", &t.OrderPollInterval},
+		{"reload_poll_interval", &t.ReloadPollInterval},
+		{"user_data_poll_interval", &t.UserDataPollInterval},
+		{"volume_poll_interval", &t.VolumePollInterval},
+		{"image_poll_interval", &t.ImagePollInterval},
+	}
+}
@@ -1,11 +1,17 @@
 package disk
 
-import "github.com/softlayer/softlayer-go/datatypes"
+import (
+	"github.com/softlayer/softlayer-go/datatypes"
+
+	bosl "bosh-softlayer-cpi/softlayer/client"
+)
 
 //go:generate counterfeiter -o fakes/fake_Disk_Service.go . Service
 type Service interface {
 	Create(size int, iops int, location string, snapshotSpace int) (int, error)
+	VerifyCreate(size int, iops int, location string, snapshotSpace int) (bosl.OrderReport, error)
 	Delete(id int) error
+	Resize(id int, size int) error
 	SetMetadata(id int, diskMetadata Metadata) error
 	Find(id int) (*datatypes.Network_Storage, error)
 }
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	bosl "bosh-softlayer-cpi/softlayer/client"
 	disk "bosh-softlayer-cpi/softlayer/disk_service"
 	"sync"
 
@@ -61,6 +62,34 @@ type FakeService struct {
 		result1 *datatypes.Network_Storage
 		result2 error
 	}
+	ResizeStub        func(id int, size int) error
+	resizeMutex       sync.RWMutex
+	resizeArgsForCall []struct {
+		id   int
+		size int
+	}
+	resizeReturns struct {
+		result1 error
+	}
+	resizeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	VerifyCreateStub        func(size int, iops int, location string, snapshotSpace int) (bosl.OrderReport, error)
+	verifyCreateMutex       sync.RWMutex
+	verifyCreateArgsForCall []struct {
+		size          int
+		iops          int
+		location      string
+		snapshotSpace int
+	}
+	verifyCreateReturns struct {
+		result1 bosl.OrderReport
+		result2 error
+	}
+	verifyCreateReturnsOnCall map[int]struct {
+		result1 bosl.OrderReport
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -267,6 +296,109 @@ func (fake *FakeService) FindReturnsOnCall(i int, result1 *datatypes.Network_Sto
 	}{result1, result2}
 }
 
+func (fake *FakeService) Resize(id int, size int) error {
+	fake.resizeMutex.Lock()
+	ret, specificReturn := fake.resizeReturnsOnCall[len(fake.resizeArgsForCall)]
+	fake.resizeArgsForCall = append(fake.resizeArgsForCall, struct {
+		id   int
+		size int
+	}{id, size})
+	fake.recordInvocation("Resize", []interface{}{id, size})
+	fake.resizeMutex.Unlock()
+	if fake.ResizeStub != nil {
+		return fake.ResizeStub(id, size)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.resizeReturns.result1
+}
+
+func (fake *FakeService) ResizeCallCount() int {
+	fake.resizeMutex.RLock()
+	defer fake.resizeMutex.RUnlock()
+	return len(fake.resizeArgsForCall)
+}
+
+func (fake *FakeService) ResizeArgsForCall(i int) (int, int) {
+	fake.resizeMutex.RLock()
+	defer fake.resizeMutex.RUnlock()
+	return fake.resizeArgsForCall[i].id, fake.resizeArgsForCall[i].size
+}
+
+func (fake *FakeService) ResizeReturns(result1 error) {
+	fake.ResizeStub = nil
+	fake.resizeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) ResizeReturnsOnCall(i int, result1 error) {
+	fake.ResizeStub = nil
+	if fake.resizeReturnsOnCall == nil {
+		fake.resizeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.resizeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) VerifyCreate(size int, iops int, location string, snapshotSpace int) (bosl.OrderReport, error) {
+	fake.verifyCreateMutex.Lock()
+	ret, specificReturn := fake.verifyCreateReturnsOnCall[len(fake.verifyCreateArgsForCall)]
+	fake.verifyCreateArgsForCall = append(fake.verifyCreateArgsForCall, struct {
+		size          int
+		iops          int
+		location      string
+		snapshotSpace int
+	}{size, iops, location, snapshotSpace})
+	fake.recordInvocation("VerifyCreate", []interface{}{size, iops, location, snapshotSpace})
+	fake.verifyCreateMutex.Unlock()
+	if fake.VerifyCreateStub != nil {
+		return fake.VerifyCreateStub(size, iops, location, snapshotSpace)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.verifyCreateReturns.result1, fake.verifyCreateReturns.result2
+}
+
+func (fake *FakeService) VerifyCreateCallCount() int {
+	fake.verifyCreateMutex.RLock()
+	defer fake.verifyCreateMutex.RUnlock()
+	return len(fake.verifyCreateArgsForCall)
+}
+
+func (fake *FakeService) VerifyCreateArgsForCall(i int) (int, int, string, int) {
+	fake.verifyCreateMutex.RLock()
+	defer fake.verifyCreateMutex.RUnlock()
+	return fake.verifyCreateArgsForCall[i].size, fake.verifyCreateArgsForCall[i].iops, fake.verifyCreateArgsForCall[i].location, fake.verifyCreateArgsForCall[i].snapshotSpace
+}
+
+func (fake *FakeService) VerifyCreateReturns(result1 bosl.OrderReport, result2 error) {
+	fake.VerifyCreateStub = nil
+	fake.verifyCreateReturns = struct {
+		result1 bosl.OrderReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeService) VerifyCreateReturnsOnCall(i int, result1 bosl.OrderReport, result2 error) {
+	fake.VerifyCreateStub = nil
+	if fake.verifyCreateReturnsOnCall == nil {
+		fake.verifyCreateReturnsOnCall = make(map[int]struct {
+			result1 bosl.OrderReport
+			result2 error
+		})
+	}
+	fake.verifyCreateReturnsOnCall[i] = struct {
+		result1 bosl.OrderReport
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeService) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -278,6 +410,10 @@ func (fake *FakeService) Invocations() map[string][][]interface{} {
 	defer fake.setMetadataMutex.RUnlock()
 	fake.findMutex.RLock()
 	defer fake.findMutex.RUnlock()
+	fake.resizeMutex.RLock()
+	defer fake.resizeMutex.RUnlock()
+	fake.verifyCreateMutex.RLock()
+	defer fake.verifyCreateMutex.RUnlock()
 	return fake.invocations
 }
 
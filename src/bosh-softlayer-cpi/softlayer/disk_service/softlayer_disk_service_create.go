@@ -4,6 +4,8 @@ import (
 	"math"
 
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	bosl "bosh-softlayer-cpi/softlayer/client"
 )
 
 func (d SoftlayerDiskService) Create(size int, iops int, location string, snapshotSpace int) (int, error) {
@@ -16,6 +18,16 @@ func (d SoftlayerDiskService) Create(size int, iops int, location string, snapsh
 	return *volume.Id, nil
 }
 
+func (d SoftlayerDiskService) VerifyCreate(size int, iops int, location string, snapshotSpace int) (bosl.OrderReport, error) {
+	d.logger.Debug(softlayerDiskServiceLogTag, "Verifying order for disk of size '%d'", size)
+	report, err := d.softlayerClient.VerifyVolumeOrder(location, d.getSoftLayerDiskSize(size), iops, snapshotSpace)
+	if err != nil {
+		return bosl.OrderReport{}, bosherr.WrapErrorf(err, "Failed to verify volume order with size '%d', iops '%d', location `%s`", d.getSoftLayerDiskSize(size), iops, location)
+	}
+
+	return report, nil
+}
+
 func (d SoftlayerDiskService) getSoftLayerDiskSize(size int) int {
 	// Sizes and IOPS ranges: http://knowledgelayer.softlayer.com/learning/performance-storage-concepts
 	sizeArray := []int{20, 40, 80, 100, 250, 500, 1000, 2000, 4000, 8000, 12000}
@@ -11,6 +11,7 @@ import (
 	"github.com/softlayer/softlayer-go/sl"
 
 	cpiLog "bosh-softlayer-cpi/logger"
+	bosl "bosh-softlayer-cpi/softlayer/client"
 	fakeslclient "bosh-softlayer-cpi/softlayer/client/fakes"
 	diskService "bosh-softlayer-cpi/softlayer/disk_service"
 )
@@ -67,4 +68,35 @@ var _ = Describe("Disk Service Create", func() {
 			})
 		})
 	})
+
+	Describe("Call VerifyCreate", func() {
+		It("verifies the volume order with the SoftLayer disk size", func() {
+			cli.VerifyVolumeOrderReturns(
+				bosl.OrderReport{Verified: true},
+				nil,
+			)
+
+			report, err := disk.VerifyCreate(size, iops, location, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Verified).To(BeTrue())
+			Expect(cli.CreateVolumeCallCount()).To(Equal(0))
+			Expect(cli.VerifyVolumeOrderCallCount()).To(Equal(1))
+			actualLocation, actualSize, actualIops, actualSnapshotSpace := cli.VerifyVolumeOrderArgsForCall(0)
+			Expect(actualLocation).To(Equal(location))
+			Expect(actualSize).To(Equal(20))
+			Expect(actualIops).To(Equal(iops))
+			Expect(actualSnapshotSpace).To(Equal(10))
+		})
+
+		It("returns error when softlayerClient VerifyVolumeOrder call returns error", func() {
+			cli.VerifyVolumeOrderReturns(
+				bosl.OrderReport{},
+				errors.New("fake-client-error"),
+			)
+
+			_, err = disk.VerifyCreate(size, iops, location, 10)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+		})
+	})
 })
@@ -32,6 +32,11 @@ func (d SoftlayerDiskService) generateNotesFromDiskMetadata(vmMetadata Metadata)
 		tagStringBuffer.WriteString(",")
 	}
 
+	if val, ok := vmMetadata["director_uuid"]; ok {
+		tagStringBuffer.WriteString("director_uuid" + ":" + val.(string))
+		tagStringBuffer.WriteString(",")
+	}
+
 	if val, ok := vmMetadata["deployment"]; ok {
 		tagStringBuffer.WriteString("deployment" + ":" + val.(string))
 		tagStringBuffer.WriteString(",")
@@ -58,6 +58,19 @@ var _ = Describe("Virtual Guest Service", func() {
 			Expect(cli.SetNotesCallCount()).To(Equal(1))
 		})
 
+		It("Set director uuid into notes", func() {
+			metaData["director_uuid"] = "fake-director-uuid"
+			cli.SetNotesReturns(
+				true,
+				nil,
+			)
+
+			err := diskService.SetMetadata(diskID, metaData)
+			Expect(err).NotTo(HaveOccurred())
+			_, actualNotes := cli.SetNotesArgsForCall(0)
+			Expect(actualNotes).To(ContainSubstring("director_uuid:fake-director-uuid"))
+		})
+
 		It("Return error if softLayerClient SetNotes call returns an error", func() {
 			cli.SetNotesReturns(
 				false,
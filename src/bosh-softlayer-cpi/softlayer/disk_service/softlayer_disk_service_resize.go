@@ -0,0 +1,67 @@
+package disk
+
+import (
+	"strconv"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"github.com/softlayer/softlayer-go/sl"
+
+	"bosh-softlayer-cpi/api"
+)
+
+const volumeResizeMask = "id,capacityGb,provisionedIops,storageType.keyName,billingItem.categoryCode"
+
+// Resize grows the volume to the given size (MB) in place. Only performance volumes ordered through
+// the storage as a service package can be upgraded, others return api.NotSupportedError.
+func (d SoftlayerDiskService) Resize(id int, size int) error {
+	d.logger.Debug(softlayerDiskServiceLogTag, "Resizing disk '%d' to size '%d'", id, size)
+	volume, found, err := d.softlayerClient.GetBlockVolumeDetails(id, volumeResizeMask)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Getting details of disk '%d'", id)
+	}
+
+	if !found {
+		return api.NewDiskNotFoundError(strconv.Itoa(id), false)
+	}
+
+	categoryCode := ""
+	if volume.BillingItem != nil {
+		categoryCode = sl.Get(volume.BillingItem.CategoryCode, "").(string)
+	}
+	storageType := ""
+	if volume.StorageType != nil {
+		storageType = sl.Get(volume.StorageType.KeyName, "").(string)
+	}
+	if categoryCode != "storage_as_a_service" || !strings.Contains(storageType, "PERFORMANCE") {
+		d.logger.Debug(softlayerDiskServiceLogTag, "Disk '%d' of storage type '%s' and category '%s' can not be resized in place", id, storageType, categoryCode)
+		return api.NotSupportedError{}
+	}
+
+	newSize := d.getSoftLayerDiskSize(size)
+	currentSize := sl.Get(volume.CapacityGb, 0).(int)
+	if newSize < currentSize {
+		return bosherr.Errorf("Resizing disk '%d': can not shrink from '%dGB' to '%dGB'", id, currentSize, newSize)
+	}
+	if newSize == currentSize {
+		d.logger.Debug(softlayerDiskServiceLogTag, "Disk '%d' already has size '%dGB', skipping resize", id, currentSize)
+		return nil
+	}
+
+	iops, err := strconv.Atoi(sl.Get(volume.ProvisionedIops, "0").(string))
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Parsing provisioned iops of disk '%d'", id)
+	}
+
+	orderId, err := d.softlayerClient.UpgradeBlockVolume(id, newSize, iops)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Resizing disk '%d' to size '%dGB'", id, newSize)
+	}
+
+	until := d.softlayerClient.Clock().Now().Add(d.softlayerClient.Timeouts().ResizeVolume.Duration)
+	if err = d.softlayerClient.WaitOrderCompleted(orderId, until); err != nil {
+		return bosherr.WrapErrorf(err, "Waiting until order placed has been completed after resizing disk '%d'", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package disk_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"errors"
+	"time"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/sl"
+
+	"bosh-softlayer-cpi/api"
+	cpiLog "bosh-softlayer-cpi/logger"
+	fakeslclient "bosh-softlayer-cpi/softlayer/client/fakes"
+	boslconfig "bosh-softlayer-cpi/softlayer/config"
+	diskService "bosh-softlayer-cpi/softlayer/disk_service"
+	"bosh-softlayer-cpi/test_helpers"
+)
+
+var _ = Describe("Disk Service Resize", func() {
+	var (
+		err error
+
+		diskID int
+		volume *datatypes.Network_Storage
+
+		cli       *fakeslclient.FakeClient
+		fakeClock *test_helpers.FakeClock
+		disk      diskService.SoftlayerDiskService
+		logger    cpiLog.Logger
+	)
+	BeforeEach(func() {
+		diskID = 12345678
+		volume = &datatypes.Network_Storage{
+			Id:              sl.Int(diskID),
+			CapacityGb:      sl.Int(20),
+			ProvisionedIops: sl.String("1000"),
+			StorageType: &datatypes.Network_Storage_Type{
+				KeyName: sl.String("PERFORMANCE_BLOCK_STORAGE"),
+			},
+			BillingItem: &datatypes.Billing_Item{
+				CategoryCode: sl.String("storage_as_a_service"),
+			},
+		}
+
+		cli = &fakeslclient.FakeClient{}
+		fakeClock = test_helpers.NewFakeClock(time.Now())
+		cli.ClockReturns(fakeClock)
+		logger = cpiLog.NewLogger(boshlog.LevelDebug, "")
+		disk = diskService.NewSoftlayerDiskService(cli, logger)
+	})
+
+	Describe("Call Resize", func() {
+		Context("when the volume is a storage as a service performance volume", func() {
+			It("upgrades the volume and waits for the order", func() {
+				cli.GetBlockVolumeDetailsReturns(volume, true, nil)
+				cli.UpgradeBlockVolumeReturns(87654321, nil)
+				cli.TimeoutsReturns(boslconfig.Timeouts{ResizeVolume: boslconfig.NewDuration(time.Hour)})
+
+				err = disk.Resize(diskID, 40960)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cli.UpgradeBlockVolumeCallCount()).To(Equal(1))
+				actualID, actualSize, actualIops := cli.UpgradeBlockVolumeArgsForCall(0)
+				Expect(actualID).To(Equal(diskID))
+				Expect(actualSize).To(Equal(40))
+				Expect(actualIops).To(Equal(1000))
+				Expect(cli.WaitOrderCompletedCallCount()).To(Equal(1))
+				orderID, until := cli.WaitOrderCompletedArgsForCall(0)
+				Expect(orderID).To(Equal(87654321))
+				Expect(until).To(Equal(fakeClock.Now().Add(time.Hour)))
+			})
+
+			It("does nothing when the volume already has the requested size", func() {
+				cli.GetBlockVolumeDetailsReturns(volume, true, nil)
+
+				err = disk.Resize(diskID, 20480)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cli.UpgradeBlockVolumeCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when shrinking the volume", func() {
+				volume.CapacityGb = sl.Int(80)
+				cli.GetBlockVolumeDetailsReturns(volume, true, nil)
+
+				err = disk.Resize(diskID, 40960)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("can not shrink"))
+				Expect(cli.UpgradeBlockVolumeCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when softlayerClient UpgradeBlockVolume call returns an error", func() {
+				cli.GetBlockVolumeDetailsReturns(volume, true, nil)
+				cli.UpgradeBlockVolumeReturns(0, errors.New("fake-client-error"))
+
+				err = disk.Resize(diskID, 40960)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+				Expect(cli.WaitOrderCompletedCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when softlayerClient WaitOrderCompleted call returns an error", func() {
+				cli.GetBlockVolumeDetailsReturns(volume, true, nil)
+				cli.UpgradeBlockVolumeReturns(87654321, nil)
+				cli.WaitOrderCompletedReturns(errors.New("fake-client-error"))
+
+				err = disk.Resize(diskID, 40960)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+			})
+		})
+
+		Context("when the volume can not be resized in place", func() {
+			It("returns a NotSupported error for legacy performance volumes", func() {
+				volume.BillingItem.CategoryCode = sl.String("performance_storage_iscsi")
+				cli.GetBlockVolumeDetailsReturns(volume, true, nil)
+
+				err = disk.Resize(diskID, 40960)
+				Expect(err).To(Equal(api.NotSupportedError{}))
+				Expect(cli.UpgradeBlockVolumeCallCount()).To(Equal(0))
+			})
+
+			It("returns a NotSupported error for endurance volumes", func() {
+				volume.StorageType.KeyName = sl.String("ENDURANCE_BLOCK_STORAGE")
+				cli.GetBlockVolumeDetailsReturns(volume, true, nil)
+
+				err = disk.Resize(diskID, 40960)
+				Expect(err).To(Equal(api.NotSupportedError{}))
+			})
+		})
+
+		It("returns a DiskNotFound error when the volume does not exist", func() {
+			cli.GetBlockVolumeDetailsReturns(&datatypes.Network_Storage{}, false, nil)
+
+			err = disk.Resize(diskID, 40960)
+			Expect(err).To(HaveOccurred())
+			_, ok := err.(api.DiskNotFoundError)
+			Expect(ok).To(BeTrue())
+		})
+
+		It("returns an error when softlayerClient GetBlockVolumeDetails call returns an error", func() {
+			cli.GetBlockVolumeDetailsReturns(&datatypes.Network_Storage{}, false, errors.New("fake-client-error"))
+
+			err = disk.Resize(diskID, 40960)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+		})
+	})
+})
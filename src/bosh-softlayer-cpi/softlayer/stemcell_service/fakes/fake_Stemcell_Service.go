@@ -35,6 +35,30 @@ type FakeService struct {
 		result1 int
 		result2 error
 	}
+	DeleteStub        func(id int) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		id int
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	FindOsCodeStub        func(id int) (string, error)
+	findOsCodeMutex       sync.RWMutex
+	findOsCodeArgsForCall []struct {
+		id int
+	}
+	findOsCodeReturns struct {
+		result1 string
+		result2 error
+	}
+	findOsCodeReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -143,6 +167,105 @@ func (fake *FakeService) CreateFromTarballReturnsOnCall(i int, result1 int, resu
 	}{result1, result2}
 }
 
+func (fake *FakeService) Delete(id int) error {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		id int
+	}{id})
+	fake.recordInvocation("Delete", []interface{}{id})
+	fake.deleteMutex.Unlock()
+	if fake.DeleteStub != nil {
+		return fake.DeleteStub(id)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.deleteReturns.result1
+}
+
+func (fake *FakeService) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeService) DeleteArgsForCall(i int) int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return fake.deleteArgsForCall[i].id
+}
+
+func (fake *FakeService) DeleteReturns(result1 error) {
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) DeleteReturnsOnCall(i int, result1 error) {
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) FindOsCode(id int) (string, error) {
+	fake.findOsCodeMutex.Lock()
+	ret, specificReturn := fake.findOsCodeReturnsOnCall[len(fake.findOsCodeArgsForCall)]
+	fake.findOsCodeArgsForCall = append(fake.findOsCodeArgsForCall, struct {
+		id int
+	}{id})
+	fake.recordInvocation("FindOsCode", []interface{}{id})
+	fake.findOsCodeMutex.Unlock()
+	if fake.FindOsCodeStub != nil {
+		return fake.FindOsCodeStub(id)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.findOsCodeReturns.result1, fake.findOsCodeReturns.result2
+}
+
+func (fake *FakeService) FindOsCodeCallCount() int {
+	fake.findOsCodeMutex.RLock()
+	defer fake.findOsCodeMutex.RUnlock()
+	return len(fake.findOsCodeArgsForCall)
+}
+
+func (fake *FakeService) FindOsCodeArgsForCall(i int) int {
+	fake.findOsCodeMutex.RLock()
+	defer fake.findOsCodeMutex.RUnlock()
+	return fake.findOsCodeArgsForCall[i].id
+}
+
+func (fake *FakeService) FindOsCodeReturns(result1 string, result2 error) {
+	fake.FindOsCodeStub = nil
+	fake.findOsCodeReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeService) FindOsCodeReturnsOnCall(i int, result1 string, result2 error) {
+	fake.FindOsCodeStub = nil
+	if fake.findOsCodeReturnsOnCall == nil {
+		fake.findOsCodeReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.findOsCodeReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeService) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -150,6 +273,10 @@ func (fake *FakeService) Invocations() map[string][][]interface{} {
 	defer fake.findMutex.RUnlock()
 	fake.createFromTarballMutex.RLock()
 	defer fake.createFromTarballMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	fake.findOsCodeMutex.RLock()
+	defer fake.findOsCodeMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
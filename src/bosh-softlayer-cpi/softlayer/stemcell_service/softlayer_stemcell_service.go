@@ -10,6 +10,9 @@ import (
 const softlayerImageNamePrefix = "stemcell"
 const softlayerStemcellServiceLogTag = "SoftlayerStemcellService"
 
+// CreateFromTarball imports image templates with this note, so only private images carrying it may be deleted
+const softlayerImportedImageNote = "Imported by SL CPI"
+
 type SoftlayerStemcellService struct {
 	softlayerClient bosl.Client
 	uuidGen         boshuuid.Generator
@@ -60,7 +60,7 @@ func (s SoftlayerStemcellService) CreateFromTarball(imagePath string, datacenter
 	}
 
 	// Import
-	stemcellId, err := s.softlayerClient.CreateImageFromExternalSource(imageName, "Imported by SL CPI", datacenter, osCode)
+	stemcellId, err := s.softlayerClient.CreateImageFromExternalSource(imageName, softlayerImportedImageNote, datacenter, osCode)
 	if err != nil {
 		return 0, bosherr.WrapErrorf(err, "Create image from Swift object storage")
 	}
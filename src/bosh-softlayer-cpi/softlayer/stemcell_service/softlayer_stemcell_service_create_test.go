@@ -85,6 +85,8 @@ var _ = Describe("Stemcell Service", func() {
 				Expect(cli.CreateSwiftContainerCallCount()).To(Equal(1))
 				Expect(cli.UploadSwiftLargeObjectCallCount()).To(Equal(1))
 				Expect(cli.CreateImageFromExternalSourceCallCount()).To(Equal(1))
+				_, note, _, _ := cli.CreateImageFromExternalSourceArgsForCall(0)
+				Expect(note).To(Equal("Imported by SL CPI"))
 				Expect(cli.DeleteSwiftLargeObjectCallCount()).To(Equal(1))
 				Expect(cli.DeleteSwiftContainerCallCount()).To(Equal(1))
 				Expect(globalIdentifier).To(Equal(stemcellID))
@@ -0,0 +1,57 @@
+package stemcell
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/sl"
+)
+
+const softlayerImageDeleteMask = "id, globalIdentifier, note, publicFlag"
+
+// Delete removes an image template imported by CreateFromTarball, i.e. a private image whose note
+// is still softlayerImportedImageNote. Other images (e.g. the shared images behind light stemcells,
+// or imported ones whose note was changed) and images still used by guests are left alone.
+func (s SoftlayerStemcellService) Delete(id int) error {
+	image, found, err := s.softlayerClient.GetImage(id, softlayerImageDeleteMask)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Getting image template with id '%d'", id)
+	}
+
+	if !found {
+		s.logger.Info(softlayerStemcellServiceLogTag, "Image template '%d' does not exist, skipping deletion", id)
+		return nil
+	}
+
+	if !s.isImported(image) {
+		s.logger.Info(softlayerStemcellServiceLogTag, "Image template '%d' was not imported by the CPI, skipping deletion", id)
+		return nil
+	}
+
+	if image.GlobalIdentifier != nil {
+		instances, err := s.softlayerClient.GetInstancesByImage(*image.GlobalIdentifier)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Getting instances provisioned from image template '%d'", id)
+		}
+
+		if len(instances) > 0 {
+			s.logger.Warn(softlayerStemcellServiceLogTag, "Image template '%d' is still used by %d instance(s), skipping deletion", id, len(instances))
+			return nil
+		}
+	}
+
+	s.logger.Debug(softlayerStemcellServiceLogTag, "Deleting image template '%d'", id)
+	err = s.softlayerClient.DeleteImage(id)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Deleting image template with id '%d'", id)
+	}
+
+	return nil
+}
+
+func (s SoftlayerStemcellService) isImported(image *datatypes.Virtual_Guest_Block_Device_Template_Group) bool {
+	if sl.Get(image.PublicFlag, 0).(int) == 1 {
+		return false
+	}
+
+	return sl.Get(image.Note, "").(string) == softlayerImportedImageNote
+}
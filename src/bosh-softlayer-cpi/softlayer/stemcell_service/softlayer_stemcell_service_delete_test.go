@@ -0,0 +1,122 @@
+package stemcell_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	fakeuuid "github.com/cloudfoundry/bosh-utils/uuid/fakes"
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/sl"
+
+	cpiLog "bosh-softlayer-cpi/logger"
+	fakeslclient "bosh-softlayer-cpi/softlayer/client/fakes"
+	stemcellService "bosh-softlayer-cpi/softlayer/stemcell_service"
+)
+
+var _ = Describe("Stemcell Service Delete", func() {
+	var (
+		err error
+
+		stemcellID int
+		image      *datatypes.Virtual_Guest_Block_Device_Template_Group
+		cli        *fakeslclient.FakeClient
+		stemcell   stemcellService.SoftlayerStemcellService
+		uuidGen    *fakeuuid.FakeGenerator
+		logger     cpiLog.Logger
+	)
+	BeforeEach(func() {
+		stemcellID = 22345678
+		image = &datatypes.Virtual_Guest_Block_Device_Template_Group{
+			Id:               sl.Int(stemcellID),
+			GlobalIdentifier: sl.String("07beadaa-1e11-476e-a188-3f7795feb9fb"),
+			Note:             sl.String("Imported by SL CPI"),
+			PublicFlag:       sl.Int(0),
+		}
+		cli = &fakeslclient.FakeClient{}
+		logger = cpiLog.NewLogger(boshlog.LevelDebug, "")
+		uuidGen = &fakeuuid.FakeGenerator{}
+		stemcell = stemcellService.NewSoftlayerStemcellService(cli, uuidGen, logger)
+	})
+
+	Describe("Call Delete", func() {
+		Context("when the image was imported by the CPI", func() {
+			It("deletes the image", func() {
+				cli.GetImageReturns(image, true, nil)
+
+				err = stemcell.Delete(stemcellID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cli.GetInstancesByImageCallCount()).To(Equal(1))
+				Expect(cli.GetInstancesByImageArgsForCall(0)).To(Equal("07beadaa-1e11-476e-a188-3f7795feb9fb"))
+				Expect(cli.DeleteImageCallCount()).To(Equal(1))
+				Expect(cli.DeleteImageArgsForCall(0)).To(Equal(stemcellID))
+			})
+
+			It("skips deletion when the image is still used by instances", func() {
+				cli.GetImageReturns(image, true, nil)
+				cli.GetInstancesByImageReturns([]datatypes.Virtual_Guest{{Id: sl.Int(12345678)}}, nil)
+
+				err = stemcell.Delete(stemcellID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cli.DeleteImageCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when softlayerClient GetInstancesByImage call returns an error", func() {
+				cli.GetImageReturns(image, true, nil)
+				cli.GetInstancesByImageReturns([]datatypes.Virtual_Guest{}, errors.New("fake-client-error"))
+
+				err = stemcell.Delete(stemcellID)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+				Expect(cli.DeleteImageCallCount()).To(Equal(0))
+			})
+
+			It("returns an error when softlayerClient DeleteImage call returns an error", func() {
+				cli.GetImageReturns(image, true, nil)
+				cli.DeleteImageReturns(errors.New("fake-client-error"))
+
+				err = stemcell.Delete(stemcellID)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+			})
+		})
+
+		Context("when the image was not imported by the CPI", func() {
+			It("leaves images without the CPI note alone", func() {
+				image.Note = sl.String("description")
+				cli.GetImageReturns(image, true, nil)
+
+				err = stemcell.Delete(stemcellID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cli.DeleteImageCallCount()).To(Equal(0))
+			})
+
+			It("leaves public images alone", func() {
+				image.PublicFlag = sl.Int(1)
+				cli.GetImageReturns(image, true, nil)
+
+				err = stemcell.Delete(stemcellID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cli.DeleteImageCallCount()).To(Equal(0))
+			})
+		})
+
+		It("does nothing when the image does not exist", func() {
+			cli.GetImageReturns(&datatypes.Virtual_Guest_Block_Device_Template_Group{}, false, nil)
+
+			err = stemcell.Delete(stemcellID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cli.DeleteImageCallCount()).To(Equal(0))
+		})
+
+		It("returns an error when softlayerClient GetImage call returns an error", func() {
+			cli.GetImageReturns(&datatypes.Virtual_Guest_Block_Device_Template_Group{}, false, errors.New("fake-client-error"))
+
+			err = stemcell.Delete(stemcellID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+		})
+	})
+})
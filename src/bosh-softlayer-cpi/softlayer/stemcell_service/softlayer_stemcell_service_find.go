@@ -5,7 +5,6 @@ import (
 	"strconv"
 	"time"
 
-	"code.cloudfoundry.org/clock"
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 	boshretry "github.com/cloudfoundry/bosh-utils/retrystrategy"
 	"github.com/softlayer/softlayer-go/datatypes"
@@ -33,7 +32,7 @@ func (s SoftlayerStemcellService) Find(id int) (string, error) {
 
 			return false, nil
 		})
-	timeService := clock.NewClock()
+	timeService := s.softlayerClient.Clock()
 	timeoutRetryStrategy := boshretry.NewTimeoutRetryStrategy(1*time.Minute, 5*time.Second, execStmtRetryable, timeService, s.logger.GetBoshLogger())
 	s.logger.ChangeRetryStrategyLogTag(&timeoutRetryStrategy)
 
@@ -44,3 +43,35 @@ func (s SoftlayerStemcellService) Find(id int) (string, error) {
 
 	return *vgbdtg.GlobalIdentifier, nil
 }
+
+// FindOsCode returns the os-code the stemcell was imported with, which SoftLayer keeps as the
+// operating system software of the image template disks, or of their copies in other datacenters.
+// It is empty when the image records no operating system.
+func (s SoftlayerStemcellService) FindOsCode(id int) (string, error) {
+	vgbdtg, found, err := s.softlayerClient.GetImage(id, bosl.IMAGE_OS_CODE_MASK)
+	if err != nil {
+		return "", bosherr.WrapErrorf(err, "Getting os code of VirtualGuestBlockDeviceTemplateGroup with id '%d'", id)
+	}
+
+	if !found {
+		return "", api.NewStemcellkNotFoundError(strconv.Itoa(id), false)
+	}
+
+	groups := append([]datatypes.Virtual_Guest_Block_Device_Template_Group{*vgbdtg}, vgbdtg.Children...)
+	for _, group := range groups {
+		for _, blockDevice := range group.BlockDevices {
+			if blockDevice.DiskImage == nil {
+				continue
+			}
+
+			for _, software := range blockDevice.DiskImage.SoftwareReferences {
+				description := software.SoftwareDescription
+				if description != nil && description.ReferenceCode != nil && description.OperatingSystem != nil && *description.OperatingSystem == 1 {
+					return *description.ReferenceCode, nil
+				}
+			}
+		}
+	}
+
+	return "", nil
+}
@@ -2,6 +2,7 @@ package stemcell_test
 
 import (
 	"errors"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -12,8 +13,10 @@ import (
 	"github.com/softlayer/softlayer-go/sl"
 
 	cpiLog "bosh-softlayer-cpi/logger"
+	bosl "bosh-softlayer-cpi/softlayer/client"
 	fakeslclient "bosh-softlayer-cpi/softlayer/client/fakes"
 	stemcellService "bosh-softlayer-cpi/softlayer/stemcell_service"
+	"bosh-softlayer-cpi/test_helpers"
 )
 
 var _ = Describe("Stemcell Service", func() {
@@ -29,6 +32,7 @@ var _ = Describe("Stemcell Service", func() {
 	BeforeEach(func() {
 		stemcellID = 22345678
 		cli = &fakeslclient.FakeClient{}
+		cli.ClockReturns(test_helpers.NewFakeClock(time.Now()))
 		logger = cpiLog.NewLogger(boshlog.LevelDebug, "")
 		uuidGen = &fakeuuid.FakeGenerator{}
 		stemcell = stemcellService.NewSoftlayerStemcellService(cli, uuidGen, logger)
@@ -84,4 +88,60 @@ var _ = Describe("Stemcell Service", func() {
 		})
 	})
 
+	Describe("Call FindOsCode", func() {
+		It("find the os code of the image template copies", func() {
+			cli.GetImageReturns(
+				&datatypes.Virtual_Guest_Block_Device_Template_Group{
+					Children: []datatypes.Virtual_Guest_Block_Device_Template_Group{
+						{
+							BlockDevices: []datatypes.Virtual_Guest_Block_Device_Template{
+								{
+									DiskImage: &datatypes.Virtual_Disk_Image{
+										SoftwareReferences: []datatypes.Virtual_Disk_Image_Software{
+											{SoftwareDescription: &datatypes.Software_Description{OperatingSystem: sl.Int(0), ReferenceCode: sl.String("fake-software")}},
+											{SoftwareDescription: &datatypes.Software_Description{OperatingSystem: sl.Int(1), ReferenceCode: sl.String("CENTOS_7_64")}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				true,
+				nil,
+			)
+
+			osCode, err := stemcell.FindOsCode(stemcellID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(osCode).To(Equal("CENTOS_7_64"))
+			id, mask := cli.GetImageArgsForCall(0)
+			Expect(id).To(Equal(stemcellID))
+			Expect(mask).To(Equal(bosl.IMAGE_OS_CODE_MASK))
+		})
+
+		It("return an empty os code when the image records no operating system", func() {
+			cli.GetImageReturns(&datatypes.Virtual_Guest_Block_Device_Template_Group{}, true, nil)
+
+			osCode, err := stemcell.FindOsCode(stemcellID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(osCode).To(BeEmpty())
+		})
+
+		It("return error when softlayerClient GetImage call return error", func() {
+			cli.GetImageReturns(&datatypes.Virtual_Guest_Block_Device_Template_Group{}, false, errors.New("fake-client-error"))
+
+			_, err = stemcell.FindOsCode(stemcellID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+		})
+
+		It("return error when softlayerClient GetImage call find nothing", func() {
+			cli.GetImageReturns(&datatypes.Virtual_Guest_Block_Device_Template_Group{}, false, nil)
+
+			_, err = stemcell.FindOsCode(stemcellID)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("not found"))
+		})
+	})
+
 })
@@ -3,6 +3,7 @@ package fakes
 
 import (
 	"bosh-softlayer-cpi/registry"
+	bosl "bosh-softlayer-cpi/softlayer/client"
 	instance "bosh-softlayer-cpi/softlayer/virtual_guest_service"
 	"sync"
 
@@ -82,10 +83,11 @@ type FakeService struct {
 	upgradeInstanceReturnsOnCall map[int]struct {
 		result1 error
 	}
-	ConfigureNetworksStub        func(id int, networks instance.Networks) (instance.Networks, error)
+	ConfigureNetworksStub        func(id int, osCode string, networks instance.Networks) (instance.Networks, error)
 	configureNetworksMutex       sync.RWMutex
 	configureNetworksArgsForCall []struct {
 		id       int
+		osCode   string
 		networks instance.Networks
 	}
 	configureNetworksReturns struct {
@@ -287,6 +289,141 @@ type FakeService struct {
 	updateInstanceUserDataReturnsOnCall map[int]struct {
 		result1 error
 	}
+	CalculateResourcesStub        func(cpu int, memory int, ephemeralDiskSize int) (instance.Resources, error)
+	calculateResourcesMutex       sync.RWMutex
+	calculateResourcesArgsForCall []struct {
+		cpu               int
+		memory            int
+		ephemeralDiskSize int
+	}
+	calculateResourcesReturns struct {
+		result1 instance.Resources
+		result2 error
+	}
+	calculateResourcesReturnsOnCall map[int]struct {
+		result1 instance.Resources
+		result2 error
+	}
+	VerifyCreateStub        func(virtualGuest *datatypes.Virtual_Guest) (bosl.OrderReport, error)
+	verifyCreateMutex       sync.RWMutex
+	verifyCreateArgsForCall []struct {
+		virtualGuest *datatypes.Virtual_Guest
+	}
+	verifyCreateReturns struct {
+		result1 bosl.OrderReport
+		result2 error
+	}
+	verifyCreateReturnsOnCall map[int]struct {
+		result1 bosl.OrderReport
+		result2 error
+	}
+	AttachPrimaryIpv6AddressStub        func(id int) error
+	attachPrimaryIpv6AddressMutex       sync.RWMutex
+	attachPrimaryIpv6AddressArgsForCall []struct {
+		id int
+	}
+	attachPrimaryIpv6AddressReturns struct {
+		result1 error
+	}
+	attachPrimaryIpv6AddressReturnsOnCall map[int]struct {
+		result1 error
+	}
+	FindStaticIpStub        func(ip string, vlanIDs []int, subnetIDs []int) (datatypes.Network_Subnet_IpAddress, error)
+	findStaticIpMutex       sync.RWMutex
+	findStaticIpArgsForCall []struct {
+		ip        string
+		vlanIDs   []int
+		subnetIDs []int
+	}
+	findStaticIpReturns struct {
+		result1 datatypes.Network_Subnet_IpAddress
+		result2 error
+	}
+	findStaticIpReturnsOnCall map[int]struct {
+		result1 datatypes.Network_Subnet_IpAddress
+		result2 error
+	}
+	AssignStaticIpsStub        func(id int, ipAddresses []datatypes.Network_Subnet_IpAddress) error
+	assignStaticIpsMutex       sync.RWMutex
+	assignStaticIpsArgsForCall []struct {
+		id          int
+		ipAddresses []datatypes.Network_Subnet_IpAddress
+	}
+	assignStaticIpsReturns struct {
+		result1 error
+	}
+	assignStaticIpsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ReleaseStaticIpsStub        func(id int) error
+	releaseStaticIpsMutex       sync.RWMutex
+	releaseStaticIpsArgsForCall []struct {
+		id int
+	}
+	releaseStaticIpsReturns struct {
+		result1 error
+	}
+	releaseStaticIpsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RouteGlobalIpStub        func(id int, ip string) error
+	routeGlobalIpMutex       sync.RWMutex
+	routeGlobalIpArgsForCall []struct {
+		id int
+		ip string
+	}
+	routeGlobalIpReturns struct {
+		result1 error
+	}
+	routeGlobalIpReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UnrouteGlobalIpsStub        func(id int) error
+	unrouteGlobalIpsMutex       sync.RWMutex
+	unrouteGlobalIpsArgsForCall []struct {
+		id int
+	}
+	unrouteGlobalIpsReturns struct {
+		result1 error
+	}
+	unrouteGlobalIpsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ReserveStaticIpsStub        func(agentID string, ipAddresses []datatypes.Network_Subnet_IpAddress) error
+	reserveStaticIpsMutex       sync.RWMutex
+	reserveStaticIpsArgsForCall []struct {
+		agentID     string
+		ipAddresses []datatypes.Network_Subnet_IpAddress
+	}
+	reserveStaticIpsReturns struct {
+		result1 error
+	}
+	reserveStaticIpsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ReleaseReservedStaticIpsStub        func(agentID string) error
+	releaseReservedStaticIpsMutex       sync.RWMutex
+	releaseReservedStaticIpsArgsForCall []struct {
+		agentID string
+	}
+	releaseReservedStaticIpsReturns struct {
+		result1 error
+	}
+	releaseReservedStaticIpsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	HasPrimaryIpv6PriceStub        func() (bool, error)
+	hasPrimaryIpv6PriceMutex       sync.RWMutex
+	hasPrimaryIpv6PriceArgsForCall []struct {
+	}
+	hasPrimaryIpv6PriceReturns struct {
+		result1 bool
+		result2 error
+	}
+	hasPrimaryIpv6PriceReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -556,17 +693,18 @@ func (fake *FakeService) UpgradeInstanceReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeService) ConfigureNetworks(id int, networks instance.Networks) (instance.Networks, error) {
+func (fake *FakeService) ConfigureNetworks(id int, osCode string, networks instance.Networks) (instance.Networks, error) {
 	fake.configureNetworksMutex.Lock()
 	ret, specificReturn := fake.configureNetworksReturnsOnCall[len(fake.configureNetworksArgsForCall)]
 	fake.configureNetworksArgsForCall = append(fake.configureNetworksArgsForCall, struct {
 		id       int
+		osCode   string
 		networks instance.Networks
-	}{id, networks})
-	fake.recordInvocation("ConfigureNetworks", []interface{}{id, networks})
+	}{id, osCode, networks})
+	fake.recordInvocation("ConfigureNetworks", []interface{}{id, osCode, networks})
 	fake.configureNetworksMutex.Unlock()
 	if fake.ConfigureNetworksStub != nil {
-		return fake.ConfigureNetworksStub(id, networks)
+		return fake.ConfigureNetworksStub(id, osCode, networks)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -580,10 +718,10 @@ func (fake *FakeService) ConfigureNetworksCallCount() int {
 	return len(fake.configureNetworksArgsForCall)
 }
 
-func (fake *FakeService) ConfigureNetworksArgsForCall(i int) (int, instance.Networks) {
+func (fake *FakeService) ConfigureNetworksArgsForCall(i int) (int, string, instance.Networks) {
 	fake.configureNetworksMutex.RLock()
 	defer fake.configureNetworksMutex.RUnlock()
-	return fake.configureNetworksArgsForCall[i].id, fake.configureNetworksArgsForCall[i].networks
+	return fake.configureNetworksArgsForCall[i].id, fake.configureNetworksArgsForCall[i].osCode, fake.configureNetworksArgsForCall[i].networks
 }
 
 func (fake *FakeService) ConfigureNetworksReturns(result1 instance.Networks, result2 error) {
@@ -1365,6 +1503,566 @@ func (fake *FakeService) UpdateInstanceUserDataReturnsOnCall(i int, result1 erro
 	}{result1}
 }
 
+func (fake *FakeService) CalculateResources(cpu int, memory int, ephemeralDiskSize int) (instance.Resources, error) {
+	fake.calculateResourcesMutex.Lock()
+	ret, specificReturn := fake.calculateResourcesReturnsOnCall[len(fake.calculateResourcesArgsForCall)]
+	fake.calculateResourcesArgsForCall = append(fake.calculateResourcesArgsForCall, struct {
+		cpu               int
+		memory            int
+		ephemeralDiskSize int
+	}{cpu, memory, ephemeralDiskSize})
+	fake.recordInvocation("CalculateResources", []interface{}{cpu, memory, ephemeralDiskSize})
+	fake.calculateResourcesMutex.Unlock()
+	if fake.CalculateResourcesStub != nil {
+		return fake.CalculateResourcesStub(cpu, memory, ephemeralDiskSize)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.calculateResourcesReturns.result1, fake.calculateResourcesReturns.result2
+}
+
+func (fake *FakeService) CalculateResourcesCallCount() int {
+	fake.calculateResourcesMutex.RLock()
+	defer fake.calculateResourcesMutex.RUnlock()
+	return len(fake.calculateResourcesArgsForCall)
+}
+
+func (fake *FakeService) CalculateResourcesArgsForCall(i int) (int, int, int) {
+	fake.calculateResourcesMutex.RLock()
+	defer fake.calculateResourcesMutex.RUnlock()
+	return fake.calculateResourcesArgsForCall[i].cpu, fake.calculateResourcesArgsForCall[i].memory, fake.calculateResourcesArgsForCall[i].ephemeralDiskSize
+}
+
+func (fake *FakeService) CalculateResourcesReturns(result1 instance.Resources, result2 error) {
+	fake.CalculateResourcesStub = nil
+	fake.calculateResourcesReturns = struct {
+		result1 instance.Resources
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeService) CalculateResourcesReturnsOnCall(i int, result1 instance.Resources, result2 error) {
+	fake.CalculateResourcesStub = nil
+	if fake.calculateResourcesReturnsOnCall == nil {
+		fake.calculateResourcesReturnsOnCall = make(map[int]struct {
+			result1 instance.Resources
+			result2 error
+		})
+	}
+	fake.calculateResourcesReturnsOnCall[i] = struct {
+		result1 instance.Resources
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeService) VerifyCreate(virtualGuest *datatypes.Virtual_Guest) (bosl.OrderReport, error) {
+	fake.verifyCreateMutex.Lock()
+	ret, specificReturn := fake.verifyCreateReturnsOnCall[len(fake.verifyCreateArgsForCall)]
+	fake.verifyCreateArgsForCall = append(fake.verifyCreateArgsForCall, struct {
+		virtualGuest *datatypes.Virtual_Guest
+	}{virtualGuest})
+	fake.recordInvocation("VerifyCreate", []interface{}{virtualGuest})
+	fake.verifyCreateMutex.Unlock()
+	if fake.VerifyCreateStub != nil {
+		return fake.VerifyCreateStub(virtualGuest)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.verifyCreateReturns.result1, fake.verifyCreateReturns.result2
+}
+
+func (fake *FakeService) VerifyCreateCallCount() int {
+	fake.verifyCreateMutex.RLock()
+	defer fake.verifyCreateMutex.RUnlock()
+	return len(fake.verifyCreateArgsForCall)
+}
+
+func (fake *FakeService) VerifyCreateArgsForCall(i int) *datatypes.Virtual_Guest {
+	fake.verifyCreateMutex.RLock()
+	defer fake.verifyCreateMutex.RUnlock()
+	return fake.verifyCreateArgsForCall[i].virtualGuest
+}
+
+func (fake *FakeService) VerifyCreateReturns(result1 bosl.OrderReport, result2 error) {
+	fake.VerifyCreateStub = nil
+	fake.verifyCreateReturns = struct {
+		result1 bosl.OrderReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeService) VerifyCreateReturnsOnCall(i int, result1 bosl.OrderReport, result2 error) {
+	fake.VerifyCreateStub = nil
+	if fake.verifyCreateReturnsOnCall == nil {
+		fake.verifyCreateReturnsOnCall = make(map[int]struct {
+			result1 bosl.OrderReport
+			result2 error
+		})
+	}
+	fake.verifyCreateReturnsOnCall[i] = struct {
+		result1 bosl.OrderReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeService) AttachPrimaryIpv6Address(id int) error {
+	fake.attachPrimaryIpv6AddressMutex.Lock()
+	ret, specificReturn := fake.attachPrimaryIpv6AddressReturnsOnCall[len(fake.attachPrimaryIpv6AddressArgsForCall)]
+	fake.attachPrimaryIpv6AddressArgsForCall = append(fake.attachPrimaryIpv6AddressArgsForCall, struct {
+		id int
+	}{id})
+	fake.recordInvocation("AttachPrimaryIpv6Address", []interface{}{id})
+	fake.attachPrimaryIpv6AddressMutex.Unlock()
+	if fake.AttachPrimaryIpv6AddressStub != nil {
+		return fake.AttachPrimaryIpv6AddressStub(id)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.attachPrimaryIpv6AddressReturns.result1
+}
+
+func (fake *FakeService) AttachPrimaryIpv6AddressCallCount() int {
+	fake.attachPrimaryIpv6AddressMutex.RLock()
+	defer fake.attachPrimaryIpv6AddressMutex.RUnlock()
+	return len(fake.attachPrimaryIpv6AddressArgsForCall)
+}
+
+func (fake *FakeService) AttachPrimaryIpv6AddressArgsForCall(i int) int {
+	fake.attachPrimaryIpv6AddressMutex.RLock()
+	defer fake.attachPrimaryIpv6AddressMutex.RUnlock()
+	return fake.attachPrimaryIpv6AddressArgsForCall[i].id
+}
+
+func (fake *FakeService) AttachPrimaryIpv6AddressReturns(result1 error) {
+	fake.AttachPrimaryIpv6AddressStub = nil
+	fake.attachPrimaryIpv6AddressReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) AttachPrimaryIpv6AddressReturnsOnCall(i int, result1 error) {
+	fake.AttachPrimaryIpv6AddressStub = nil
+	if fake.attachPrimaryIpv6AddressReturnsOnCall == nil {
+		fake.attachPrimaryIpv6AddressReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.attachPrimaryIpv6AddressReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) FindStaticIp(ip string, vlanIDs []int, subnetIDs []int) (datatypes.Network_Subnet_IpAddress, error) {
+	var vlanIDsCopy []int
+	if vlanIDs != nil {
+		vlanIDsCopy = make([]int, len(vlanIDs))
+		copy(vlanIDsCopy, vlanIDs)
+	}
+	var subnetIDsCopy []int
+	if subnetIDs != nil {
+		subnetIDsCopy = make([]int, len(subnetIDs))
+		copy(subnetIDsCopy, subnetIDs)
+	}
+	fake.findStaticIpMutex.Lock()
+	ret, specificReturn := fake.findStaticIpReturnsOnCall[len(fake.findStaticIpArgsForCall)]
+	fake.findStaticIpArgsForCall = append(fake.findStaticIpArgsForCall, struct {
+		ip        string
+		vlanIDs   []int
+		subnetIDs []int
+	}{ip, vlanIDsCopy, subnetIDsCopy})
+	fake.recordInvocation("FindStaticIp", []interface{}{ip, vlanIDsCopy, subnetIDsCopy})
+	fake.findStaticIpMutex.Unlock()
+	if fake.FindStaticIpStub != nil {
+		return fake.FindStaticIpStub(ip, vlanIDs, subnetIDs)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.findStaticIpReturns.result1, fake.findStaticIpReturns.result2
+}
+
+func (fake *FakeService) FindStaticIpCallCount() int {
+	fake.findStaticIpMutex.RLock()
+	defer fake.findStaticIpMutex.RUnlock()
+	return len(fake.findStaticIpArgsForCall)
+}
+
+func (fake *FakeService) FindStaticIpArgsForCall(i int) (string, []int, []int) {
+	fake.findStaticIpMutex.RLock()
+	defer fake.findStaticIpMutex.RUnlock()
+	return fake.findStaticIpArgsForCall[i].ip, fake.findStaticIpArgsForCall[i].vlanIDs, fake.findStaticIpArgsForCall[i].subnetIDs
+}
+
+func (fake *FakeService) FindStaticIpReturns(result1 datatypes.Network_Subnet_IpAddress, result2 error) {
+	fake.FindStaticIpStub = nil
+	fake.findStaticIpReturns = struct {
+		result1 datatypes.Network_Subnet_IpAddress
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeService) FindStaticIpReturnsOnCall(i int, result1 datatypes.Network_Subnet_IpAddress, result2 error) {
+	fake.FindStaticIpStub = nil
+	if fake.findStaticIpReturnsOnCall == nil {
+		fake.findStaticIpReturnsOnCall = make(map[int]struct {
+			result1 datatypes.Network_Subnet_IpAddress
+			result2 error
+		})
+	}
+	fake.findStaticIpReturnsOnCall[i] = struct {
+		result1 datatypes.Network_Subnet_IpAddress
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeService) AssignStaticIps(id int, ipAddresses []datatypes.Network_Subnet_IpAddress) error {
+	var ipAddressesCopy []datatypes.Network_Subnet_IpAddress
+	if ipAddresses != nil {
+		ipAddressesCopy = make([]datatypes.Network_Subnet_IpAddress, len(ipAddresses))
+		copy(ipAddressesCopy, ipAddresses)
+	}
+	fake.assignStaticIpsMutex.Lock()
+	ret, specificReturn := fake.assignStaticIpsReturnsOnCall[len(fake.assignStaticIpsArgsForCall)]
+	fake.assignStaticIpsArgsForCall = append(fake.assignStaticIpsArgsForCall, struct {
+		id          int
+		ipAddresses []datatypes.Network_Subnet_IpAddress
+	}{id, ipAddressesCopy})
+	fake.recordInvocation("AssignStaticIps", []interface{}{id, ipAddressesCopy})
+	fake.assignStaticIpsMutex.Unlock()
+	if fake.AssignStaticIpsStub != nil {
+		return fake.AssignStaticIpsStub(id, ipAddresses)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.assignStaticIpsReturns.result1
+}
+
+func (fake *FakeService) AssignStaticIpsCallCount() int {
+	fake.assignStaticIpsMutex.RLock()
+	defer fake.assignStaticIpsMutex.RUnlock()
+	return len(fake.assignStaticIpsArgsForCall)
+}
+
+func (fake *FakeService) AssignStaticIpsArgsForCall(i int) (int, []datatypes.Network_Subnet_IpAddress) {
+	fake.assignStaticIpsMutex.RLock()
+	defer fake.assignStaticIpsMutex.RUnlock()
+	return fake.assignStaticIpsArgsForCall[i].id, fake.assignStaticIpsArgsForCall[i].ipAddresses
+}
+
+func (fake *FakeService) AssignStaticIpsReturns(result1 error) {
+	fake.AssignStaticIpsStub = nil
+	fake.assignStaticIpsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) AssignStaticIpsReturnsOnCall(i int, result1 error) {
+	fake.AssignStaticIpsStub = nil
+	if fake.assignStaticIpsReturnsOnCall == nil {
+		fake.assignStaticIpsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.assignStaticIpsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) ReleaseStaticIps(id int) error {
+	fake.releaseStaticIpsMutex.Lock()
+	ret, specificReturn := fake.releaseStaticIpsReturnsOnCall[len(fake.releaseStaticIpsArgsForCall)]
+	fake.releaseStaticIpsArgsForCall = append(fake.releaseStaticIpsArgsForCall, struct {
+		id int
+	}{id})
+	fake.recordInvocation("ReleaseStaticIps", []interface{}{id})
+	fake.releaseStaticIpsMutex.Unlock()
+	if fake.ReleaseStaticIpsStub != nil {
+		return fake.ReleaseStaticIpsStub(id)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.releaseStaticIpsReturns.result1
+}
+
+func (fake *FakeService) ReleaseStaticIpsCallCount() int {
+	fake.releaseStaticIpsMutex.RLock()
+	defer fake.releaseStaticIpsMutex.RUnlock()
+	return len(fake.releaseStaticIpsArgsForCall)
+}
+
+func (fake *FakeService) ReleaseStaticIpsArgsForCall(i int) int {
+	fake.releaseStaticIpsMutex.RLock()
+	defer fake.releaseStaticIpsMutex.RUnlock()
+	return fake.releaseStaticIpsArgsForCall[i].id
+}
+
+func (fake *FakeService) ReleaseStaticIpsReturns(result1 error) {
+	fake.ReleaseStaticIpsStub = nil
+	fake.releaseStaticIpsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) ReleaseStaticIpsReturnsOnCall(i int, result1 error) {
+	fake.ReleaseStaticIpsStub = nil
+	if fake.releaseStaticIpsReturnsOnCall == nil {
+		fake.releaseStaticIpsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releaseStaticIpsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) RouteGlobalIp(id int, ip string) error {
+	fake.routeGlobalIpMutex.Lock()
+	ret, specificReturn := fake.routeGlobalIpReturnsOnCall[len(fake.routeGlobalIpArgsForCall)]
+	fake.routeGlobalIpArgsForCall = append(fake.routeGlobalIpArgsForCall, struct {
+		id int
+		ip string
+	}{id, ip})
+	fake.recordInvocation("RouteGlobalIp", []interface{}{id, ip})
+	fake.routeGlobalIpMutex.Unlock()
+	if fake.RouteGlobalIpStub != nil {
+		return fake.RouteGlobalIpStub(id, ip)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.routeGlobalIpReturns.result1
+}
+
+func (fake *FakeService) RouteGlobalIpCallCount() int {
+	fake.routeGlobalIpMutex.RLock()
+	defer fake.routeGlobalIpMutex.RUnlock()
+	return len(fake.routeGlobalIpArgsForCall)
+}
+
+func (fake *FakeService) RouteGlobalIpArgsForCall(i int) (int, string) {
+	fake.routeGlobalIpMutex.RLock()
+	defer fake.routeGlobalIpMutex.RUnlock()
+	return fake.routeGlobalIpArgsForCall[i].id, fake.routeGlobalIpArgsForCall[i].ip
+}
+
+func (fake *FakeService) RouteGlobalIpReturns(result1 error) {
+	fake.RouteGlobalIpStub = nil
+	fake.routeGlobalIpReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) RouteGlobalIpReturnsOnCall(i int, result1 error) {
+	fake.RouteGlobalIpStub = nil
+	if fake.routeGlobalIpReturnsOnCall == nil {
+		fake.routeGlobalIpReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.routeGlobalIpReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) UnrouteGlobalIps(id int) error {
+	fake.unrouteGlobalIpsMutex.Lock()
+	ret, specificReturn := fake.unrouteGlobalIpsReturnsOnCall[len(fake.unrouteGlobalIpsArgsForCall)]
+	fake.unrouteGlobalIpsArgsForCall = append(fake.unrouteGlobalIpsArgsForCall, struct {
+		id int
+	}{id})
+	fake.recordInvocation("UnrouteGlobalIps", []interface{}{id})
+	fake.unrouteGlobalIpsMutex.Unlock()
+	if fake.UnrouteGlobalIpsStub != nil {
+		return fake.UnrouteGlobalIpsStub(id)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.unrouteGlobalIpsReturns.result1
+}
+
+func (fake *FakeService) UnrouteGlobalIpsCallCount() int {
+	fake.unrouteGlobalIpsMutex.RLock()
+	defer fake.unrouteGlobalIpsMutex.RUnlock()
+	return len(fake.unrouteGlobalIpsArgsForCall)
+}
+
+func (fake *FakeService) UnrouteGlobalIpsArgsForCall(i int) int {
+	fake.unrouteGlobalIpsMutex.RLock()
+	defer fake.unrouteGlobalIpsMutex.RUnlock()
+	return fake.unrouteGlobalIpsArgsForCall[i].id
+}
+
+func (fake *FakeService) UnrouteGlobalIpsReturns(result1 error) {
+	fake.UnrouteGlobalIpsStub = nil
+	fake.unrouteGlobalIpsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) UnrouteGlobalIpsReturnsOnCall(i int, result1 error) {
+	fake.UnrouteGlobalIpsStub = nil
+	if fake.unrouteGlobalIpsReturnsOnCall == nil {
+		fake.unrouteGlobalIpsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.unrouteGlobalIpsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) ReserveStaticIps(agentID string, ipAddresses []datatypes.Network_Subnet_IpAddress) error {
+	var ipAddressesCopy []datatypes.Network_Subnet_IpAddress
+	if ipAddresses != nil {
+		ipAddressesCopy = make([]datatypes.Network_Subnet_IpAddress, len(ipAddresses))
+		copy(ipAddressesCopy, ipAddresses)
+	}
+	fake.reserveStaticIpsMutex.Lock()
+	ret, specificReturn := fake.reserveStaticIpsReturnsOnCall[len(fake.reserveStaticIpsArgsForCall)]
+	fake.reserveStaticIpsArgsForCall = append(fake.reserveStaticIpsArgsForCall, struct {
+		agentID     string
+		ipAddresses []datatypes.Network_Subnet_IpAddress
+	}{agentID, ipAddressesCopy})
+	fake.recordInvocation("ReserveStaticIps", []interface{}{agentID, ipAddressesCopy})
+	fake.reserveStaticIpsMutex.Unlock()
+	if fake.ReserveStaticIpsStub != nil {
+		return fake.ReserveStaticIpsStub(agentID, ipAddresses)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.reserveStaticIpsReturns.result1
+}
+
+func (fake *FakeService) ReserveStaticIpsCallCount() int {
+	fake.reserveStaticIpsMutex.RLock()
+	defer fake.reserveStaticIpsMutex.RUnlock()
+	return len(fake.reserveStaticIpsArgsForCall)
+}
+
+func (fake *FakeService) ReserveStaticIpsArgsForCall(i int) (string, []datatypes.Network_Subnet_IpAddress) {
+	fake.reserveStaticIpsMutex.RLock()
+	defer fake.reserveStaticIpsMutex.RUnlock()
+	return fake.reserveStaticIpsArgsForCall[i].agentID, fake.reserveStaticIpsArgsForCall[i].ipAddresses
+}
+
+func (fake *FakeService) ReserveStaticIpsReturns(result1 error) {
+	fake.ReserveStaticIpsStub = nil
+	fake.reserveStaticIpsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) ReserveStaticIpsReturnsOnCall(i int, result1 error) {
+	fake.ReserveStaticIpsStub = nil
+	if fake.reserveStaticIpsReturnsOnCall == nil {
+		fake.reserveStaticIpsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.reserveStaticIpsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) ReleaseReservedStaticIps(agentID string) error {
+	fake.releaseReservedStaticIpsMutex.Lock()
+	ret, specificReturn := fake.releaseReservedStaticIpsReturnsOnCall[len(fake.releaseReservedStaticIpsArgsForCall)]
+	fake.releaseReservedStaticIpsArgsForCall = append(fake.releaseReservedStaticIpsArgsForCall, struct {
+		agentID string
+	}{agentID})
+	fake.recordInvocation("ReleaseReservedStaticIps", []interface{}{agentID})
+	fake.releaseReservedStaticIpsMutex.Unlock()
+	if fake.ReleaseReservedStaticIpsStub != nil {
+		return fake.ReleaseReservedStaticIpsStub(agentID)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.releaseReservedStaticIpsReturns.result1
+}
+
+func (fake *FakeService) ReleaseReservedStaticIpsCallCount() int {
+	fake.releaseReservedStaticIpsMutex.RLock()
+	defer fake.releaseReservedStaticIpsMutex.RUnlock()
+	return len(fake.releaseReservedStaticIpsArgsForCall)
+}
+
+func (fake *FakeService) ReleaseReservedStaticIpsArgsForCall(i int) string {
+	fake.releaseReservedStaticIpsMutex.RLock()
+	defer fake.releaseReservedStaticIpsMutex.RUnlock()
+	return fake.releaseReservedStaticIpsArgsForCall[i].agentID
+}
+
+func (fake *FakeService) ReleaseReservedStaticIpsReturns(result1 error) {
+	fake.ReleaseReservedStaticIpsStub = nil
+	fake.releaseReservedStaticIpsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) ReleaseReservedStaticIpsReturnsOnCall(i int, result1 error) {
+	fake.ReleaseReservedStaticIpsStub = nil
+	if fake.releaseReservedStaticIpsReturnsOnCall == nil {
+		fake.releaseReservedStaticIpsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releaseReservedStaticIpsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeService) HasPrimaryIpv6Price() (bool, error) {
+	fake.hasPrimaryIpv6PriceMutex.Lock()
+	ret, specificReturn := fake.hasPrimaryIpv6PriceReturnsOnCall[len(fake.hasPrimaryIpv6PriceArgsForCall)]
+	fake.hasPrimaryIpv6PriceArgsForCall = append(fake.hasPrimaryIpv6PriceArgsForCall, struct {
+	}{})
+	fake.recordInvocation("HasPrimaryIpv6Price", []interface{}{})
+	fake.hasPrimaryIpv6PriceMutex.Unlock()
+	if fake.HasPrimaryIpv6PriceStub != nil {
+		return fake.HasPrimaryIpv6PriceStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.hasPrimaryIpv6PriceReturns.result1, fake.hasPrimaryIpv6PriceReturns.result2
+}
+
+func (fake *FakeService) HasPrimaryIpv6PriceCallCount() int {
+	fake.hasPrimaryIpv6PriceMutex.RLock()
+	defer fake.hasPrimaryIpv6PriceMutex.RUnlock()
+	return len(fake.hasPrimaryIpv6PriceArgsForCall)
+}
+
+func (fake *FakeService) HasPrimaryIpv6PriceReturns(result1 bool, result2 error) {
+	fake.HasPrimaryIpv6PriceStub = nil
+	fake.hasPrimaryIpv6PriceReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeService) HasPrimaryIpv6PriceReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.HasPrimaryIpv6PriceStub = nil
+	if fake.hasPrimaryIpv6PriceReturnsOnCall == nil {
+		fake.hasPrimaryIpv6PriceReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.hasPrimaryIpv6PriceReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeService) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -1410,6 +2108,28 @@ func (fake *FakeService) Invocations() map[string][][]interface{} {
 	defer fake.setMetadataMutex.RUnlock()
 	fake.updateInstanceUserDataMutex.RLock()
 	defer fake.updateInstanceUserDataMutex.RUnlock()
+	fake.calculateResourcesMutex.RLock()
+	defer fake.calculateResourcesMutex.RUnlock()
+	fake.verifyCreateMutex.RLock()
+	defer fake.verifyCreateMutex.RUnlock()
+	fake.attachPrimaryIpv6AddressMutex.RLock()
+	defer fake.attachPrimaryIpv6AddressMutex.RUnlock()
+	fake.findStaticIpMutex.RLock()
+	defer fake.findStaticIpMutex.RUnlock()
+	fake.assignStaticIpsMutex.RLock()
+	defer fake.assignStaticIpsMutex.RUnlock()
+	fake.releaseStaticIpsMutex.RLock()
+	defer fake.releaseStaticIpsMutex.RUnlock()
+	fake.routeGlobalIpMutex.RLock()
+	defer fake.routeGlobalIpMutex.RUnlock()
+	fake.unrouteGlobalIpsMutex.RLock()
+	defer fake.unrouteGlobalIpsMutex.RUnlock()
+	fake.reserveStaticIpsMutex.RLock()
+	defer fake.reserveStaticIpsMutex.RUnlock()
+	fake.releaseReservedStaticIpsMutex.RLock()
+	defer fake.releaseReservedStaticIpsMutex.RUnlock()
+	fake.hasPrimaryIpv6PriceMutex.RLock()
+	defer fake.hasPrimaryIpv6PriceMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -4,6 +4,7 @@ import (
 	"github.com/softlayer/softlayer-go/datatypes"
 
 	"bosh-softlayer-cpi/registry"
+	bosl "bosh-softlayer-cpi/softlayer/client"
 )
 
 //go:generate counterfeiter -o fakes/fake_Instance_Service.go . Service
@@ -11,9 +12,13 @@ type Service interface {
 	AttachDisk(id int, diskID int) ([]byte, error)
 	AttachedDisks(id int) ([]string, error)
 	AttachEphemeralDisk(id int, diskSize int) error
+	CalculateResources(cpu int, memory int, ephemeralDiskSize int) (Resources, error)
 	Create(virtualGuest *datatypes.Virtual_Guest, enableVps bool, stemcellID int, sshKeys []int, userData *registry.SoftlayerUserData) (int, error)
+	VerifyCreate(virtualGuest *datatypes.Virtual_Guest) (bosl.OrderReport, error)
 	UpgradeInstance(id int, cpu int, memory int, network int, privateCPU bool, dedicatedHost bool) error
-	ConfigureNetworks(id int, networks Networks) (Networks, error)
+	ConfigureNetworks(id int, osCode string, networks Networks) (Networks, error)
+	AttachPrimaryIpv6Address(id int) error
+	HasPrimaryIpv6Price() (bool, error)
 	CleanUp(id int) error
 	CreateSshKey(label string, key string, fingerPrint string) (int, error)
 	Delete(id int, enableVps bool) error
@@ -25,6 +30,13 @@ type Service interface {
 	FindByPrimaryIp(ip string) (*datatypes.Virtual_Guest, error)
 	GetVlan(id int, mask string) (*datatypes.Network_Vlan, error)
 	GetSubnet(id int, mask string) (*datatypes.Network_Subnet, error)
+	FindStaticIp(ip string, vlanIDs []int, subnetIDs []int) (datatypes.Network_Subnet_IpAddress, error)
+	ReserveStaticIps(agentID string, ipAddresses []datatypes.Network_Subnet_IpAddress) error
+	AssignStaticIps(id int, ipAddresses []datatypes.Network_Subnet_IpAddress) error
+	ReleaseStaticIps(id int) error
+	ReleaseReservedStaticIps(agentID string) error
+	RouteGlobalIp(id int, ip string) error
+	UnrouteGlobalIps(id int) error
 	Reboot(id int) error
 	ReloadOS(id int, stemcellID int, sshKeyIds []int, hostname string, domain string, userData *registry.SoftlayerUserData) error
 	SetMetadata(id int, vmMetadata Metadata) error
@@ -2,15 +2,17 @@ package instance
 
 import (
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"net"
 	"regexp"
 
 	"bosh-softlayer-cpi/registry"
 )
 
 type NetworkCloudProperties struct {
-	VlanID              int  `json:"vlanId"`
-	SubnetID            int  `json:"subnetId"`
-	SourcePolicyRouting bool `json:"source_policy_routing,omitempty"`
+	VlanID              int      `json:"vlanId"`
+	SubnetID            int      `json:"subnetId"`
+	SourcePolicyRouting bool     `json:"source_policy_routing,omitempty"`
+	Routes              []string `json:"routes,omitempty"`
 }
 
 const maxTagLength = 63
@@ -86,12 +88,28 @@ func (n Network) AppendDNS(dns string) Network {
 
 func (n Network) IsVip() bool { return n.Type == "vip" }
 
+func (n Network) IsIpv6() bool {
+	ip := net.ParseIP(n.IP)
+	return ip != nil && ip.To4() == nil
+}
+
 func (n Network) IsManual() bool { return n.Type == "" || n.Type == "manual" }
 
 func (n Network) Validate() error {
 	switch {
 	case n.IsVip():
-		return bosherr.Errorf("Network type '%s' not supported", n.Type)
+		if net.ParseIP(n.IP).To4() == nil {
+			return bosherr.Errorf("Network type '%s' needs the IPv4 address of a global ip", n.Type)
+		}
+		return nil
+
+	case len(n.CloudProperties.Routes) > 0:
+		for _, destination := range n.CloudProperties.Routes {
+			if _, _, err := net.ParseCIDR(destination); err != nil {
+				return bosherr.Errorf("Route destination '%s' is not a CIDR", destination)
+			}
+		}
+		return nil
 
 	default:
 		return nil
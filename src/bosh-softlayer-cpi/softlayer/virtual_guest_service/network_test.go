@@ -217,7 +217,7 @@ var _ = Describe("Networks", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		It("Return error when validate single vip network", func() {
+		It("Return nil when validate single vip network", func() {
 			network = Network{
 				Type:    "vip",
 				IP:      "10.10.10.10",
@@ -231,9 +231,27 @@ var _ = Describe("Networks", func() {
 				},
 			}
 
+			err := network.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Return error when validate vip network without an IPv4 address", func() {
+			network = Network{
+				Type:    "vip",
+				IP:      "2607:f0d0:1:3::10",
+				Gateway: "fake-network-gateway",
+				Netmask: "fake-network-netmask",
+				DNS:     []string{"fake-network-dns"},
+				Default: []string{"fake-network-default"},
+				CloudProperties: NetworkCloudProperties{
+					VlanID:              42345678,
+					SourcePolicyRouting: true,
+				},
+			}
+
 			err := network.Validate()
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("Network type 'vip' not supported"))
+			Expect(err.Error()).To(ContainSubstring("Network type 'vip' needs the IPv4 address of a global ip"))
 		})
 	})
 })
@@ -56,7 +56,7 @@ var _ = Describe("Networks", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		It("Return error when validate single vip network", func() {
+		It("Return nil when validate single vip network", func() {
 			networks = Networks{
 				"fake-network-name": Network{
 					Type:    "vip",
@@ -72,9 +72,29 @@ var _ = Describe("Networks", func() {
 				},
 			}
 
+			err := networks.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Return error when validate vip network without an IPv4 address", func() {
+			networks = Networks{
+				"fake-network-name": Network{
+					Type:    "vip",
+					IP:      "2607:f0d0:1:3::10",
+					Gateway: "fake-network-gateway",
+					Netmask: "fake-network-netmask",
+					DNS:     []string{"fake-network-dns"},
+					Default: []string{"fake-network-default"},
+					CloudProperties: NetworkCloudProperties{
+						VlanID:              42345678,
+						SourcePolicyRouting: true,
+					},
+				},
+			}
+
 			err := networks.Validate()
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("Network type 'vip' not supported"))
+			Expect(err.Error()).To(ContainSubstring("Network type 'vip' needs the IPv4 address of a global ip"))
 		})
 	})
 
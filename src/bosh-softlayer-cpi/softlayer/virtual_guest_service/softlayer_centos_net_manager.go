@@ -0,0 +1,83 @@
+package instance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+
+	"bosh-softlayer-cpi/registry"
+)
+
+// Softlayer_Centos_Net configures the networks of CentOS and RHEL guests as Softlayer_Ubuntu_Net does,
+// but for the routes of alias interfaces such as eth0:1: network-scripts ignore a route-eth0:1 file,
+// so their routes go on the parent interface.
+type Softlayer_Centos_Net struct {
+	Softlayer_Ubuntu_Net
+}
+
+func (c *Softlayer_Centos_Net) FinalizedNetworkDefinitions(networkComponents datatypes.Virtual_Guest, networks Networks, componentByNetwork map[string]datatypes.Virtual_Guest_Network_Component) (Networks, error) {
+	finalized, err := c.Softlayer_Ubuntu_Net.FinalizedNetworkDefinitions(networkComponents, networks, componentByNetwork)
+	if err != nil {
+		return finalized, err
+	}
+
+	names := make([]string, 0, len(finalized))
+	for name := range finalized {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// The IPv4 network configuring each interface itself, which holds the routes of its aliases
+	parents := map[string]string{}
+	for _, name := range names {
+		nw := finalized[name]
+		if _, ok := parents[nw.Alias]; ok || nw.IsIpv6() || strings.Contains(nw.Alias, ":") {
+			continue
+		}
+		parents[nw.Alias] = name
+	}
+
+	for _, name := range names {
+		nw := finalized[name]
+		separator := strings.Index(nw.Alias, ":")
+		if separator < 0 || len(nw.Routes) == 0 {
+			continue
+		}
+
+		parentInterface := nw.Alias[:separator]
+		parentName, ok := parents[parentInterface]
+		if !ok {
+			return networks, fmt.Errorf("Routing network `%s`: no network configures interface `%s` of alias `%s`", name, parentInterface, nw.Alias)
+		}
+
+		parent := finalized[parentName]
+		parent.Routes = appendRoutes(parent.Routes, nw.Routes)
+		finalized[parentName] = parent
+
+		nw.Routes = nil
+		finalized[name] = nw
+	}
+
+	return finalized, nil
+}
+
+// appendRoutes appends the routes of extra to routes, leaving out the ones already there
+func appendRoutes(routes registry.Routes, extra registry.Routes) registry.Routes {
+	merged := append(registry.Routes{}, routes...)
+	for _, route := range extra {
+		found := false
+		for _, existing := range merged {
+			if existing == route {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, route)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,112 @@
+package instance_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/sl"
+
+	"bosh-softlayer-cpi/registry"
+	. "bosh-softlayer-cpi/softlayer/virtual_guest_service"
+	fakesVirtualGustService "bosh-softlayer-cpi/softlayer/virtual_guest_service/fakes"
+)
+
+var _ = Describe("Softlayer_Centos_Net", func() {
+	Describe("Call NewNetManager", func() {
+		It("Pick the CentOS net manager for CentOS and RHEL os codes", func() {
+			Expect(NewNetManager("CENTOS_7_64", nil, nil)).To(BeAssignableToTypeOf(&Softlayer_Centos_Net{}))
+			Expect(NewNetManager("REDHAT_7_64", nil, nil)).To(BeAssignableToTypeOf(&Softlayer_Centos_Net{}))
+		})
+
+		It("Pick the Ubuntu net manager for other or unknown os codes", func() {
+			Expect(NewNetManager("UBUNTU_16_64", nil, nil)).To(BeAssignableToTypeOf(&Softlayer_Ubuntu_Net{}))
+			Expect(NewNetManager("", nil, nil)).To(BeAssignableToTypeOf(&Softlayer_Ubuntu_Net{}))
+		})
+	})
+
+	Describe("Call FinalizedNetworkDefinitions", func() {
+		var (
+			linkNamer          *fakesVirtualGustService.FakeLinkNamer
+			net                *Softlayer_Centos_Net
+			networkComponents  datatypes.Virtual_Guest
+			networks           Networks
+			componentByNetwork map[string]datatypes.Virtual_Guest_Network_Component
+		)
+
+		BeforeEach(func() {
+			linkNamer = &fakesVirtualGustService.FakeLinkNamer{}
+			linkNamer.NameReturns("eth0:1", nil)
+			net = &Softlayer_Centos_Net{
+				Softlayer_Ubuntu_Net: Softlayer_Ubuntu_Net{
+					LinkNamer:     linkNamer,
+					PrivateRoutes: []string{"10.0.0.0/8"},
+				},
+			}
+
+			component := datatypes.Virtual_Guest_Network_Component{
+				IpAddressBindings: []datatypes.Virtual_Guest_Network_Component_IpAddress{
+					{
+						Type: sl.String("PRIMARY"),
+						IpAddress: &datatypes.Network_Subnet_IpAddress{
+							IpAddress: sl.String("10.10.10.10"),
+							Subnet: &datatypes.Network_Subnet{
+								Netmask: sl.String("255.255.255.192"),
+								Gateway: sl.String("10.10.10.1"),
+							},
+						},
+					},
+				},
+				NetworkVlan: &datatypes.Network_Vlan{
+					Id: sl.Int(32345),
+				},
+				MacAddress: sl.String("fake-mac-addr"),
+				Name:       sl.String("eth"),
+				Port:       sl.Int(0),
+			}
+			networkComponents = datatypes.Virtual_Guest{
+				PrimaryBackendNetworkComponent: &component,
+			}
+			networks = Networks{
+				"fake-network1": Network{
+					Type: "dynamic",
+					IP:   "10.10.10.10",
+				},
+				"fake-network2": Network{
+					Type:    "manual",
+					IP:      "10.20.10.10",
+					Netmask: "255.255.255.0",
+					Gateway: "10.20.10.1",
+					CloudProperties: NetworkCloudProperties{
+						Routes: []string{"172.16.0.0/12"},
+					},
+				},
+			}
+			componentByNetwork = map[string]datatypes.Virtual_Guest_Network_Component{
+				"fake-network1": component,
+				"fake-network2": component,
+			}
+		})
+
+		It("Move the routes of alias interfaces to their parent interface", func() {
+			finalized, err := net.FinalizedNetworkDefinitions(networkComponents, networks, componentByNetwork)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(finalized["fake-network1"].Alias).To(Equal("eth0"))
+			Expect(finalized["fake-network1"].Routes).To(Equal(registry.Routes{
+				{Destination: "10.0.0.0", NetMask: "255.0.0.0", Gateway: "10.10.10.1"},
+				{Destination: "172.16.0.0", NetMask: "255.240.0.0", Gateway: "10.20.10.1"},
+			}))
+			Expect(finalized["fake-network2"].Alias).To(Equal("eth0:1"))
+			Expect(finalized["fake-network2"].Routes).To(BeEmpty())
+		})
+
+		It("Return error when no network configures the parent interface of an alias", func() {
+			delete(networks, "fake-network1")
+
+			_, err := net.FinalizedNetworkDefinitions(networkComponents, networks, componentByNetwork)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("no network configures interface `eth0` of alias `eth0:1`"))
+		})
+	})
+})
@@ -0,0 +1,34 @@
+package instance
+
+import (
+	"strings"
+
+	"github.com/softlayer/softlayer-go/datatypes"
+)
+
+// NetManager turns the networks of a virtual guest into the network settings of the agent
+type NetManager interface {
+	ComponentByNetworkName(components datatypes.Virtual_Guest, networks Networks) (map[string]datatypes.Virtual_Guest_Network_Component, error)
+	NormalizeVips(networkComponents datatypes.Virtual_Guest, networks Networks) (Networks, error)
+	NormalizeNetworkDefinitions(networks Networks, componentByNetwork map[string]datatypes.Virtual_Guest_Network_Component) (Networks, error)
+	NormalizeDynamics(networkComponents datatypes.Virtual_Guest, networks Networks) (Networks, error)
+	NormalizeIpv6(networkComponents datatypes.Virtual_Guest, networks Networks) (Networks, error)
+	FinalizedNetworkDefinitions(networkComponents datatypes.Virtual_Guest, networks Networks, componentByNetwork map[string]datatypes.Virtual_Guest_Network_Component) (Networks, error)
+}
+
+// NewNetManager picks the net manager of the os-code the stemcell of the virtual guest was imported
+// with. CentOS and RHEL codes such as CENTOS_7_64 or REDHAT_7_64 get their own, anything else, an
+// unknown code included, the Ubuntu one of the stock stemcells.
+func NewNetManager(osCode string, linkNamer LinkNamer, privateRoutes []string) NetManager {
+	ubuntu := Softlayer_Ubuntu_Net{
+		LinkNamer:     linkNamer,
+		PrivateRoutes: privateRoutes,
+	}
+
+	code := strings.ToUpper(osCode)
+	if strings.HasPrefix(code, "CENTOS") || strings.HasPrefix(code, "REDHAT") {
+		return &Softlayer_Centos_Net{Softlayer_Ubuntu_Net: ubuntu}
+	}
+
+	return &ubuntu
+}
@@ -3,20 +3,58 @@ package instance
 import (
 	"errors"
 	"fmt"
+	"net"
 
 	"bosh-softlayer-cpi/registry"
 	"github.com/softlayer/softlayer-go/datatypes"
 )
 
-func SoftlayerPrivateRoutes(gateway string) registry.Routes {
-	return []registry.Route{
-		{Destination: "10.0.0.0", NetMask: "255.0.0.0", Gateway: gateway},
-		{Destination: "161.26.0.0", NetMask: "255.255.0.0", Gateway: gateway},
+// StaticRoutes routes each of the destinations, given in CIDR notation, through gateway
+func StaticRoutes(destinations []string, gateway string) (registry.Routes, error) {
+	routes := registry.Routes{}
+	if len(destinations) > 0 && gateway == "" {
+		return routes, fmt.Errorf("routes %v need a gateway", destinations)
 	}
+
+	for _, destination := range destinations {
+		_, ipNet, err := net.ParseCIDR(destination)
+		if err != nil {
+			return routes, fmt.Errorf("route destination %q is not a CIDR", destination)
+		}
+
+		routes = append(routes, registry.Route{Destination: ipNet.IP.String(), NetMask: net.IP(ipNet.Mask).String(), Gateway: gateway})
+	}
+
+	return routes, nil
+}
+
+// MergeRoutes appends the destinations of extra to the ones of routes, leaving out duplicated networks
+func MergeRoutes(routes []string, extra []string) []string {
+	merged := []string{}
+	seen := map[string]bool{}
+
+	for _, destination := range append(append([]string{}, routes...), extra...) {
+		key := destination
+		if _, ipNet, err := net.ParseCIDR(destination); err == nil {
+			key = ipNet.String()
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, destination)
+	}
+
+	return merged
 }
 
+// GeneratedPublicIpv6Network is the network of the primary IPv6 address of the public component
+const GeneratedPublicIpv6Network = "generated-public-ipv6"
+
 type Softlayer_Ubuntu_Net struct {
 	LinkNamer LinkNamer
+	// Destinations routed through the backend gateway of the private network, next to its own routes
+	PrivateRoutes []string
 }
 
 func (u *Softlayer_Ubuntu_Net) NormalizeNetworkDefinitions(networks Networks, componentByNetwork map[string]datatypes.Virtual_Guest_Network_Component) (Networks, error) {
@@ -48,6 +86,9 @@ func (u *Softlayer_Ubuntu_Net) FinalizedNetworkDefinitions(networkComponents dat
 			return networks, fmt.Errorf("network not found: %q", name)
 		}
 
+		// Routes of the network itself, through its own gateway
+		routes := nw.CloudProperties.Routes
+		privatePrimary := false
 		for _, ipAddressBinding := range component.IpAddressBindings {
 			if *ipAddressBinding.Type == "PRIMARY" {
 				networkComponentIpAddress := ipAddressBinding.IpAddress
@@ -55,18 +96,41 @@ func (u *Softlayer_Ubuntu_Net) FinalizedNetworkDefinitions(networkComponents dat
 					nw.Netmask = *networkComponentIpAddress.Subnet.Netmask
 					nw.Gateway = *networkComponentIpAddress.Subnet.Gateway
 					nw.MAC = *component.MacAddress
-					if component.NetworkVlan.Id == networkComponents.PrimaryBackendNetworkComponent.NetworkVlan.Id {
-						nw.Routes = SoftlayerPrivateRoutes(*networkComponentIpAddress.Subnet.Gateway)
-						nw.Gateway = ""
+					if *component.NetworkVlan.Id == *networkComponents.PrimaryBackendNetworkComponent.NetworkVlan.Id {
+						routes = MergeRoutes(u.PrivateRoutes, routes)
+						privatePrimary = true
 					}
 				}
 			}
 		}
 
+		staticRoutes, err := StaticRoutes(routes, nw.Gateway)
+		if err != nil {
+			return networks, fmt.Errorf("Routing network `%s`: `%s`", name, err.Error())
+		}
+		if len(staticRoutes) > 0 {
+			nw.Routes = staticRoutes
+		}
+		if privatePrimary {
+			// The private network only reaches the routed destinations, the default route stays on the public one
+			nw.Gateway = ""
+		}
+
 		var alias string
-		var err error
 
 		alias = fmt.Sprintf("%s%d", *component.Name, *component.Port)
+		if nw.IsIpv6() {
+			// IPv6 addresses go on the interface itself. Only the primary one holds the default route,
+			// portable IPv6 subnets are routed to it.
+			nw.MAC = *component.MacAddress
+			if name != GeneratedPublicIpv6Network {
+				nw.Gateway = ""
+			}
+			nw.Alias = alias
+			finalized[name] = nw
+			continue
+		}
+
 		if nw.Type != "dynamic" {
 			alias, err = u.LinkNamer.Name(alias, name)
 			if err != nil {
@@ -130,6 +194,68 @@ func (u *Softlayer_Ubuntu_Net) NormalizeDynamics(networkComponents datatypes.Vir
 	return networks, nil
 }
 
+// NormalizeVips turns vip networks into manual networks on the public component, so that the agent
+// configures the global IPs routed to the virtual guest as aliases of its public interface
+func (u *Softlayer_Ubuntu_Net) NormalizeVips(networkComponents datatypes.Virtual_Guest, networks Networks) (Networks, error) {
+	for name, nw := range networks {
+		if !nw.IsVip() {
+			continue
+		}
+
+		public := networkComponents.PrimaryNetworkComponent
+		if public == nil || public.NetworkVlan == nil || public.NetworkVlan.Id == nil {
+			return nil, fmt.Errorf("vip network %q needs a public network component", name)
+		}
+
+		networks[name] = Network{
+			Type:    "manual",
+			IP:      nw.IP,
+			Netmask: "255.255.255.255",
+			DNS:     nw.DNS,
+			CloudProperties: NetworkCloudProperties{
+				VlanID: *public.NetworkVlan.Id,
+			},
+		}
+	}
+
+	return networks, nil
+}
+
+// NormalizeIpv6 adds a manual network for the primary IPv6 address of the public component, so that
+// the agent configures it next to the IPv4 address of the public network
+func (u *Softlayer_Ubuntu_Net) NormalizeIpv6(networkComponents datatypes.Virtual_Guest, networks Networks) (Networks, error) {
+	public := networkComponents.PrimaryNetworkComponent
+	if public == nil || public.NetworkVlan == nil || public.NetworkVlan.Id == nil || public.PrimaryVersion6IpAddressRecord == nil {
+		return networks, nil
+	}
+
+	record := public.PrimaryVersion6IpAddressRecord
+	if record.IpAddress == nil || record.Subnet == nil || record.Subnet.Cidr == nil {
+		return nil, errors.New("primary IPv6 address record lacks its address or subnet")
+	}
+
+	for _, nw := range networks {
+		if nw.IP == *record.IpAddress {
+			return networks, nil
+		}
+	}
+
+	ipv6 := Network{
+		Type:    "manual",
+		IP:      *record.IpAddress,
+		Netmask: net.IP(net.CIDRMask(*record.Subnet.Cidr, 128)).String(),
+		CloudProperties: NetworkCloudProperties{
+			VlanID: *public.NetworkVlan.Id,
+		},
+	}
+	if record.Subnet.Gateway != nil {
+		ipv6.Gateway = *record.Subnet.Gateway
+	}
+	networks[GeneratedPublicIpv6Network] = ipv6
+
+	return networks, nil
+}
+
 func (u *Softlayer_Ubuntu_Net) ComponentByNetworkName(components datatypes.Virtual_Guest, networks Networks) (map[string]datatypes.Virtual_Guest_Network_Component, error) {
 	componentByNetwork := map[string]datatypes.Virtual_Guest_Network_Component{}
 
@@ -38,29 +38,177 @@ var _ = Describe("Virtual Guest Service", func() {
 		}
 	})
 
-	Describe("Call SoftlayerPrivateRoutes", func() {
+	Describe("Call StaticRoutes", func() {
+		It("Generate routes successfully", func() {
+			routes, err := StaticRoutes([]string{"10.0.0.0/8", "161.26.0.0/16", "166.8.0.0/14"}, "fake-gateway")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routes).To(Equal(registry.Routes{
+				{Destination: "10.0.0.0", NetMask: "255.0.0.0", Gateway: "fake-gateway"},
+				{Destination: "161.26.0.0", NetMask: "255.255.0.0", Gateway: "fake-gateway"},
+				{Destination: "166.8.0.0", NetMask: "255.252.0.0", Gateway: "fake-gateway"},
+			}))
+		})
+
+		It("Return error when a destination is not a CIDR", func() {
+			_, err := StaticRoutes([]string{"192.168.10.0"}, "fake-gateway")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("is not a CIDR"))
+		})
+
+		It("Return error when there is no gateway", func() {
+			_, err := StaticRoutes([]string{"192.168.10.0/24"}, "")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("need a gateway"))
+		})
+	})
+
+	Describe("Call MergeRoutes", func() {
+		It("Append the extra destinations leaving out duplicated networks", func() {
+			Expect(MergeRoutes(
+				[]string{"10.0.0.0/8", "161.26.0.0/16"},
+				[]string{"10.1.2.3/8", "192.168.10.0/24"},
+			)).To(Equal([]string{"10.0.0.0/8", "161.26.0.0/16", "192.168.10.0/24"}))
+		})
+	})
+
+	Describe("Call ComponentByNetworkName", func() {
 		var (
-			gateway string
+			networkComponents datatypes.Virtual_Guest
+			networks          Networks
 		)
 
 		BeforeEach(func() {
-			gateway = "fake-gateway"
+			networkComponents = datatypes.Virtual_Guest{
+				PrimaryBackendNetworkComponent: &datatypes.Virtual_Guest_Network_Component{
+					Id:               sl.Int(32345678),
+					PrimaryIpAddress: sl.String("10.10.10.10"),
+					NetworkVlan: &datatypes.Network_Vlan{
+						Id: sl.Int(12345678),
+					},
+				},
+				PrimaryNetworkComponent: &datatypes.Virtual_Guest_Network_Component{
+					Id: sl.Int(22345678),
+					NetworkVlan: &datatypes.Network_Vlan{
+						Id: sl.Int(1234580),
+					},
+				},
+			}
+			networks = Networks{
+				"fake-network1": Network{
+					Type:            "manual",
+					CloudProperties: NetworkCloudProperties{VlanID: 12345679},
+				},
+			}
 		})
 
-		Context("Generate registry routes", func() {
-			It("Generate routes successfully", func() {
-				cli.DeleteInstanceFromVPSReturns(
-					nil,
-				)
+		It("Return error when the vlan is not native to the virtual guest", func() {
+			_, err := net.ComponentByNetworkName(networkComponents, networks)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("not associated with this virtual guest"))
+		})
+	})
 
-				expectRoutes := []registry.Route{
-					{Destination: "10.0.0.0", NetMask: "255.0.0.0", Gateway: gateway},
-					{Destination: "161.26.0.0", NetMask: "255.255.0.0", Gateway: gateway},
-				}
+	Describe("Call NormalizeVips", func() {
+		var (
+			networkComponents datatypes.Virtual_Guest
+			networks          Networks
+		)
 
-				routes := SoftlayerPrivateRoutes(gateway)
-				Expect(routes).To(BeEquivalentTo(expectRoutes))
-			})
+		BeforeEach(func() {
+			networkComponents = datatypes.Virtual_Guest{
+				PrimaryNetworkComponent: &datatypes.Virtual_Guest_Network_Component{
+					Id: sl.Int(22345678),
+					NetworkVlan: &datatypes.Network_Vlan{
+						Id: sl.Int(1234580),
+					},
+				},
+			}
+			networks = Networks{
+				"vip-network": Network{
+					Type: "vip",
+					IP:   "169.50.100.10",
+					DNS:  []string{"8.8.8.8"},
+				},
+			}
+		})
+
+		It("Turns vip networks into host manual networks on the public component", func() {
+			normalized, err := net.NormalizeVips(networkComponents, networks)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(normalized["vip-network"]).To(Equal(Network{
+				Type:    "manual",
+				IP:      "169.50.100.10",
+				Netmask: "255.255.255.255",
+				DNS:     []string{"8.8.8.8"},
+				CloudProperties: NetworkCloudProperties{
+					VlanID: 1234580,
+				},
+			}))
+		})
+
+		It("Return error without a public component", func() {
+			networkComponents.PrimaryNetworkComponent = nil
+
+			_, err := net.NormalizeVips(networkComponents, networks)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("vip network \"vip-network\" needs a public network component"))
+		})
+	})
+
+	Describe("Call NormalizeIpv6", func() {
+		var (
+			networkComponents datatypes.Virtual_Guest
+			networks          Networks
+		)
+
+		BeforeEach(func() {
+			networkComponents = datatypes.Virtual_Guest{
+				PrimaryNetworkComponent: &datatypes.Virtual_Guest_Network_Component{
+					Id: sl.Int(22345678),
+					NetworkVlan: &datatypes.Network_Vlan{
+						Id: sl.Int(1234580),
+					},
+					PrimaryVersion6IpAddressRecord: &datatypes.Network_Subnet_IpAddress{
+						IpAddress: sl.String("2607:f0d0:1:2::10"),
+						Subnet: &datatypes.Network_Subnet{
+							NetworkIdentifier: sl.String("2607:f0d0:1:2::"),
+							Cidr:              sl.Int(64),
+							Gateway:           sl.String("2607:f0d0:1:2::1"),
+						},
+					},
+				},
+			}
+			networks = Networks{}
+		})
+
+		It("Adds a manual network for the primary IPv6 address of the public component", func() {
+			normalized, err := net.NormalizeIpv6(networkComponents, networks)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(normalized[GeneratedPublicIpv6Network]).To(Equal(Network{
+				Type:    "manual",
+				IP:      "2607:f0d0:1:2::10",
+				Netmask: "ffff:ffff:ffff:ffff::",
+				Gateway: "2607:f0d0:1:2::1",
+				CloudProperties: NetworkCloudProperties{
+					VlanID: 1234580,
+				},
+			}))
+		})
+
+		It("Leaves networks untouched without a primary IPv6 address", func() {
+			networkComponents.PrimaryNetworkComponent.PrimaryVersion6IpAddressRecord = nil
+
+			normalized, err := net.NormalizeIpv6(networkComponents, networks)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(normalized).To(BeEmpty())
+		})
+
+		It("Return error when the primary IPv6 address record has no subnet", func() {
+			networkComponents.PrimaryNetworkComponent.PrimaryVersion6IpAddressRecord.Subnet = nil
+
+			_, err := net.NormalizeIpv6(networkComponents, networks)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("lacks its address or subnet"))
 		})
 	})
 
@@ -165,6 +313,37 @@ var _ = Describe("Virtual Guest Service", func() {
 				Expect(err).NotTo(HaveOccurred())
 			})
 
+			It("Route the private network and the networks with routes through their gateways", func() {
+				net.PrivateRoutes = []string{"10.0.0.0/8", "166.8.0.0/14"}
+				networks["fake-network1"] = Network{
+					Type: "dynamic",
+					IP:   "fake-ip-address1",
+					CloudProperties: NetworkCloudProperties{
+						Routes: []string{"192.168.10.0/24"},
+					},
+				}
+				networks["fake-network2"] = Network{
+					Type:    "manual",
+					IP:      "fake-ip-address2",
+					Gateway: "fake-gateway",
+					CloudProperties: NetworkCloudProperties{
+						Routes: []string{"172.16.0.0/12"},
+					},
+				}
+
+				finalized, err := net.FinalizedNetworkDefinitions(networkComponents, networks, componentByNetwork)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(finalized["fake-network1"].Gateway).To(BeEmpty())
+				Expect(finalized["fake-network1"].Routes).To(Equal(registry.Routes{
+					{Destination: "10.0.0.0", NetMask: "255.0.0.0", Gateway: "fake-gateway1"},
+					{Destination: "166.8.0.0", NetMask: "255.252.0.0", Gateway: "fake-gateway1"},
+					{Destination: "192.168.10.0", NetMask: "255.255.255.0", Gateway: "fake-gateway1"},
+				}))
+				Expect(finalized["fake-network2"].Routes).To(Equal(registry.Routes{
+					{Destination: "172.16.0.0", NetMask: "255.240.0.0", Gateway: "fake-gateway2"},
+				}))
+			})
+
 			It("Return error when network not found", func() {
 				componentByNetwork = map[string]datatypes.Virtual_Guest_Network_Component{
 					"fake-network3": {
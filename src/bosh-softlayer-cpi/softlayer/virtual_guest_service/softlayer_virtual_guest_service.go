@@ -5,6 +5,7 @@ import (
 
 	"bosh-softlayer-cpi/logger"
 	bosl "bosh-softlayer-cpi/softlayer/client"
+	boslconfig "bosh-softlayer-cpi/softlayer/config"
 )
 
 const rootUser = "root"
@@ -14,6 +15,7 @@ type SoftlayerVirtualGuestService struct {
 	softlayerClient bosl.Client
 	uuidGen         boshuuid.Generator
 	logger          logger.Logger
+	privateRoutes   []string
 }
 
 func NewSoftLayerVirtualGuestService(
@@ -25,9 +27,16 @@ func NewSoftLayerVirtualGuestService(
 		softlayerClient: softlayerClient,
 		uuidGen:         uuidGen,
 		logger:          logger,
+		privateRoutes:   boslconfig.DefaultPrivateRoutes,
 	}
 }
 
+// WithPrivateRoutes returns a copy of the service routing the given destinations through the backend gateway of VMs
+func (vg SoftlayerVirtualGuestService) WithPrivateRoutes(routes []string) SoftlayerVirtualGuestService {
+	vg.privateRoutes = routes
+	return vg
+}
+
 type Mount struct {
 	PartitionPath string
 	MountPoint    string
@@ -9,6 +9,7 @@ import (
 
 	"bosh-softlayer-cpi/api"
 	"bosh-softlayer-cpi/registry"
+	bosl "bosh-softlayer-cpi/softlayer/client"
 )
 
 func (vg SoftlayerVirtualGuestService) Create(virtualGuest *datatypes.Virtual_Guest, enableVps bool, stemcellID int, sshKeys []int, userData *registry.SoftlayerUserData) (int, error) {
@@ -29,6 +30,15 @@ func (vg SoftlayerVirtualGuestService) Create(virtualGuest *datatypes.Virtual_Gu
 	return *virtualGuest.Id, nil
 }
 
+func (vg SoftlayerVirtualGuestService) VerifyCreate(virtualGuest *datatypes.Virtual_Guest) (bosl.OrderReport, error) {
+	report, err := vg.softlayerClient.VerifyInstanceOrder(virtualGuest)
+	if err != nil {
+		return bosl.OrderReport{}, bosherr.WrapError(err, "Verifying VirtualGuest order")
+	}
+
+	return report, nil
+}
+
 func (vg SoftlayerVirtualGuestService) CleanUp(id int) error {
 	if err := vg.Delete(id, false); err != nil {
 		vg.logger.Debug(softlayerVirtualGuestServiceLogTag, "Failed cleaning up Softlayer VirtualGuest '%s': %d", id, err)
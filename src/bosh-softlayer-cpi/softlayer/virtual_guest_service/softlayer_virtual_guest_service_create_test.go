@@ -15,6 +15,7 @@ import (
 
 	cpiLog "bosh-softlayer-cpi/logger"
 	"bosh-softlayer-cpi/registry"
+	bosl "bosh-softlayer-cpi/softlayer/client"
 	fakeslclient "bosh-softlayer-cpi/softlayer/client/fakes"
 )
 
@@ -163,6 +164,33 @@ var _ = Describe("Virtual Guest Service", func() {
 		})
 	})
 
+	Describe("Call VerifyCreate", func() {
+		It("verifies the order without creating the instance", func() {
+			cli.VerifyInstanceOrderReturns(
+				bosl.OrderReport{Verified: true},
+				nil,
+			)
+
+			report, err := virtualGuestService.VerifyCreate(&datatypes.Virtual_Guest{Hostname: sl.String("fake-hostname")})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Verified).To(BeTrue())
+			Expect(cli.VerifyInstanceOrderCallCount()).To(Equal(1))
+			Expect(*cli.VerifyInstanceOrderArgsForCall(0).Hostname).To(Equal("fake-hostname"))
+			Expect(cli.CreateInstanceCallCount()).To(Equal(0))
+		})
+
+		It("returns error if softLayerClient VerifyInstanceOrder call returns error", func() {
+			cli.VerifyInstanceOrderReturns(
+				bosl.OrderReport{},
+				errors.New("fake-client-error"),
+			)
+
+			_, err := virtualGuestService.VerifyCreate(&datatypes.Virtual_Guest{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+		})
+	})
+
 	Describe("Call Cleanup", func() {
 		var (
 			vmID int
@@ -2,7 +2,6 @@ package instance
 
 import (
 	"fmt"
-	"time"
 
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 
@@ -41,7 +40,7 @@ func (vg SoftlayerVirtualGuestService) AttachDisk(id int, diskID int) ([]byte, e
 		}
 	}
 	if !alreadyAuthorized {
-		until := time.Now().Add(time.Duration(1) * time.Hour)
+		until := vg.softlayerClient.Clock().Now().Add(vg.softlayerClient.Timeouts().AuthorizeVolume.Duration)
 		_, err = vg.softlayerClient.AuthorizeHostToVolume(instance, diskID, until)
 		if err != nil {
 			return []byte{}, bosherr.WrapErrorf(err, "Authorizing vm with id '%d' to disk with id '%d'", id, diskID)
@@ -96,7 +95,7 @@ func (vg SoftlayerVirtualGuestService) DetachDisk(id int, diskID int) error {
 		return api.NewVMNotFoundError(strconv.Itoa(id))
 	}
 
-	until := time.Now().Add(time.Duration(1) * time.Hour)
+	until := vg.softlayerClient.Clock().Now().Add(vg.softlayerClient.Timeouts().AuthorizeVolume.Duration)
 	_, err = vg.softlayerClient.DeauthorizeHostToVolume(instance, diskID, until)
 	if err != nil {
 		return bosherr.WrapErrorf(err, "De-Authorizing vm with id '%d' to disk with id '%d'", id, diskID)
@@ -2,6 +2,7 @@ package instance_test
 
 import (
 	"errors"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -16,6 +17,7 @@ import (
 	cpiLog "bosh-softlayer-cpi/logger"
 	fakeslclient "bosh-softlayer-cpi/softlayer/client/fakes"
 	. "bosh-softlayer-cpi/softlayer/virtual_guest_service"
+	"bosh-softlayer-cpi/test_helpers"
 )
 
 var _ = Describe("Virtual Guest Service", func() {
@@ -30,6 +32,7 @@ var _ = Describe("Virtual Guest Service", func() {
 
 	BeforeEach(func() {
 		cli = &fakeslclient.FakeClient{}
+		cli.ClockReturns(test_helpers.NewFakeClock(time.Now()))
 		uuidGen = &fakeuuid.FakeGenerator{}
 		logger = cpiLog.NewLogger(boshlog.LevelDebug, "")
 		virtualGuestService = NewSoftLayerVirtualGuestService(cli, uuidGen, logger)
@@ -4,7 +4,6 @@ import (
 	"strconv"
 	"time"
 
-	"code.cloudfoundry.org/clock"
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 	boshretry "github.com/cloudfoundry/bosh-utils/retrystrategy"
 	"github.com/softlayer/softlayer-go/datatypes"
@@ -33,7 +32,7 @@ func (vg SoftlayerVirtualGuestService) Find(id int) (*datatypes.Virtual_Guest, e
 
 			return false, nil
 		})
-	timeService := clock.NewClock()
+	timeService := vg.softlayerClient.Clock()
 	timeoutRetryStrategy := boshretry.NewTimeoutRetryStrategy(1*time.Minute, 5*time.Second, execStmtRetryable, timeService, vg.logger.GetBoshLogger())
 	vg.logger.ChangeRetryStrategyLogTag(&timeoutRetryStrategy)
 
@@ -2,6 +2,7 @@ package instance_test
 
 import (
 	"errors"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -15,11 +16,13 @@ import (
 	cpiLog "bosh-softlayer-cpi/logger"
 	fakeslclient "bosh-softlayer-cpi/softlayer/client/fakes"
 	. "bosh-softlayer-cpi/softlayer/virtual_guest_service"
+	"bosh-softlayer-cpi/test_helpers"
 )
 
 var _ = Describe("Virtual Guest Service", func() {
 	var (
 		softLayerClient     *fakeslclient.FakeClient
+		fakeClock           *test_helpers.FakeClock
 		uuidGen             *fakeuuid.FakeGenerator
 		logger              cpiLog.Logger
 		virtualGuestService SoftlayerVirtualGuestService
@@ -27,6 +30,8 @@ var _ = Describe("Virtual Guest Service", func() {
 
 	BeforeEach(func() {
 		softLayerClient = &fakeslclient.FakeClient{}
+		fakeClock = test_helpers.NewFakeClock(time.Now())
+		softLayerClient.ClockReturns(fakeClock)
 		uuidGen = &fakeuuid.FakeGenerator{}
 		logger = cpiLog.NewLogger(boshlog.LevelDebug, "vitualGuestServiceSerial")
 		virtualGuestService = NewSoftLayerVirtualGuestService(softLayerClient, uuidGen, logger)
@@ -67,6 +72,7 @@ var _ = Describe("Virtual Guest Service", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("fake-client-error"))
 			Expect(softLayerClient.GetInstanceCallCount()).To(BeNumerically(">=", 2))
+			Expect(fakeClock.TotalSlept()).To(Equal(time.Minute))
 		})
 
 		It("Return error if softLayerClient GetInstance returns non-existing", func() {
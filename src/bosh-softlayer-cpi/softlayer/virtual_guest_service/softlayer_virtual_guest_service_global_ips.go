@@ -0,0 +1,82 @@
+package instance
+
+import (
+	"strconv"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	"bosh-softlayer-cpi/api"
+)
+
+// RouteGlobalIp routes a global IP of the account to the primary public address of the virtual guest
+func (vg SoftlayerVirtualGuestService) RouteGlobalIp(id int, ip string) error {
+	primaryIp, err := vg.primaryIpAddress(id)
+	if err != nil {
+		return err
+	}
+	if primaryIp == "" {
+		return bosherr.Errorf("Virtual guest '%d' has no public ip address to route global ip '%s' to", id, ip)
+	}
+
+	records, err := vg.softlayerClient.GetGlobalIpRecords(ip, "")
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Finding global ip '%s'", ip)
+	}
+	if len(records) == 0 {
+		return bosherr.Errorf("Global ip '%s' is not ordered in the account", ip)
+	}
+
+	record := records[0]
+	if record.DestinationIpAddress != nil && record.DestinationIpAddress.IpAddress != nil && *record.DestinationIpAddress.IpAddress == primaryIp {
+		return nil
+	}
+
+	vg.logger.Info(softlayerVirtualGuestServiceLogTag, "Routing global ip '%s' to virtual guest '%d' at '%s'", ip, id, primaryIp)
+	if err = vg.softlayerClient.RouteGlobalIp(*record.Id, primaryIp); err != nil {
+		return bosherr.WrapErrorf(err, "Routing global ip '%s' to virtual guest '%d'", ip, id)
+	}
+
+	return nil
+}
+
+// UnrouteGlobalIps unroutes the global IPs routed to the primary public address of the virtual guest
+func (vg SoftlayerVirtualGuestService) UnrouteGlobalIps(id int) error {
+	primaryIp, err := vg.primaryIpAddress(id)
+	if err != nil {
+		return err
+	}
+	if primaryIp == "" {
+		return nil
+	}
+
+	records, err := vg.softlayerClient.GetGlobalIpRecords("", primaryIp)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Finding global ips routed to virtual guest '%d'", id)
+	}
+
+	for _, record := range records {
+		vg.logger.Info(softlayerVirtualGuestServiceLogTag, "Unrouting global ip '%s' from virtual guest '%d'", *record.IpAddress.IpAddress, id)
+		if err = vg.softlayerClient.UnrouteGlobalIp(*record.Id); err != nil {
+			return bosherr.WrapErrorf(err, "Unrouting global ip '%s' from virtual guest '%d'", *record.IpAddress.IpAddress, id)
+		}
+	}
+
+	return nil
+}
+
+func (vg SoftlayerVirtualGuestService) primaryIpAddress(id int) (string, error) {
+	instance, found, err := vg.softlayerClient.GetInstance(id, "id, primaryIpAddress")
+	if err != nil {
+		return "", bosherr.WrapErrorf(err, "Failed to find SoftLayer VirtualGuest with id '%d'", id)
+	}
+
+	if !found {
+		return "", api.NewVMNotFoundError(strconv.Itoa(id))
+	}
+
+	if instance.PrimaryIpAddress == nil {
+		return "", nil
+	}
+
+	return *instance.PrimaryIpAddress, nil
+}
@@ -0,0 +1,143 @@
+package instance_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	fakeuuid "github.com/cloudfoundry/bosh-utils/uuid/fakes"
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/sl"
+
+	"bosh-softlayer-cpi/api"
+	cpiLog "bosh-softlayer-cpi/logger"
+	fakeslclient "bosh-softlayer-cpi/softlayer/client/fakes"
+	. "bosh-softlayer-cpi/softlayer/virtual_guest_service"
+)
+
+var _ = Describe("Virtual Guest Service", func() {
+	var (
+		cli                 *fakeslclient.FakeClient
+		uuidGen             *fakeuuid.FakeGenerator
+		logger              cpiLog.Logger
+		virtualGuestService SoftlayerVirtualGuestService
+	)
+
+	BeforeEach(func() {
+		cli = &fakeslclient.FakeClient{}
+		uuidGen = &fakeuuid.FakeGenerator{}
+		logger = cpiLog.NewLogger(boshlog.LevelDebug, "")
+		virtualGuestService = NewSoftLayerVirtualGuestService(cli, uuidGen, logger)
+
+		cli.GetInstanceReturns(
+			&datatypes.Virtual_Guest{
+				Id:               sl.Int(12345678),
+				PrimaryIpAddress: sl.String("169.50.1.10"),
+			},
+			true,
+			nil,
+		)
+	})
+
+	Describe("Call RouteGlobalIp", func() {
+		BeforeEach(func() {
+			cli.GetGlobalIpRecordsReturns([]datatypes.Network_Subnet_IpAddress_Global{
+				{
+					Id:        sl.Int(1234567),
+					IpAddress: &datatypes.Network_Subnet_IpAddress{IpAddress: sl.String("169.50.100.10")},
+				},
+			}, nil)
+		})
+
+		It("Route the global ip to the primary ip address of the virtual guest", func() {
+			err := virtualGuestService.RouteGlobalIp(12345678, "169.50.100.10")
+			Expect(err).NotTo(HaveOccurred())
+
+			ip, destinationIp := cli.GetGlobalIpRecordsArgsForCall(0)
+			Expect(ip).To(Equal("169.50.100.10"))
+			Expect(destinationIp).To(BeEmpty())
+			Expect(cli.RouteGlobalIpCallCount()).To(Equal(1))
+			id, destinationIp := cli.RouteGlobalIpArgsForCall(0)
+			Expect(id).To(Equal(1234567))
+			Expect(destinationIp).To(Equal("169.50.1.10"))
+		})
+
+		It("Skip the global ip already routed to the virtual guest", func() {
+			cli.GetGlobalIpRecordsReturns([]datatypes.Network_Subnet_IpAddress_Global{
+				{
+					Id:                   sl.Int(1234567),
+					IpAddress:            &datatypes.Network_Subnet_IpAddress{IpAddress: sl.String("169.50.100.10")},
+					DestinationIpAddress: &datatypes.Network_Subnet_IpAddress{IpAddress: sl.String("169.50.1.10")},
+				},
+			}, nil)
+
+			err := virtualGuestService.RouteGlobalIp(12345678, "169.50.100.10")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cli.RouteGlobalIpCallCount()).To(Equal(0))
+		})
+
+		It("Return error when the global ip is not ordered", func() {
+			cli.GetGlobalIpRecordsReturns([]datatypes.Network_Subnet_IpAddress_Global{}, nil)
+
+			err := virtualGuestService.RouteGlobalIp(12345678, "169.50.100.10")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Global ip '169.50.100.10' is not ordered in the account"))
+		})
+
+		It("Return error when the virtual guest has no public ip address", func() {
+			cli.GetInstanceReturns(&datatypes.Virtual_Guest{Id: sl.Int(12345678)}, true, nil)
+
+			err := virtualGuestService.RouteGlobalIp(12345678, "169.50.100.10")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("has no public ip address"))
+			Expect(cli.GetGlobalIpRecordsCallCount()).To(Equal(0))
+		})
+
+		It("Return error if client call RouteGlobalIp returns an error", func() {
+			cli.RouteGlobalIpReturns(errors.New("fake-client-error"))
+
+			err := virtualGuestService.RouteGlobalIp(12345678, "169.50.100.10")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+		})
+	})
+
+	Describe("Call UnrouteGlobalIps", func() {
+		It("Unroute the global ips routed to the virtual guest", func() {
+			cli.GetGlobalIpRecordsReturns([]datatypes.Network_Subnet_IpAddress_Global{
+				{
+					Id:        sl.Int(1234567),
+					IpAddress: &datatypes.Network_Subnet_IpAddress{IpAddress: sl.String("169.50.100.10")},
+				},
+			}, nil)
+
+			err := virtualGuestService.UnrouteGlobalIps(12345678)
+			Expect(err).NotTo(HaveOccurred())
+
+			ip, destinationIp := cli.GetGlobalIpRecordsArgsForCall(0)
+			Expect(ip).To(BeEmpty())
+			Expect(destinationIp).To(Equal("169.50.1.10"))
+			Expect(cli.UnrouteGlobalIpArgsForCall(0)).To(Equal(1234567))
+		})
+
+		It("Return error if the virtual guest is not found", func() {
+			cli.GetInstanceReturns(nil, false, nil)
+
+			err := virtualGuestService.UnrouteGlobalIps(12345678)
+			Expect(err).To(HaveOccurred())
+			_, ok := err.(api.CloudError)
+			Expect(ok).To(BeTrue())
+		})
+
+		It("Return error if client call GetGlobalIpRecords returns an error", func() {
+			cli.GetGlobalIpRecordsReturns(nil, errors.New("fake-client-error"))
+
+			err := virtualGuestService.UnrouteGlobalIps(12345678)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Finding global ips routed to virtual guest '12345678'"))
+			Expect(cli.UnrouteGlobalIpCallCount()).To(Equal(0))
+		})
+	})
+})
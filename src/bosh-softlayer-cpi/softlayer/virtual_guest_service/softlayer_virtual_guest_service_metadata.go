@@ -5,7 +5,6 @@ import (
 	"strconv"
 	"time"
 
-	"code.cloudfoundry.org/clock"
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 	boshretry "github.com/cloudfoundry/bosh-utils/retrystrategy"
 
@@ -34,7 +33,7 @@ func (vg SoftlayerVirtualGuestService) SetMetadata(id int, vmMetadata Metadata)
 
 			return false, nil
 		})
-	timeService := clock.NewClock()
+	timeService := vg.softlayerClient.Clock()
 	timeoutRetryStrategy := boshretry.NewTimeoutRetryStrategy(1*time.Minute, 5*time.Second, execStmtRetryable, timeService, vg.logger.GetBoshLogger())
 	vg.logger.ChangeRetryStrategyLogTag(&timeoutRetryStrategy)
 
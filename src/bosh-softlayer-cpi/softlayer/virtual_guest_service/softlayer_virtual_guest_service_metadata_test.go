@@ -3,6 +3,7 @@ package instance_test
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -13,6 +14,7 @@ import (
 	cpiLog "bosh-softlayer-cpi/logger"
 	fakeslclient "bosh-softlayer-cpi/softlayer/client/fakes"
 	. "bosh-softlayer-cpi/softlayer/virtual_guest_service"
+	"bosh-softlayer-cpi/test_helpers"
 )
 
 var _ = Describe("Virtual Guest Service", func() {
@@ -25,6 +27,7 @@ var _ = Describe("Virtual Guest Service", func() {
 
 	BeforeEach(func() {
 		cli = &fakeslclient.FakeClient{}
+		cli.ClockReturns(test_helpers.NewFakeClock(time.Now()))
 		uuidGen = &fakeuuid.FakeGenerator{}
 		logger = cpiLog.NewLogger(boshlog.LevelDebug, "")
 		virtualGuestService = NewSoftLayerVirtualGuestService(cli, uuidGen, logger)
@@ -5,12 +5,16 @@ import (
 
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/sl"
 
 	"bosh-softlayer-cpi/api"
 	boslc "bosh-softlayer-cpi/softlayer/client"
 )
 
-func (vg SoftlayerVirtualGuestService) ConfigureNetworks(id int, networks Networks) (Networks, error) {
+// ConfigureNetworks normalizes the networks of the virtual guest following the conventions of osCode,
+// the os-code of its stemcell. The operating system SoftLayer reports for the guest stands in when
+// the stemcell records none.
+func (vg SoftlayerVirtualGuestService) ConfigureNetworks(id int, osCode string, networks Networks) (Networks, error) {
 	vg.logger.Debug(softlayerVirtualGuestServiceLogTag, "Finding Softlayer Virtual Guest '%d' ", id)
 	instance, found, err := vg.softlayerClient.GetInstance(id, boslc.INSTANCE_NETWORK_COMPONENTS_MASK)
 	if err != nil {
@@ -21,36 +25,47 @@ func (vg SoftlayerVirtualGuestService) ConfigureNetworks(id int, networks Networ
 		return networks, api.NewVMNotFoundError(strconv.Itoa(id))
 	}
 
-	vg.logger.Info(softlayerVirtualGuestServiceLogTag, "Configuring networks: %+v", networks)
-	ubuntu := Softlayer_Ubuntu_Net{
-		LinkNamer: NewIndexedNamer(networks),
+	if osCode == "" {
+		osCode = sl.Get(instance.OperatingSystemReferenceCode, "").(string)
 	}
+	vg.logger.Info(softlayerVirtualGuestServiceLogTag, "Configuring networks for os code '%s': %+v", osCode, networks)
+	netManager := NewNetManager(osCode, NewIndexedNamer(networks), vg.privateRoutes)
 
-	componentByNetwork, err := ubuntu.ComponentByNetworkName(*instance, networks)
+	networks, err = netManager.NormalizeVips(*instance, networks)
+	if err != nil {
+		return networks, bosherr.WrapError(err, "Normalizing vip networks definitions")
+	}
+
+	componentByNetwork, err := netManager.ComponentByNetworkName(*instance, networks)
 	if err != nil {
 		return networks, bosherr.WrapError(err, "Mapping network component and name")
 	}
 	vg.logger.Debug(softlayerVirtualGuestServiceLogTag, "ComponentByNetworkName: %+v", componentByNetwork)
 
-	networks, err = ubuntu.NormalizeNetworkDefinitions(networks, componentByNetwork)
+	networks, err = netManager.NormalizeNetworkDefinitions(networks, componentByNetwork)
 	if err != nil {
 		return networks, bosherr.WrapError(err, "Normalizing network definitions")
 	}
 	vg.logger.Debug(softlayerVirtualGuestServiceLogTag, "Normalized networks: %+v", networks)
 
-	networks, err = ubuntu.NormalizeDynamics(*instance, networks)
+	networks, err = netManager.NormalizeDynamics(*instance, networks)
 	if err != nil {
 		return networks, bosherr.WrapError(err, "Normalizing dynamic networks definitions")
 	}
 	vg.logger.Debug(softlayerVirtualGuestServiceLogTag, "Normalized Dynamics: %+v", networks)
 
-	componentByNetwork, err = ubuntu.ComponentByNetworkName(*instance, networks)
+	networks, err = netManager.NormalizeIpv6(*instance, networks)
+	if err != nil {
+		return networks, bosherr.WrapError(err, "Normalizing IPv6 networks definitions")
+	}
+
+	componentByNetwork, err = netManager.ComponentByNetworkName(*instance, networks)
 	if err != nil {
 		return networks, bosherr.WrapError(err, "Mapping network component and name")
 	}
 	vg.logger.Debug(softlayerVirtualGuestServiceLogTag, "ComponentByNetworkName: %+v", componentByNetwork)
 
-	networks, err = ubuntu.FinalizedNetworkDefinitions(*instance, networks, componentByNetwork)
+	networks, err = netManager.FinalizedNetworkDefinitions(*instance, networks, componentByNetwork)
 	if err != nil {
 		return networks, bosherr.WrapError(err, "Finalizing networks definitions")
 	}
@@ -59,6 +74,20 @@ func (vg SoftlayerVirtualGuestService) ConfigureNetworks(id int, networks Networ
 	return networks, nil
 }
 
+func (vg SoftlayerVirtualGuestService) AttachPrimaryIpv6Address(id int) error {
+	return vg.softlayerClient.AttachPrimaryIpv6Address(id)
+}
+
+// HasPrimaryIpv6Price tells whether primary IPv6 addresses can be ordered for virtual guests at all
+func (vg SoftlayerVirtualGuestService) HasPrimaryIpv6Price() (bool, error) {
+	_, found, err := vg.softlayerClient.FindVirtualServerItemPrice(boslc.PRIMARY_IPV6_CATEGORY_CODE)
+	if err != nil {
+		return false, bosherr.WrapError(err, "Finding primary IPv6 address price")
+	}
+
+	return found, nil
+}
+
 func (vg SoftlayerVirtualGuestService) GetVlan(vlanID int, mask string) (*datatypes.Network_Vlan, error) {
 	vlan, found, err := vg.softlayerClient.GetVlan(vlanID, mask)
 	if err != nil {
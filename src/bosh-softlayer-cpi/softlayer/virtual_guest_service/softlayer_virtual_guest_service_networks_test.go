@@ -1,7 +1,10 @@
 package instance_test
 
 import (
+	"encoding/json"
 	"errors"
+	"os"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -12,9 +15,11 @@ import (
 	"github.com/softlayer/softlayer-go/sl"
 
 	cpiLog "bosh-softlayer-cpi/logger"
+	"bosh-softlayer-cpi/registry"
 	"bosh-softlayer-cpi/softlayer/client"
 	fakeslclient "bosh-softlayer-cpi/softlayer/client/fakes"
 	. "bosh-softlayer-cpi/softlayer/virtual_guest_service"
+	"bosh-softlayer-cpi/test_helpers"
 )
 
 var _ = Describe("Virtual Guest Service", func() {
@@ -84,11 +89,114 @@ var _ = Describe("Virtual Guest Service", func() {
 		})
 
 		It("Configure networks successfully", func() {
-			_, err := virtualGuestService.ConfigureNetworks(vmID, networks)
+			_, err := virtualGuestService.ConfigureNetworks(vmID, "", networks)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(cli.GetInstanceCallCount()).To(Equal(1))
 		})
 
+		It("Configure the routes of alias interfaces on their parent interface for CentOS stemcells", func() {
+			cli.GetInstanceReturns(
+				&datatypes.Virtual_Guest{
+					OperatingSystemReferenceCode: sl.String("UBUNTU_16_64"),
+					PrimaryNetworkComponent:      &datatypes.Virtual_Guest_Network_Component{},
+					PrimaryBackendNetworkComponent: &datatypes.Virtual_Guest_Network_Component{
+						Id:   sl.Int(32345678),
+						Name: sl.String("eth"),
+						Port: sl.Int(0),
+						NetworkVlan: &datatypes.Network_Vlan{
+							Id: sl.Int(32345),
+						},
+						PrimaryIpAddress: sl.String("10.10.10.10"),
+						MacAddress:       sl.String("fake-mac-addr2"),
+					},
+				},
+				true,
+				nil,
+			)
+			networks = Networks{
+				"fake-network2": Network{
+					CloudProperties: NetworkCloudProperties{
+						VlanID: 32345,
+					},
+					Type: "dynamic",
+				},
+				"fake-network3": Network{
+					IP:      "10.20.10.10",
+					Netmask: "255.255.255.0",
+					Gateway: "10.20.10.1",
+					CloudProperties: NetworkCloudProperties{
+						VlanID: 32345,
+						Routes: []string{"172.16.0.0/12"},
+					},
+					Type: "manual",
+				},
+			}
+
+			configured, err := virtualGuestService.ConfigureNetworks(vmID, "CENTOS_7_64", networks)
+			Expect(err).NotTo(HaveOccurred())
+			_, mask := cli.GetInstanceArgsForCall(0)
+			Expect(mask).To(Equal(client.INSTANCE_NETWORK_COMPONENTS_MASK))
+
+			Expect(configured["fake-network2"].Alias).To(Equal("eth0"))
+			Expect(configured["fake-network2"].Routes).To(Equal(registry.Routes{
+				{Destination: "172.16.0.0", NetMask: "255.240.0.0", Gateway: "10.20.10.1"},
+			}))
+			Expect(configured["fake-network3"].Alias).To(HavePrefix("eth0:"))
+			Expect(configured["fake-network3"].Routes).To(BeEmpty())
+		})
+
+		It("Configure the networks of a CentOS guest as SoftLayer returns it when the stemcell records no os code", func() {
+			wd, err := os.Getwd()
+			Expect(err).NotTo(HaveOccurred())
+			fixture, err := test_helpers.ReadJsonTestFixtures(filepath.Join(wd, "..", ".."), "services", "SoftLayer_Virtual_Guest_getObject.json")
+			Expect(err).NotTo(HaveOccurred())
+
+			guest := &datatypes.Virtual_Guest{}
+			Expect(json.Unmarshal(fixture, guest)).To(Succeed())
+			Expect(*guest.OperatingSystemReferenceCode).To(Equal("CENTOS_7_64"))
+			cli.GetInstanceReturns(guest, true, nil)
+
+			networks = Networks{
+				"private": Network{
+					CloudProperties: NetworkCloudProperties{
+						VlanID: 1421725,
+					},
+					Type: "dynamic",
+				},
+				"public": Network{
+					CloudProperties: NetworkCloudProperties{
+						VlanID: 1421723,
+					},
+					Type: "dynamic",
+				},
+				"services": Network{
+					IP:      "10.127.95.10",
+					Netmask: "255.255.255.192",
+					Gateway: "10.127.95.1",
+					CloudProperties: NetworkCloudProperties{
+						VlanID: 1421725,
+						Routes: []string{"192.168.100.0/24"},
+					},
+					Type: "manual",
+				},
+			}
+
+			configured, err := virtualGuestService.ConfigureNetworks(vmID, "", networks)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(configured["private"].Alias).To(Equal("eth0"))
+			Expect(configured["private"].IP).To(Equal("10.127.94.175"))
+			Expect(configured["private"].MAC).To(Equal("06:a5:4c:8c:cc:7c"))
+			Expect(configured["private"].Routes).To(Equal(registry.Routes{
+				{Destination: "192.168.100.0", NetMask: "255.255.255.0", Gateway: "10.127.95.1"},
+			}))
+			Expect(configured["public"].Alias).To(Equal("eth1"))
+			Expect(configured["public"].IP).To(Equal("159.8.71.16"))
+			Expect(configured["public"].Routes).To(BeEmpty())
+			Expect(configured["services"].Alias).To(HavePrefix("eth0:"))
+			Expect(configured["services"].Routes).To(BeEmpty())
+		})
+
 		It("Return error if softLayerClient ConfigureNetworks call returns an error", func() {
 			cli.GetInstanceReturns(
 				&datatypes.Virtual_Guest{},
@@ -96,7 +204,7 @@ var _ = Describe("Virtual Guest Service", func() {
 				errors.New("fake-client-error"),
 			)
 
-			_, err := virtualGuestService.ConfigureNetworks(vmID, networks)
+			_, err := virtualGuestService.ConfigureNetworks(vmID, "", networks)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("fake-client-error"))
 			Expect(cli.GetInstanceCallCount()).To(Equal(1))
@@ -109,7 +217,7 @@ var _ = Describe("Virtual Guest Service", func() {
 				nil,
 			)
 
-			_, err := virtualGuestService.ConfigureNetworks(vmID, networks)
+			_, err := virtualGuestService.ConfigureNetworks(vmID, "", networks)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("not found"))
 			Expect(cli.GetInstanceCallCount()).To(Equal(1))
@@ -132,7 +240,7 @@ var _ = Describe("Virtual Guest Service", func() {
 				},
 			}
 
-			_, err := virtualGuestService.ConfigureNetworks(vmID, networks)
+			_, err := virtualGuestService.ConfigureNetworks(vmID, "", networks)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("Mapping network component and name"))
 			Expect(cli.GetInstanceCallCount()).To(Equal(1))
@@ -154,7 +262,7 @@ var _ = Describe("Virtual Guest Service", func() {
 				},
 			}
 
-			_, err := virtualGuestService.ConfigureNetworks(vmID, networks)
+			_, err := virtualGuestService.ConfigureNetworks(vmID, "", networks)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("Normalizing network definitions"))
 			Expect(cli.GetInstanceCallCount()).To(Equal(1))
@@ -175,7 +283,7 @@ var _ = Describe("Virtual Guest Service", func() {
 				},
 			}
 
-			_, err := virtualGuestService.ConfigureNetworks(vmID, networks)
+			_, err := virtualGuestService.ConfigureNetworks(vmID, "", networks)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(cli.GetInstanceCallCount()).To(Equal(1))
 		})
@@ -196,13 +304,114 @@ var _ = Describe("Virtual Guest Service", func() {
 				},
 			}
 
-			_, err := virtualGuestService.ConfigureNetworks(vmID, networks)
+			_, err := virtualGuestService.ConfigureNetworks(vmID, "", networks)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("Normalizing dynamic networks definitions"))
 			Expect(cli.GetInstanceCallCount()).To(Equal(1))
 		})
 	})
 
+	Describe("Call ConfigureNetworks with a primary IPv6 address", func() {
+		var (
+			vmID     int
+			networks Networks
+		)
+
+		BeforeEach(func() {
+			vmID = 12345678
+			networks = Networks{
+				"fake-network1": Network{
+					CloudProperties: NetworkCloudProperties{
+						VlanID: 32345,
+					},
+					Type: "dynamic",
+				},
+			}
+
+			cli.GetInstanceReturns(
+				&datatypes.Virtual_Guest{
+					PrimaryNetworkComponent: &datatypes.Virtual_Guest_Network_Component{
+						Id:   sl.Int(22345678),
+						Name: sl.String("eth"),
+						Port: sl.Int(1),
+						NetworkVlan: &datatypes.Network_Vlan{
+							Id: sl.Int(22345),
+						},
+						PrimaryIpAddress: sl.String("169.50.10.10"),
+						MacAddress:       sl.String("fake-public-mac-addr"),
+						PrimaryVersion6IpAddressRecord: &datatypes.Network_Subnet_IpAddress{
+							IpAddress: sl.String("2607:f0d0:1:2::10"),
+							Subnet: &datatypes.Network_Subnet{
+								NetworkIdentifier: sl.String("2607:f0d0:1:2::"),
+								Cidr:              sl.Int(64),
+								Gateway:           sl.String("2607:f0d0:1:2::1"),
+							},
+						},
+					},
+					PrimaryBackendNetworkComponent: &datatypes.Virtual_Guest_Network_Component{
+						Id:   sl.Int(32345678),
+						Name: sl.String("eth"),
+						Port: sl.Int(0),
+						NetworkVlan: &datatypes.Network_Vlan{
+							Id: sl.Int(32345),
+						},
+						PrimaryIpAddress: sl.String("10.10.10.10"),
+						MacAddress:       sl.String("fake-mac-addr"),
+					},
+				},
+				true,
+				nil,
+			)
+		})
+
+		It("configures the IPv6 address on the public interface next to its IPv4 address", func() {
+			configured, err := virtualGuestService.ConfigureNetworks(vmID, "", networks)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(configured["generated-public"].Alias).To(Equal("eth1"))
+			Expect(configured[GeneratedPublicIpv6Network]).To(Equal(Network{
+				Type:    "manual",
+				IP:      "2607:f0d0:1:2::10",
+				Netmask: "ffff:ffff:ffff:ffff::",
+				Gateway: "2607:f0d0:1:2::1",
+				MAC:     "fake-public-mac-addr",
+				Alias:   "eth1",
+				CloudProperties: NetworkCloudProperties{
+					VlanID: 22345,
+				},
+			}))
+		})
+	})
+
+	Describe("Call HasPrimaryIpv6Price", func() {
+		It("Find the primary IPv6 address price successfully", func() {
+			cli.FindVirtualServerItemPriceReturns(
+				&datatypes.Product_Item_Price{
+					Id: sl.Int(17129),
+				},
+				true,
+				nil,
+			)
+
+			found, err := virtualGuestService.HasPrimaryIpv6Price()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(cli.FindVirtualServerItemPriceArgsForCall(0)).To(Equal(client.PRIMARY_IPV6_CATEGORY_CODE))
+		})
+
+		It("Return error if softLayerClient FindVirtualServerItemPrice call returns an error", func() {
+			cli.FindVirtualServerItemPriceReturns(
+				&datatypes.Product_Item_Price{},
+				false,
+				errors.New("fake-client-error"),
+			)
+
+			_, err := virtualGuestService.HasPrimaryIpv6Price()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Finding primary IPv6 address price"))
+		})
+	})
+
 	Describe("Call GetVlan", func() {
 		var (
 			vlanID int
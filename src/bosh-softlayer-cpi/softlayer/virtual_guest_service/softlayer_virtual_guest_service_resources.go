@@ -0,0 +1,154 @@
+package instance
+
+import (
+	"sort"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/helpers/product"
+	"github.com/softlayer/softlayer-go/sl"
+
+	bosl "bosh-softlayer-cpi/softlayer/client"
+)
+
+// Resources are the SoftLayer offerings of a virtual server. Memory is in MB, ephemeral disk size in GB.
+type Resources struct {
+	FlavorKeyName     string
+	Cpu               int
+	Memory            int
+	EphemeralDiskSize int
+}
+
+type flavor struct {
+	keyName string
+	cpu     int
+	memory  int
+}
+
+// CalculateResources finds the smallest offering satisfying the requested cpu, memory (MB) and
+// ephemeral disk size (MB). A cpu/memory pair is preferred over a flavor.
+func (vg SoftlayerVirtualGuestService) CalculateResources(cpu int, memory int, ephemeralDiskSize int) (Resources, error) {
+	vg.logger.Debug(softlayerVirtualGuestServiceLogTag, "Calculating resources for cpu '%d', memory '%dMB' and ephemeral disk '%dMB'", cpu, memory, ephemeralDiskSize)
+
+	packageItems, err := vg.softlayerClient.GetVirtualServerPackageItems()
+	if err != nil {
+		return Resources{}, bosherr.WrapError(err, "Getting virtual server package items")
+	}
+
+	var resources Resources
+
+	cores := vg.capacities(packageItems, product.CPUCategoryCode)
+	rams := vg.capacities(packageItems, product.MemoryCategoryCode)
+	desiredCpu, cpuFound := vg.smallestCapacity(cores, cpu)
+	desiredRam, ramFound := vg.smallestCapacity(rams, divideRoundingUp(memory, 1024))
+	if cpuFound && ramFound {
+		resources.Cpu = desiredCpu
+		resources.Memory = desiredRam * 1024
+	} else {
+		presets, err := vg.softlayerClient.GetVirtualServerPackagePresets()
+		if err != nil {
+			return Resources{}, bosherr.WrapError(err, "Getting virtual server package presets")
+		}
+
+		desiredFlavor, found := vg.smallestFlavor(presets, cpu, memory)
+		if !found {
+			return Resources{}, bosherr.Errorf("Unable to find an offering with at least %d cpu and %dMB memory", cpu, memory)
+		}
+		resources.FlavorKeyName = desiredFlavor.keyName
+	}
+
+	if ephemeralDiskSize > 0 {
+		disks := vg.capacities(packageItems, bosl.EPHEMERAL_DISK_CATEGORY_CODE)
+		desiredDisk, found := vg.smallestCapacity(disks, divideRoundingUp(ephemeralDiskSize, 1024))
+		if !found {
+			return Resources{}, bosherr.Errorf("Unable to find an ephemeral disk of at least %dMB", ephemeralDiskSize)
+		}
+		resources.EphemeralDiskSize = desiredDisk
+	}
+
+	return resources, nil
+}
+
+// capacities returns the sorted capacities of the public items in a price category
+func (vg SoftlayerVirtualGuestService) capacities(packageItems []datatypes.Product_Item, categoryCode string) []int {
+	capacities := []int{}
+	for _, item := range packageItems {
+		if item.Capacity == nil || len(item.Prices) == 0 {
+			continue
+		}
+
+		keyName := sl.Get(item.KeyName, "").(string)
+		if strings.Contains(keyName, "PRIVATE") || strings.Contains(keyName, "DEDICATED") {
+			continue
+		}
+
+		for _, category := range item.Prices[0].Categories {
+			if sl.Get(category.CategoryCode, "").(string) == categoryCode {
+				capacities = append(capacities, int(*item.Capacity))
+				break
+			}
+		}
+	}
+	sort.Ints(capacities)
+
+	return capacities
+}
+
+func (vg SoftlayerVirtualGuestService) smallestCapacity(capacities []int, minimum int) (int, bool) {
+	for _, capacity := range capacities {
+		if capacity >= minimum {
+			return capacity, true
+		}
+	}
+
+	return 0, false
+}
+
+func (vg SoftlayerVirtualGuestService) smallestFlavor(presets []datatypes.Product_Package_Preset, cpu int, memory int) (flavor, bool) {
+	flavors := []flavor{}
+	for _, preset := range presets {
+		if preset.KeyName == nil {
+			continue
+		}
+
+		f := flavor{keyName: *preset.KeyName}
+		for _, price := range preset.Prices {
+			if price.Item == nil || price.Item.Capacity == nil {
+				continue
+			}
+			for _, category := range price.Categories {
+				switch sl.Get(category.CategoryCode, "").(string) {
+				case product.CPUCategoryCode:
+					f.cpu = int(*price.Item.Capacity)
+				case product.MemoryCategoryCode:
+					f.memory = int(*price.Item.Capacity) * 1024
+				}
+			}
+		}
+
+		if f.cpu >= cpu && f.memory >= memory {
+			flavors = append(flavors, f)
+		}
+	}
+
+	if len(flavors) == 0 {
+		return flavor{}, false
+	}
+
+	sort.Slice(flavors, func(i, j int) bool {
+		if flavors[i].cpu != flavors[j].cpu {
+			return flavors[i].cpu < flavors[j].cpu
+		}
+		if flavors[i].memory != flavors[j].memory {
+			return flavors[i].memory < flavors[j].memory
+		}
+		return flavors[i].keyName < flavors[j].keyName
+	})
+
+	return flavors[0], true
+}
+
+func divideRoundingUp(value int, divisor int) int {
+	return (value + divisor - 1) / divisor
+}
@@ -0,0 +1,134 @@
+package instance_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	fakeuuid "github.com/cloudfoundry/bosh-utils/uuid/fakes"
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/sl"
+
+	cpiLog "bosh-softlayer-cpi/logger"
+	fakeslclient "bosh-softlayer-cpi/softlayer/client/fakes"
+	. "bosh-softlayer-cpi/softlayer/virtual_guest_service"
+)
+
+var _ = Describe("Virtual Guest Service", func() {
+	var (
+		cli                 *fakeslclient.FakeClient
+		uuidGen             *fakeuuid.FakeGenerator
+		logger              cpiLog.Logger
+		virtualGuestService SoftlayerVirtualGuestService
+	)
+
+	newItem := func(keyName string, capacity float64, categoryCode string) datatypes.Product_Item {
+		return datatypes.Product_Item{
+			KeyName:  sl.String(keyName),
+			Capacity: sl.Float(capacity),
+			Prices: []datatypes.Product_Item_Price{
+				{
+					Categories: []datatypes.Product_Item_Category{
+						{CategoryCode: sl.String(categoryCode)},
+					},
+				},
+			},
+		}
+	}
+
+	newPresetPrice := func(capacity float64, categoryCode string) datatypes.Product_Item_Price {
+		return datatypes.Product_Item_Price{
+			Item: &datatypes.Product_Item{Capacity: sl.Float(capacity)},
+			Categories: []datatypes.Product_Item_Category{
+				{CategoryCode: sl.String(categoryCode)},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		cli = &fakeslclient.FakeClient{}
+		uuidGen = &fakeuuid.FakeGenerator{}
+		logger = cpiLog.NewLogger(boshlog.LevelDebug, "")
+		virtualGuestService = NewSoftLayerVirtualGuestService(cli, uuidGen, logger)
+
+		cli.GetVirtualServerPackageItemsReturns(
+			[]datatypes.Product_Item{
+				newItem("GUEST_CORES_1", 1, "guest_core"),
+				newItem("GUEST_CORES_4", 4, "guest_core"),
+				newItem("GUEST_CORES_2", 2, "guest_core"),
+				newItem("GUEST_PRIVATE_CORES_2", 2, "guest_core"),
+				newItem("RAM_2_GB", 2, "ram"),
+				newItem("RAM_4_GB", 4, "ram"),
+				newItem("RAM_8_GB", 8, "ram"),
+				newItem("GUEST_DISK_25_GB_SAN", 25, "guest_disk1"),
+				newItem("GUEST_DISK_100_GB_SAN", 100, "guest_disk1"),
+			},
+			nil,
+		)
+		cli.GetVirtualServerPackagePresetsReturns(
+			[]datatypes.Product_Package_Preset{
+				{
+					KeyName: sl.String("B1_16X64X25"),
+					Prices: []datatypes.Product_Item_Price{
+						newPresetPrice(16, "guest_core"),
+						newPresetPrice(64, "ram"),
+					},
+				},
+				{
+					KeyName: sl.String("B1_8X32X25"),
+					Prices: []datatypes.Product_Item_Price{
+						newPresetPrice(8, "guest_core"),
+						newPresetPrice(32, "ram"),
+					},
+				},
+			},
+			nil,
+		)
+	})
+
+	Describe("Call CalculateResources", func() {
+		It("Return the smallest cpu/memory pair and ephemeral disk satisfying the request", func() {
+			resources, err := virtualGuestService.CalculateResources(2, 3072, 30*1024)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resources).To(Equal(Resources{
+				Cpu:               2,
+				Memory:            4096,
+				EphemeralDiskSize: 100,
+			}))
+			Expect(cli.GetVirtualServerPackagePresetsCallCount()).To(Equal(0))
+		})
+
+		It("Return the smallest flavor when no cpu/memory pair satisfies the request", func() {
+			resources, err := virtualGuestService.CalculateResources(6, 16384, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resources).To(Equal(Resources{
+				FlavorKeyName: "B1_8X32X25",
+			}))
+		})
+
+		It("Return error if no offering satisfies the request", func() {
+			_, err := virtualGuestService.CalculateResources(32, 131072, 0)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Unable to find an offering"))
+		})
+
+		It("Return error if no ephemeral disk satisfies the request", func() {
+			_, err := virtualGuestService.CalculateResources(2, 2048, 200*1024)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Unable to find an ephemeral disk"))
+		})
+
+		It("Return error if softLayerClient GetVirtualServerPackageItems call returns an error", func() {
+			cli.GetVirtualServerPackageItemsReturns(
+				[]datatypes.Product_Item{},
+				errors.New("fake-client-error"),
+			)
+
+			_, err := virtualGuestService.CalculateResources(2, 2048, 0)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+		})
+	})
+})
@@ -0,0 +1,221 @@
+package instance
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"github.com/softlayer/softlayer-go/datatypes"
+
+	boslc "bosh-softlayer-cpi/softlayer/client"
+)
+
+// Static IPs of manual networks are recorded in the note of their SoftLayer IP address, first as
+// reserved for the agent of the VM being ordered, e.g. "bosh-softlayer-cpi: reserved for agent
+// fake-agent-id", then as assigned to the virtual guest, e.g. "bosh-softlayer-cpi: assigned to
+// virtual guest 12345678". Notes without the prefix are left to operators and never cleared.
+const (
+	staticIpNotePrefix         = "bosh-softlayer-cpi: "
+	staticIpAssignedNotePrefix = staticIpNotePrefix + "assigned to virtual guest "
+	staticIpReservedNotePrefix = staticIpNotePrefix + "reserved for agent "
+)
+
+// Portable subnets are the ones routed to a VLAN rather than to a single network component
+var portableSubnetTypes = map[string]bool{
+	"SECONDARY_ON_VLAN": true,
+	"SUBNET_ON_VLAN":    true,
+}
+
+func StaticIpNote(id int) string {
+	return fmt.Sprintf("%s%d", staticIpAssignedNotePrefix, id)
+}
+
+func StaticIpReservationNote(agentID string) string {
+	return staticIpReservedNotePrefix + agentID
+}
+
+// IsPortableSubnet tells the subnets whose addresses can be assigned to virtual guests as static IPs
+func IsPortableSubnet(subnet datatypes.Network_Subnet) bool {
+	return subnet.SubnetType != nil && portableSubnetTypes[*subnet.SubnetType]
+}
+
+// StaticIpOwner returns the virtual guest a static IP was assigned to by the CPI, if any
+func StaticIpOwner(ipAddress datatypes.Network_Subnet_IpAddress) (int, bool) {
+	if ipAddress.Note == nil || !strings.HasPrefix(*ipAddress.Note, staticIpAssignedNotePrefix) {
+		return 0, false
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(*ipAddress.Note, staticIpAssignedNotePrefix))
+	if err != nil {
+		return 0, false
+	}
+
+	return id, true
+}
+
+// IsStaticIpInUse tells the IPs the CPI has reserved or assigned to a virtual guest
+func IsStaticIpInUse(ipAddress datatypes.Network_Subnet_IpAddress) bool {
+	return ipAddress.Note != nil && strings.HasPrefix(*ipAddress.Note, staticIpNotePrefix)
+}
+
+// FindStaticIp looks the ip up in the portable subnets of the given subnets or vlans, and makes sure
+// it is free to be assigned to a virtual guest
+func (vg SoftlayerVirtualGuestService) FindStaticIp(ip string, vlanIDs []int, subnetIDs []int) (datatypes.Network_Subnet_IpAddress, error) {
+	address := net.ParseIP(ip)
+	if address == nil {
+		return datatypes.Network_Subnet_IpAddress{}, bosherr.Errorf("Parsing ip '%s'", ip)
+	}
+
+	candidates := subnetIDs
+	for _, vlanID := range vlanIDs {
+		vlan, err := vg.GetVlan(vlanID, boslc.NETWORK_STATIC_IP_VLAN_MASK)
+		if err != nil {
+			return datatypes.Network_Subnet_IpAddress{}, err
+		}
+
+		for _, subnet := range vlan.Subnets {
+			if subnet.Id != nil && subnetContains(subnet, address) {
+				candidates = append(candidates, *subnet.Id)
+			}
+		}
+	}
+
+	for _, subnetID := range candidates {
+		subnet, err := vg.GetSubnet(subnetID, boslc.NETWORK_STATIC_IP_MASK)
+		if err != nil {
+			return datatypes.Network_Subnet_IpAddress{}, err
+		}
+
+		if !subnetContains(*subnet, address) {
+			continue
+		}
+
+		if !IsPortableSubnet(*subnet) {
+			return datatypes.Network_Subnet_IpAddress{}, bosherr.Errorf("Ip '%s' is in subnet '%d' which is not a portable subnet", ip, subnetID)
+		}
+
+		for _, ipAddress := range subnet.IpAddresses {
+			if ipAddress.IpAddress == nil || !net.ParseIP(*ipAddress.IpAddress).Equal(address) {
+				continue
+			}
+
+			if IsTrue(ipAddress.IsNetwork) || IsTrue(ipAddress.IsGateway) || IsTrue(ipAddress.IsBroadcast) || IsTrue(ipAddress.IsReserved) {
+				return datatypes.Network_Subnet_IpAddress{}, bosherr.Errorf("Ip '%s' is reserved in subnet '%d'", ip, subnetID)
+			}
+
+			if owner, assigned := StaticIpOwner(ipAddress); assigned {
+				return datatypes.Network_Subnet_IpAddress{}, bosherr.Errorf("Ip '%s' is already assigned to virtual guest '%d'", ip, owner)
+			}
+
+			if IsStaticIpInUse(ipAddress) {
+				return datatypes.Network_Subnet_IpAddress{}, bosherr.Errorf("Ip '%s' is already reserved for agent '%s'", ip, strings.TrimPrefix(*ipAddress.Note, staticIpReservedNotePrefix))
+			}
+
+			return ipAddress, nil
+		}
+
+		return datatypes.Network_Subnet_IpAddress{}, bosherr.Errorf("Ip '%s' has no ip address record in subnet '%d'", ip, subnetID)
+	}
+
+	return datatypes.Network_Subnet_IpAddress{}, bosherr.Errorf("Ip '%s' is not in a portable subnet of vlans %v or subnets %v", ip, vlanIDs, subnetIDs)
+}
+
+// ReserveStaticIps records the agent of a VM about to be ordered in the note of its static IP
+// addresses, so that other VMs cannot take them meanwhile. SoftLayer cannot edit a note only if it
+// is unchanged, so each note is read back after it is written and the reservation fails when a
+// concurrent one replaced it. The notes already written are cleared again when one of them fails.
+func (vg SoftlayerVirtualGuestService) ReserveStaticIps(agentID string, ipAddresses []datatypes.Network_Subnet_IpAddress) error {
+	note := StaticIpReservationNote(agentID)
+	for i, ipAddress := range ipAddresses {
+		vg.logger.Debug(softlayerVirtualGuestServiceLogTag, "Reserving static ip '%s' for agent '%s'", *ipAddress.IpAddress, agentID)
+		if err := vg.softlayerClient.SetIpAddressNote(*ipAddress.Id, note); err != nil {
+			vg.unreserveStaticIps(agentID, ipAddresses[:i])
+			return bosherr.WrapErrorf(err, "Reserving static ip '%s' for agent '%s'", *ipAddress.IpAddress, agentID)
+		}
+
+		current, err := vg.softlayerClient.GetIpAddressNote(*ipAddress.Id)
+		if err != nil {
+			vg.unreserveStaticIps(agentID, ipAddresses[:i+1])
+			return bosherr.WrapErrorf(err, "Checking reservation of static ip '%s' for agent '%s'", *ipAddress.IpAddress, agentID)
+		}
+
+		if current != note {
+			vg.unreserveStaticIps(agentID, ipAddresses[:i])
+			return bosherr.Errorf("Reserving static ip '%s' for agent '%s': a concurrent reservation replaced the note with '%s'", *ipAddress.IpAddress, agentID, current)
+		}
+	}
+
+	return nil
+}
+
+// unreserveStaticIps clears the notes written by a failed reservation. Failures are only logged, so
+// that the reservation error is the one returned
+func (vg SoftlayerVirtualGuestService) unreserveStaticIps(agentID string, ipAddresses []datatypes.Network_Subnet_IpAddress) {
+	for _, ipAddress := range ipAddresses {
+		if err := vg.softlayerClient.SetIpAddressNote(*ipAddress.Id, ""); err != nil {
+			vg.logger.Warn(softlayerVirtualGuestServiceLogTag, "Clearing reservation of static ip '%s' for agent '%s': %s", *ipAddress.IpAddress, agentID, err)
+		}
+	}
+}
+
+// AssignStaticIps records the virtual guest in the note of its static IP addresses
+func (vg SoftlayerVirtualGuestService) AssignStaticIps(id int, ipAddresses []datatypes.Network_Subnet_IpAddress) error {
+	for _, ipAddress := range ipAddresses {
+		vg.logger.Debug(softlayerVirtualGuestServiceLogTag, "Assigning static ip '%s' to virtual guest '%d'", *ipAddress.IpAddress, id)
+		if err := vg.softlayerClient.SetIpAddressNote(*ipAddress.Id, StaticIpNote(id)); err != nil {
+			return bosherr.WrapErrorf(err, "Assigning static ip '%s' to virtual guest '%d'", *ipAddress.IpAddress, id)
+		}
+	}
+
+	return nil
+}
+
+// ReleaseStaticIps clears the note of the static IP addresses assigned to the virtual guest
+func (vg SoftlayerVirtualGuestService) ReleaseStaticIps(id int) error {
+	return vg.releaseStaticIps(StaticIpNote(id), fmt.Sprintf("virtual guest '%d'", id))
+}
+
+// ReleaseReservedStaticIps clears the note of the static IP addresses reserved for the agent
+func (vg SoftlayerVirtualGuestService) ReleaseReservedStaticIps(agentID string) error {
+	return vg.releaseStaticIps(StaticIpReservationNote(agentID), fmt.Sprintf("agent '%s'", agentID))
+}
+
+func (vg SoftlayerVirtualGuestService) releaseStaticIps(note string, owner string) error {
+	ipAddresses, err := vg.softlayerClient.GetIpAddressesByNote(note)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Finding static ips of %s", owner)
+	}
+
+	for _, ipAddress := range ipAddresses {
+		if !IsStaticIpInUse(ipAddress) {
+			continue
+		}
+
+		vg.logger.Debug(softlayerVirtualGuestServiceLogTag, "Releasing static ip '%s' of %s", *ipAddress.IpAddress, owner)
+		if err := vg.softlayerClient.SetIpAddressNote(*ipAddress.Id, ""); err != nil {
+			return bosherr.WrapErrorf(err, "Releasing static ip '%s' of %s", *ipAddress.IpAddress, owner)
+		}
+	}
+
+	return nil
+}
+
+func subnetContains(subnet datatypes.Network_Subnet, address net.IP) bool {
+	if subnet.NetworkIdentifier == nil || subnet.Cidr == nil {
+		return false
+	}
+
+	_, ipNet, err := net.ParseCIDR(fmt.Sprintf("%s/%d", *subnet.NetworkIdentifier, *subnet.Cidr))
+	if err != nil {
+		return false
+	}
+
+	return ipNet.Contains(address)
+}
+
+// IsTrue tells whether an optional SoftLayer flag is set
+func IsTrue(flag *bool) bool {
+	return flag != nil && *flag
+}
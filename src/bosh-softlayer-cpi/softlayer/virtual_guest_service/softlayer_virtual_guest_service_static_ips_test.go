@@ -0,0 +1,273 @@
+package instance_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	fakeuuid "github.com/cloudfoundry/bosh-utils/uuid/fakes"
+	"github.com/softlayer/softlayer-go/datatypes"
+	"github.com/softlayer/softlayer-go/sl"
+
+	cpiLog "bosh-softlayer-cpi/logger"
+	"bosh-softlayer-cpi/softlayer/client"
+	fakeslclient "bosh-softlayer-cpi/softlayer/client/fakes"
+	. "bosh-softlayer-cpi/softlayer/virtual_guest_service"
+)
+
+var _ = Describe("Virtual Guest Service", func() {
+	var (
+		cli                 *fakeslclient.FakeClient
+		uuidGen             *fakeuuid.FakeGenerator
+		logger              cpiLog.Logger
+		virtualGuestService SoftlayerVirtualGuestService
+	)
+
+	BeforeEach(func() {
+		cli = &fakeslclient.FakeClient{}
+		uuidGen = &fakeuuid.FakeGenerator{}
+		logger = cpiLog.NewLogger(boshlog.LevelDebug, "")
+		virtualGuestService = NewSoftLayerVirtualGuestService(cli, uuidGen, logger)
+	})
+
+	Describe("Call FindStaticIp", func() {
+		var subnet *datatypes.Network_Subnet
+
+		BeforeEach(func() {
+			subnet = &datatypes.Network_Subnet{
+				Id:                sl.Int(514990),
+				SubnetType:        sl.String("SECONDARY_ON_VLAN"),
+				NetworkIdentifier: sl.String("10.40.207.168"),
+				Cidr:              sl.Int(29),
+				IpAddresses: []datatypes.Network_Subnet_IpAddress{
+					{Id: sl.Int(10776596), IpAddress: sl.String("10.40.207.168"), IsNetwork: sl.Bool(true)},
+					{Id: sl.Int(10776597), IpAddress: sl.String("10.40.207.170")},
+					{Id: sl.Int(10776598), IpAddress: sl.String("10.40.207.171"), Note: sl.String(StaticIpNote(22345678))},
+					{Id: sl.Int(10776599), IpAddress: sl.String("10.40.207.172"), Note: sl.String(StaticIpReservationNote("fake-agent-id"))},
+					{Id: sl.Int(10776600), IpAddress: sl.String("10.40.207.173"), Note: sl.String("kept by the network team")},
+				},
+			}
+			cli.GetVlanReturns(
+				&datatypes.Network_Vlan{
+					Id: sl.Int(1292653),
+					Subnets: []datatypes.Network_Subnet{
+						{Id: sl.Int(514980), NetworkIdentifier: sl.String("10.40.207.0"), Cidr: sl.Int(26)},
+						{Id: sl.Int(514990), NetworkIdentifier: sl.String("10.40.207.168"), Cidr: sl.Int(29)},
+					},
+				},
+				true,
+				nil,
+			)
+			cli.GetSubnetReturns(subnet, true, nil)
+		})
+
+		It("Find the ip address in the portable subnets of the vlans", func() {
+			ipAddress, err := virtualGuestService.FindStaticIp("10.40.207.170", []int{1292653}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*ipAddress.Id).To(Equal(10776597))
+
+			_, mask := cli.GetVlanArgsForCall(0)
+			Expect(mask).To(Equal(client.NETWORK_STATIC_IP_VLAN_MASK))
+			subnetID, mask := cli.GetSubnetArgsForCall(0)
+			Expect(subnetID).To(Equal(514990))
+			Expect(mask).To(Equal(client.NETWORK_STATIC_IP_MASK))
+		})
+
+		It("Find the ip address in the given subnets", func() {
+			ipAddress, err := virtualGuestService.FindStaticIp("10.40.207.170", nil, []int{514990})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*ipAddress.Id).To(Equal(10776597))
+			Expect(cli.GetVlanCallCount()).To(Equal(0))
+		})
+
+		It("Return error when the ip is not in a subnet of the vlans", func() {
+			_, err := virtualGuestService.FindStaticIp("10.40.208.10", []int{1292653}, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("is not in a portable subnet"))
+			Expect(cli.GetSubnetCallCount()).To(Equal(0))
+		})
+
+		It("Return error when the subnet is not portable", func() {
+			subnet.SubnetType = sl.String("PRIMARY")
+
+			_, err := virtualGuestService.FindStaticIp("10.40.207.170", []int{1292653}, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("which is not a portable subnet"))
+		})
+
+		It("Return error when the ip is reserved", func() {
+			_, err := virtualGuestService.FindStaticIp("10.40.207.168", []int{1292653}, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Ip '10.40.207.168' is reserved"))
+		})
+
+		It("Return error when the ip is already assigned to another virtual guest", func() {
+			_, err := virtualGuestService.FindStaticIp("10.40.207.171", []int{1292653}, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("already assigned to virtual guest '22345678'"))
+		})
+
+		It("Return error when the ip is already reserved for another agent", func() {
+			_, err := virtualGuestService.FindStaticIp("10.40.207.172", []int{1292653}, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("already reserved for agent 'fake-agent-id'"))
+		})
+
+		It("Find the ip address when its note was not written by the CPI", func() {
+			ipAddress, err := virtualGuestService.FindStaticIp("10.40.207.173", []int{1292653}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*ipAddress.Id).To(Equal(10776600))
+		})
+
+		It("Return error if client call GetSubnet returns an error", func() {
+			cli.GetSubnetReturns(&datatypes.Network_Subnet{}, false, errors.New("fake-client-error"))
+
+			_, err := virtualGuestService.FindStaticIp("10.40.207.170", []int{1292653}, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-client-error"))
+		})
+	})
+
+	Describe("Call ReserveStaticIps", func() {
+		ipAddresses := []datatypes.Network_Subnet_IpAddress{
+			{Id: sl.Int(10776597), IpAddress: sl.String("10.40.207.170")},
+			{Id: sl.Int(10776598), IpAddress: sl.String("10.40.207.171")},
+		}
+
+		BeforeEach(func() {
+			cli.GetIpAddressNoteReturns(StaticIpReservationNote("fake-agent-id"), nil)
+		})
+
+		It("Record the agent in the note of the ip addresses", func() {
+			err := virtualGuestService.ReserveStaticIps("fake-agent-id", ipAddresses)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cli.SetIpAddressNoteCallCount()).To(Equal(2))
+			id, note := cli.SetIpAddressNoteArgsForCall(1)
+			Expect(id).To(Equal(10776598))
+			Expect(note).To(Equal(StaticIpReservationNote("fake-agent-id")))
+			Expect(cli.GetIpAddressNoteCallCount()).To(Equal(2))
+			Expect(cli.GetIpAddressNoteArgsForCall(1)).To(Equal(10776598))
+		})
+
+		It("Return error when a concurrent reservation replaced the note", func() {
+			cli.GetIpAddressNoteReturnsOnCall(1, StaticIpReservationNote("other-agent-id"), nil)
+
+			err := virtualGuestService.ReserveStaticIps("fake-agent-id", ipAddresses)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("a concurrent reservation replaced the note with 'bosh-softlayer-cpi: reserved for agent other-agent-id'"))
+			Expect(cli.SetIpAddressNoteCallCount()).To(Equal(3))
+			id, note := cli.SetIpAddressNoteArgsForCall(2)
+			Expect(id).To(Equal(10776597))
+			Expect(note).To(BeEmpty())
+		})
+
+		It("Clear the notes written if client call GetIpAddressNote returns an error", func() {
+			cli.GetIpAddressNoteReturnsOnCall(1, "", errors.New("fake-client-error"))
+
+			err := virtualGuestService.ReserveStaticIps("fake-agent-id", ipAddresses)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Checking reservation of static ip '10.40.207.171'"))
+			Expect(cli.SetIpAddressNoteCallCount()).To(Equal(4))
+			id, note := cli.SetIpAddressNoteArgsForCall(3)
+			Expect(id).To(Equal(10776598))
+			Expect(note).To(BeEmpty())
+		})
+
+		It("Clear the notes already written if client call SetIpAddressNote returns an error", func() {
+			cli.SetIpAddressNoteReturnsOnCall(1, errors.New("fake-client-error"))
+
+			err := virtualGuestService.ReserveStaticIps("fake-agent-id", ipAddresses)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Reserving static ip '10.40.207.171'"))
+			Expect(cli.SetIpAddressNoteCallCount()).To(Equal(3))
+			id, note := cli.SetIpAddressNoteArgsForCall(2)
+			Expect(id).To(Equal(10776597))
+			Expect(note).To(BeEmpty())
+		})
+
+		It("Return the reservation error when clearing the notes fails too", func() {
+			cli.SetIpAddressNoteReturnsOnCall(1, errors.New("fake-client-error"))
+			cli.SetIpAddressNoteReturnsOnCall(2, errors.New("fake-clear-error"))
+
+			err := virtualGuestService.ReserveStaticIps("fake-agent-id", ipAddresses)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Reserving static ip '10.40.207.171'"))
+			Expect(err.Error()).NotTo(ContainSubstring("fake-clear-error"))
+			Expect(cli.SetIpAddressNoteCallCount()).To(Equal(3))
+		})
+	})
+
+	Describe("Call AssignStaticIps", func() {
+		It("Record the virtual guest in the note of the ip addresses", func() {
+			err := virtualGuestService.AssignStaticIps(12345678, []datatypes.Network_Subnet_IpAddress{
+				{Id: sl.Int(10776597), IpAddress: sl.String("10.40.207.170")},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cli.SetIpAddressNoteCallCount()).To(Equal(1))
+			id, note := cli.SetIpAddressNoteArgsForCall(0)
+			Expect(id).To(Equal(10776597))
+			Expect(note).To(Equal(StaticIpNote(12345678)))
+		})
+
+		It("Return error if client call SetIpAddressNote returns an error", func() {
+			cli.SetIpAddressNoteReturns(errors.New("fake-client-error"))
+
+			err := virtualGuestService.AssignStaticIps(12345678, []datatypes.Network_Subnet_IpAddress{
+				{Id: sl.Int(10776597), IpAddress: sl.String("10.40.207.170")},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Assigning static ip '10.40.207.170'"))
+		})
+	})
+
+	Describe("Call ReleaseStaticIps", func() {
+		It("Clear the note of the ip addresses assigned to the virtual guest", func() {
+			cli.GetIpAddressesByNoteReturns([]datatypes.Network_Subnet_IpAddress{
+				{Id: sl.Int(10776597), IpAddress: sl.String("10.40.207.170"), Note: sl.String(StaticIpNote(12345678))},
+			}, nil)
+
+			err := virtualGuestService.ReleaseStaticIps(12345678)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cli.GetIpAddressesByNoteArgsForCall(0)).To(Equal(StaticIpNote(12345678)))
+			id, note := cli.SetIpAddressNoteArgsForCall(0)
+			Expect(id).To(Equal(10776597))
+			Expect(note).To(BeEmpty())
+		})
+
+		It("Leave the notes that were not written by the CPI", func() {
+			cli.GetIpAddressesByNoteReturns([]datatypes.Network_Subnet_IpAddress{
+				{Id: sl.Int(10776597), IpAddress: sl.String("10.40.207.170"), Note: sl.String("kept by the network team")},
+			}, nil)
+
+			err := virtualGuestService.ReleaseStaticIps(12345678)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cli.SetIpAddressNoteCallCount()).To(Equal(0))
+		})
+
+		It("Return error if client call GetIpAddressesByNote returns an error", func() {
+			cli.GetIpAddressesByNoteReturns(nil, errors.New("fake-client-error"))
+
+			err := virtualGuestService.ReleaseStaticIps(12345678)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Finding static ips of virtual guest '12345678'"))
+			Expect(cli.SetIpAddressNoteCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("Call ReleaseReservedStaticIps", func() {
+		It("Clear the note of the ip addresses reserved for the agent", func() {
+			cli.GetIpAddressesByNoteReturns([]datatypes.Network_Subnet_IpAddress{
+				{Id: sl.Int(10776597), IpAddress: sl.String("10.40.207.170"), Note: sl.String(StaticIpReservationNote("fake-agent-id"))},
+			}, nil)
+
+			err := virtualGuestService.ReleaseReservedStaticIps("fake-agent-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cli.GetIpAddressesByNoteArgsForCall(0)).To(Equal(StaticIpReservationNote("fake-agent-id")))
+			id, note := cli.SetIpAddressNoteArgsForCall(0)
+			Expect(id).To(Equal(10776597))
+			Expect(note).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,102 @@
+package test_helpers
+
+import (
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+)
+
+// FakeClock is a clock.Clock whose time only moves when something sleeps on it,
+// so wait loops run through their timeouts instantly while recording each sleep.
+type FakeClock struct {
+	mutex      sync.Mutex
+	now        time.Time
+	sleptTimes []time.Duration
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *FakeClock) Sleep(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.sleptTimes = append(c.sleptTimes, d)
+	if d > 0 {
+		c.now = c.now.Add(d)
+	}
+}
+
+// Increment moves the clock forward without recording a sleep
+func (c *FakeClock) Increment(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func (c *FakeClock) SleptTimes() []time.Duration {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]time.Duration{}, c.sleptTimes...)
+}
+
+// TotalSlept is the sum of all the recorded sleeps
+func (c *FakeClock) TotalSlept() time.Duration {
+	var total time.Duration
+	for _, d := range c.SleptTimes() {
+		total += d
+	}
+
+	return total
+}
+
+// After, NewTimer and NewTicker fire once, right after advancing the clock
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) clock.Timer {
+	c.Sleep(d)
+	return firedTimer{fired(c.Now())}
+}
+
+func (c *FakeClock) NewTicker(d time.Duration) clock.Ticker {
+	c.Sleep(d)
+	return firedTicker{fired(c.Now())}
+}
+
+func fired(now time.Time) chan time.Time {
+	c := make(chan time.Time, 1)
+	c <- now
+	return c
+}
+
+type firedTimer struct {
+	c chan time.Time
+}
+
+func (t firedTimer) C() <-chan time.Time      { return t.c }
+func (t firedTimer) Reset(time.Duration) bool { return false }
+func (t firedTimer) Stop() bool               { return false }
+
+type firedTicker struct {
+	c chan time.Time
+}
+
+func (t firedTicker) C() <-chan time.Time { return t.c }
+func (t firedTicker) Stop()               {}
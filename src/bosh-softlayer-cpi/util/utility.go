@@ -48,14 +48,23 @@ var privateRanges = []ipRange{
 		start: net.ParseIP("198.18.0.0"),
 		end:   net.ParseIP("198.19.255.255"),
 	},
+	// IPv6 unique local addresses, fc00::/7
+	ipRange{
+		start: net.ParseIP("fc00::"),
+		end:   net.ParseIP("fdff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"),
+	},
 }
 
 func IsPrivateSubnet(ipAddress net.IP) bool {
-	if ipCheck := ipAddress.To4(); ipCheck != nil {
-		for _, r := range privateRanges {
-			if inRange(r, ipAddress) {
-				return true
-			}
+	if ipAddress == nil {
+		return false
+	}
+
+	// Compare IPv4 addresses in their 16 bytes form, like the ranges
+	ipAddress = ipAddress.To16()
+	for _, r := range privateRanges {
+		if inRange(r, ipAddress) {
+			return true
 		}
 	}
 	return false
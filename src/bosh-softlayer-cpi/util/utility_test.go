@@ -1,6 +1,8 @@
 package util_test
 
 import (
+	"net"
+
 	. "bosh-softlayer-cpi/util"
 
 	. "github.com/onsi/ginkgo"
@@ -28,4 +30,17 @@ var _ = Describe("Utility", func() {
 		})
 	})
 
+	Context("#IsPrivateSubnet", func() {
+		It("returns true for private IPv4 and unique local IPv6 addresses", func() {
+			Expect(IsPrivateSubnet(net.ParseIP("10.10.10.10"))).To(BeTrue())
+			Expect(IsPrivateSubnet(net.ParseIP("fd12:3456:789a::1"))).To(BeTrue())
+		})
+
+		It("returns false for public addresses", func() {
+			Expect(IsPrivateSubnet(net.ParseIP("169.50.10.10"))).To(BeFalse())
+			Expect(IsPrivateSubnet(net.ParseIP("2607:f0d0:1:2::10"))).To(BeFalse())
+			Expect(IsPrivateSubnet(nil)).To(BeFalse())
+		})
+	})
+
 })